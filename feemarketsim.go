@@ -0,0 +1,129 @@
+// Package feemarketsim runs fee market experiments from Go programs. It is the stable entry point
+// for tools embedding the simulator: Run executes an experiment the way the run-experiment command
+// does, without writing to stdout or stderr; WithLogger opts into diagnostics.
+//
+//	results, err := feemarketsim.Run(ctx, feemarketsim.Experiment{
+//		Name:      "nightly",
+//		Scenarios: feemarketsim.Workloads{BuiltIn: []string{"all"}},
+//		Variants:  []string{"eip1559", "fast:aimd:aimd-gamma=0.1"},
+//		Seeds:     []int64{1, 2, 3},
+//	}, feemarketsim.WithWorkers(4))
+//
+// Results.Summaries aggregates every metric across seeds, and Save writes the results directory
+// of the run-experiment command.
+package feemarketsim
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/experiment"
+)
+
+// Experiment declares the workloads, adjuster variants, parameter grid, seeds and metrics of a run
+type Experiment = experiment.Experiment
+
+// Workloads lists the built-in scenarios, scenario files and datasets of an experiment
+type Workloads = experiment.Workloads
+
+// Results holds the outcome of every run of an experiment
+type Results = experiment.Results
+
+// CaseResult holds every run's results on one workload with one seed
+type CaseResult = experiment.CaseResult
+
+// Summary aggregates one run's metrics on one workload across seeds
+type Summary = experiment.Summary
+
+// Config holds every simulation parameter an experiment does not vary
+type Config = config.Config
+
+// ProgressFunc is called after each workload and seed combination completes
+type ProgressFunc func(completed, total int)
+
+// Option configures Run
+type Option func(*options)
+
+type options struct {
+	config   Config
+	workers  int
+	progress ProgressFunc
+	logger   *slog.Logger
+}
+
+// WithConfig sets the base configuration every run starts from (default DefaultConfig)
+func WithConfig(cfg Config) Option {
+	return func(o *options) {
+		o.config = cfg
+	}
+}
+
+// WithWorkers sets the number of workload and seed combinations run in parallel (default one per CPU)
+func WithWorkers(workers int) Option {
+	return func(o *options) {
+		o.workers = workers
+	}
+}
+
+// WithProgress sets a function called after each workload and seed combination completes. Calls
+// are serialized, but come from the worker goroutines.
+func WithProgress(progress ProgressFunc) Option {
+	return func(o *options) {
+		o.progress = progress
+	}
+}
+
+// WithLogger sets the logger the run reports diagnostics to, e.g. a
+// slog.New(slog.NewTextHandler(os.Stderr, nil)). Without it, or with nil, they are discarded. Each
+// call to Run or Save uses only its own logger, so concurrent runs can log to different places.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// DefaultConfig returns the configuration the simulator uses without flags
+func DefaultConfig() Config {
+	return config.Default()
+}
+
+// LoadExperiment reads an experiment from a JSON or TOML file, as run by the run-experiment command
+func LoadExperiment(filename string) (*Experiment, error) {
+	return experiment.LoadFromFile(filename)
+}
+
+// Run executes every combination of the experiment's workloads, variants, parameter points and
+// seeds. An unnamed experiment is named "experiment". Cancelling ctx stops the run before the next
+// workload and seed combination starts, and Run returns the context's error.
+func Run(ctx context.Context, e Experiment, opts ...Option) (*Results, error) {
+	if e.Name == "" {
+		e.Name = "experiment"
+	}
+	o := options{config: DefaultConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := config.Validate(o.config); err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	runner, err := experiment.NewRunner(o.config, e, o.workers)
+	if err != nil {
+		return nil, fmt.Errorf("experiment %s: %w", e.Name, err)
+	}
+	runner.SetLogger(o.logger)
+	return runner.Run(ctx, o.progress)
+}
+
+// Save writes the results directory of the run-experiment command: runs.csv, summary.csv,
+// summary.md, a per-block trace of every run and a chart per workload. It returns the data files
+// written. Of the options, only WithLogger applies: saved charts are reported to its logger.
+func Save(dir string, results *Results, useLogScale bool, opts ...Option) ([]string, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return experiment.SaveDir(dir, results, useLogScale, o.logger)
+}
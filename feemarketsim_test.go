@@ -0,0 +1,116 @@
+package feemarketsim
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var testExperiment = Experiment{
+	Name:      "library",
+	Scenarios: Workloads{BuiltIn: []string{"stable", "full"}},
+	Variants:  []string{"eip1559", "fast:aimd:aimd-gamma=0.1", "pid"},
+	Seeds:     []int64{1, 2},
+	Metrics:   []string{"base_fee_volatility"},
+}
+
+func TestRun(t *testing.T) {
+	// Nothing may be written to stdout or stderr
+	stdout, stderr := os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = w, w
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	cfg := DefaultConfig()
+	cfg.Simulation.Randomizer.GaussianNoise = 0.1
+	var calls []int
+	results, err := Run(context.Background(), testExperiment,
+		WithConfig(cfg), WithWorkers(2), WithProgress(func(completed, total int) {
+			calls = append(calls, completed)
+			if total != 4 {
+				t.Errorf("expected 4 combinations, got %d", total)
+			}
+		}))
+
+	os.Stdout, os.Stderr = stdout, stderr
+	w.Close()
+	written, _ := io.ReadAll(r)
+	if len(written) > 0 {
+		t.Errorf("Run wrote to stdout or stderr: %q", written)
+	}
+
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(calls) != 4 || calls[3] != 4 {
+		t.Errorf("expected a progress call per combination, got %v", calls)
+	}
+	if len(results.Cases) != 4 || len(results.Labels) != 3 {
+		t.Fatalf("expected 4 cases of 3 runs, got %d cases of %d runs", len(results.Cases), len(results.Labels))
+	}
+	summaries := results.Summaries()
+	if len(summaries) != 6 || summaries[0].Runs != 2 {
+		t.Fatalf("expected 6 summaries over 2 seeds, got %+v", summaries)
+	}
+	if _, ok := summaries[0].Metrics["base_fee_volatility"]; !ok {
+		t.Errorf("expected base_fee_volatility in %v", summaries[0].Metrics)
+	}
+}
+
+func TestRunWithLogger(t *testing.T) {
+	// Concurrent runs each report only to their own logger
+	variants := [][]string{{"pid"}, {"eip1559"}}
+	buffers := make([]bytes.Buffer, len(variants))
+	var wg sync.WaitGroup
+	for i := range variants {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			e := testExperiment
+			e.Variants = variants[i]
+			logger := slog.New(slog.NewTextHandler(&buffers[i], &slog.HandlerOptions{Level: slog.LevelDebug}))
+			if _, err := Run(context.Background(), e, WithLogger(logger)); err != nil {
+				t.Errorf("Run failed: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if !strings.Contains(buffers[0].String(), "PID effective learning rate") {
+		t.Error("expected the PID run to log its learning rate")
+	}
+	if buffers[1].Len() > 0 {
+		t.Errorf("expected nothing logged by the EIP-1559 run, got %q", buffers[1].String())
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Run(ctx, testExperiment); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestRunInvalid(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Simulation.Randomizer.GaussianNoise = -1
+	if _, err := Run(context.Background(), testExperiment, WithConfig(cfg)); err == nil {
+		t.Error("expected an invalid configuration to fail")
+	}
+
+	e := testExperiment
+	e.Variants = []string{"unknown"}
+	if _, err := Run(context.Background(), e); err == nil {
+		t.Error("expected an unknown adjuster type to fail")
+	}
+}
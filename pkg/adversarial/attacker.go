@@ -0,0 +1,286 @@
+package adversarial
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// Strategy identifies how an attacker uses the blocks it produces
+type Strategy string
+
+const (
+	// StrategyStuff fills attacker blocks to burst capacity to push fees up
+	StrategyStuff Strategy = "stuff"
+	// StrategyDrain produces empty blocks to push fees down, then lands own transactions
+	StrategyDrain Strategy = "drain"
+)
+
+// ParseStrategy parses a string into a Strategy
+func ParseStrategy(s string) (Strategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "stuff":
+		return StrategyStuff, nil
+	case "drain":
+		return StrategyDrain, nil
+	default:
+		return "", fmt.Errorf("unknown attack strategy: %s", s)
+	}
+}
+
+// Result contains the outcome of running an attack against a single fee adjuster
+type Result struct {
+	ScenarioName string
+	AdjusterType simulator.AdjusterType
+	Strategy     Strategy
+	Share        float64
+
+	AttackerBlocks int     // Blocks produced by the attacker
+	LandingBlocks  int     // Drain: attacker blocks carrying the attacker's own transactions
+	StuffedGas     uint64  // Stuff: filler gas paid for by the attacker
+	ExcludedGas    uint64  // Honest gas left out of attacker blocks
+	BurnedCost     float64 // Base fee paid on filler gas (wei)
+	ForgoneTips    float64 // Priority fees given up by excluding honest gas (wei)
+	Savings        float64 // Drain: base fee saved on landed gas vs the honest baseline (wei)
+
+	MeanFeeDistortion  float64 // Mean relative base fee deviation from the honest baseline
+	MaxFeeDistortion   float64 // Largest absolute relative base fee deviation
+	FinalFeeDistortion float64 // Relative base fee deviation on the last block
+
+	BaselineBaseFees []uint64 // Base fee per block without the attacker
+	AttackedBaseFees []uint64 // Base fee per block with the attacker
+}
+
+// TotalCost returns the attacker's total cost in wei
+func (r Result) TotalCost() float64 {
+	return r.BurnedCost + r.ForgoneTips
+}
+
+// NetProfit returns the attacker's savings minus its cost in wei
+func (r Result) NetProfit() float64 {
+	return r.Savings - r.TotalCost()
+}
+
+// CostPerDistortion returns the attacker's cost in wei per percentage point of mean fee distortion
+func (r Result) CostPerDistortion() float64 {
+	distortion := math.Abs(r.MeanFeeDistortion) * 100
+	if distortion == 0 {
+		return 0
+	}
+	return r.TotalCost() / distortion
+}
+
+// Attacker models a block producer that controls a share of block production
+type Attacker struct {
+	config config.Config
+	attack config.AttackConfig
+}
+
+// NewAttacker creates a new attacker from configuration
+func NewAttacker(cfg config.Config) *Attacker {
+	return &Attacker{
+		config: cfg,
+		attack: cfg.Simulation.Attack,
+	}
+}
+
+// Schedule returns which blocks of the scenario the attacker produces.
+// The schedule depends only on the seed so every adjuster faces the same attack.
+func (a *Attacker) Schedule(blocks int) []bool {
+	rng := rand.New(rand.NewSource(a.config.Simulation.Randomizer.Seed))
+	schedule := make([]bool, blocks)
+	for i := range schedule {
+		schedule[i] = rng.Float64() < a.attack.Share
+	}
+	return schedule
+}
+
+// RunAll runs the attack against every available fee adjuster
+func (a *Attacker) RunAll(scenario scenarios.Scenario) ([]Result, error) {
+	factory := simulator.NewAdjusterFactory()
+	var results []Result
+	for _, adjusterType := range factory.GetAvailableTypes() {
+		result, err := a.Run(adjusterType, scenario)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Run runs the attack against the given fee adjuster and compares it with an honest baseline
+func (a *Attacker) Run(adjusterType simulator.AdjusterType, scenario scenarios.Scenario) (Result, error) {
+	strategy, err := ParseStrategy(a.attack.Strategy)
+	if err != nil {
+		return Result{}, err
+	}
+
+	factory := simulator.NewAdjusterFactory()
+	baseline, err := factory.CreateAdjusterWithConfigs(adjusterType, &a.config)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create baseline adjuster: %w", err)
+	}
+	attacked, err := factory.CreateAdjusterWithConfigs(adjusterType, &a.config)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create attacked adjuster: %w", err)
+	}
+
+	result := Result{
+		ScenarioName:     scenario.Name,
+		AdjusterType:     adjusterType,
+		Strategy:         strategy,
+		Share:            a.attack.Share,
+		BaselineBaseFees: make([]uint64, 0, len(scenario.Blocks)),
+		AttackedBaseFees: make([]uint64, 0, len(scenario.Blocks)),
+	}
+
+	maxBlockSize := attacked.GetMaxBlockSize()
+	schedule := a.Schedule(len(scenario.Blocks))
+
+	var distortionSum float64
+	for i, demand := range scenario.Blocks {
+		honest := demand
+		if honest > maxBlockSize {
+			honest = maxBlockSize
+		}
+
+		// Fees for this block are set by the previous block, so both runs
+		// are priced before either processes the block
+		baselineFee := baseline.GetCurrentState().BaseFee
+		attackedFee := attacked.GetCurrentState().BaseFee
+
+		gasUsed := honest
+		if schedule[i] {
+			result.AttackerBlocks++
+			gasUsed = a.produceBlock(strategy, honest, maxBlockSize, baselineFee, attackedFee, &result)
+		}
+
+		baseline.ProcessBlock(honest)
+		attacked.ProcessBlock(gasUsed)
+
+		baselineFee = baseline.GetCurrentState().BaseFee
+		attackedFee = attacked.GetCurrentState().BaseFee
+		result.BaselineBaseFees = append(result.BaselineBaseFees, baselineFee)
+		result.AttackedBaseFees = append(result.AttackedBaseFees, attackedFee)
+
+		distortion := relativeDeviation(attackedFee, baselineFee)
+		distortionSum += distortion
+		if math.Abs(distortion) > result.MaxFeeDistortion {
+			result.MaxFeeDistortion = math.Abs(distortion)
+		}
+		result.FinalFeeDistortion = distortion
+	}
+
+	if len(scenario.Blocks) > 0 {
+		result.MeanFeeDistortion = distortionSum / float64(len(scenario.Blocks))
+	}
+
+	return result, nil
+}
+
+// produceBlock returns the gas used by an attacker-produced block and accounts for its cost
+func (a *Attacker) produceBlock(strategy Strategy, honest, maxBlockSize, baselineFee, attackedFee uint64, result *Result) uint64 {
+	switch strategy {
+	case StrategyStuff:
+		// Include all honest demand and pay the base fee on filler up to capacity
+		filler := maxBlockSize - honest
+		result.StuffedGas += filler
+		result.BurnedCost += float64(filler) * float64(attackedFee)
+		return maxBlockSize
+
+	case StrategyDrain:
+		// Exclude honest demand; every Nth attacker block lands the attacker's own transactions
+		result.ExcludedGas += honest
+		result.ForgoneTips += float64(honest) * float64(a.attack.PriorityFee)
+		if result.AttackerBlocks%a.attack.LandEvery != 0 {
+			return 0
+		}
+
+		landed := a.attack.LandingGas
+		if landed > maxBlockSize {
+			landed = maxBlockSize
+		}
+		result.LandingBlocks++
+		result.BurnedCost += float64(landed) * float64(attackedFee)
+		result.Savings += float64(landed) * (float64(baselineFee) - float64(attackedFee))
+		return landed
+	}
+
+	return honest
+}
+
+// relativeDeviation returns (value - reference) / reference, or 0 when the reference is zero
+func relativeDeviation(value, reference uint64) float64 {
+	if reference == 0 {
+		return 0
+	}
+	return (float64(value) - float64(reference)) / float64(reference)
+}
+
+// PrintResults prints a comparison of attack outcomes across fee adjusters
+func PrintResults(w io.Writer, results []Result) {
+	if len(results) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "ADVERSARIAL ANALYSIS: %s (strategy=%s, share=%.1f%%)\n",
+		results[0].ScenarioName, results[0].Strategy, results[0].Share*100)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Adjuster\tAttacker Blocks\tCost (ETH)\tSavings (ETH)\tNet (ETH)\tMean Distortion\tMax Distortion\tFinal Distortion\tCost/pp (ETH)")
+	for _, r := range results {
+		fmt.Fprintf(tw, "%s\t%d\t%.6f\t%.6f\t%.6f\t%+.2f%%\t%.2f%%\t%+.2f%%\t%.6f\n",
+			r.AdjusterType,
+			r.AttackerBlocks,
+			r.TotalCost()/1e18,
+			r.Savings/1e18,
+			r.NetProfit()/1e18,
+			r.MeanFeeDistortion*100,
+			r.MaxFeeDistortion*100,
+			r.FinalFeeDistortion*100,
+			r.CostPerDistortion()/1e18,
+		)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nCost breakdown:\n")
+	for _, r := range results {
+		fmt.Fprintf(w, "  %s: burned %.6f ETH on %.1f M filler gas, forgone tips %.6f ETH on %.1f M excluded gas",
+			r.AdjusterType, r.BurnedCost/1e18, float64(r.StuffedGas)/1e6, r.ForgoneTips/1e18, float64(r.ExcludedGas)/1e6)
+		if r.Strategy == StrategyDrain {
+			fmt.Fprintf(w, ", %d landing blocks", r.LandingBlocks)
+		}
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintf(w, "  (Cost/pp is the attacker's cost per percentage point of mean fee distortion; lower is easier to manipulate)\n")
+}
+
+// NewResultsTable returns an empty table of attack results, filled by AppendResults
+func NewResultsTable() *output.Table {
+	return output.NewTable("attacks", "Adversarial Analysis", "scenario", "adjuster_type", "strategy", "share",
+		"attacker_blocks", "landing_blocks", "stuffed_gas", "excluded_gas", "burned_cost_eth", "forgone_tips_eth",
+		"total_cost_eth", "savings_eth", "net_profit_eth", "mean_distortion_percent", "max_distortion_percent",
+		"final_distortion_percent", "cost_per_distortion_point_eth")
+}
+
+// AppendResults adds one row per adjuster of a scenario's attack results. Costs are in ETH
+// and distortions in percent of the honest fee, as printed by PrintResults.
+func AppendResults(table *output.Table, results []Result) {
+	for _, r := range results {
+		table.Append(r.ScenarioName, string(r.AdjusterType), string(r.Strategy), r.Share, r.AttackerBlocks, r.LandingBlocks,
+			r.StuffedGas, r.ExcludedGas, r.BurnedCost/1e18, r.ForgoneTips/1e18, r.TotalCost()/1e18,
+			r.Savings/1e18, r.NetProfit()/1e18, r.MeanFeeDistortion*100, r.MaxFeeDistortion*100,
+			r.FinalFeeDistortion*100, r.CostPerDistortion()/1e18)
+	}
+}
@@ -0,0 +1,78 @@
+package adversarial
+
+import (
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+func testScenario(cfg config.Config, blocks int) scenarios.Scenario {
+	gas := make([]uint64, blocks)
+	for i := range gas {
+		gas[i] = cfg.TargetBlockSize
+	}
+	return scenarios.Scenario{Name: "Steady", Blocks: gas}
+}
+
+func TestAttackerNoShare(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.Randomizer.Seed = 1
+	cfg.Simulation.Attack.Share = 0
+
+	result, err := NewAttacker(cfg).Run(simulator.AdjusterTypeEIP1559, testScenario(cfg, 50))
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.AttackerBlocks != 0 {
+		t.Errorf("Expected no attacker blocks, got %d", result.AttackerBlocks)
+	}
+	if result.MaxFeeDistortion != 0 || result.TotalCost() != 0 {
+		t.Errorf("Expected no distortion or cost, got %f and %f", result.MaxFeeDistortion, result.TotalCost())
+	}
+}
+
+func TestAttackerStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		raises   bool
+	}{
+		{"Stuff raises fees", "stuff", true},
+		{"Drain lowers fees", "drain", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Simulation.Randomizer.Seed = 42
+			cfg.Simulation.Attack.Share = 0.3
+			cfg.Simulation.Attack.Strategy = tt.strategy
+
+			results, err := NewAttacker(cfg).RunAll(testScenario(cfg, 100))
+			if err != nil {
+				t.Fatalf("RunAll failed: %v", err)
+			}
+			if len(results) != len(simulator.NewAdjusterFactory().GetAvailableTypes()) {
+				t.Fatalf("Expected one result per adjuster type, got %d", len(results))
+			}
+
+			for _, result := range results {
+				if result.AttackerBlocks == 0 {
+					t.Fatalf("%s: expected attacker blocks", result.AdjusterType)
+				}
+				if result.TotalCost() <= 0 {
+					t.Errorf("%s: expected positive attacker cost", result.AdjusterType)
+				}
+				if tt.raises && result.MeanFeeDistortion <= 0 {
+					t.Errorf("%s: expected fees pushed up, got %f", result.AdjusterType, result.MeanFeeDistortion)
+				}
+				if !tt.raises && result.MeanFeeDistortion >= 0 {
+					t.Errorf("%s: expected fees pushed down, got %f", result.AdjusterType, result.MeanFeeDistortion)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package adversarial
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/stats"
+)
+
+// Metric identifies the undesirable behaviour the search maximizes
+type Metric string
+
+const (
+	// MetricVolatility is the standard deviation of block-to-block log base fee changes
+	MetricVolatility Metric = "volatility"
+	// MetricPeakToTrough is the ratio of the highest to the lowest base fee
+	MetricPeakToTrough Metric = "peak-to-trough"
+	// MetricMinFeeTime is the fraction of blocks spent at the fee floor
+	MetricMinFeeTime Metric = "min-fee-time"
+)
+
+// ParseMetric parses a string into a Metric
+func ParseMetric(s string) (Metric, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "volatility":
+		return MetricVolatility, nil
+	case "peak-to-trough":
+		return MetricPeakToTrough, nil
+	case "min-fee-time":
+		return MetricMinFeeTime, nil
+	default:
+		return "", fmt.Errorf("unknown search metric: %s", s)
+	}
+}
+
+// SearchResult contains the worst-case demand sequence found by a search
+type SearchResult struct {
+	AdjusterType simulator.AdjusterType
+	Metric       Metric
+	Method       string
+	Score        float64   // Metric value of the worst-case sequence
+	Evaluations  int       // Number of simulations run
+	Progress     []float64 // Best score after each iteration
+	Scenario     scenarios.Scenario
+}
+
+// Searcher searches bounded gas usage sequences for the one that maximizes a bad metric
+type Searcher struct {
+	config       config.Config
+	search       config.SearchConfig
+	adjusterType simulator.AdjusterType
+	metric       Metric
+	minGas       float64
+	maxGas       float64
+	rng          *rand.Rand
+	evaluations  int
+}
+
+// NewSearcher creates a new worst-case demand searcher
+func NewSearcher(cfg config.Config, adjusterType simulator.AdjusterType) (*Searcher, error) {
+	metric, err := ParseMetric(cfg.Simulation.Search.Metric)
+	if err != nil {
+		return nil, err
+	}
+
+	maxGas := cfg.Simulation.Search.MaxGas
+	burstCapacity := simulator.CalculateMaxBlockSize(cfg.TargetBlockSize, cfg.BurstMultiplier)
+	if maxGas == 0 || maxGas > burstCapacity {
+		maxGas = burstCapacity
+	}
+
+	return &Searcher{
+		config:       cfg,
+		search:       cfg.Simulation.Search,
+		adjusterType: adjusterType,
+		metric:       metric,
+		minGas:       float64(cfg.Simulation.Search.MinGas),
+		maxGas:       float64(maxGas),
+		rng:          rand.New(rand.NewSource(cfg.Simulation.Randomizer.Seed)),
+	}, nil
+}
+
+// Run executes the configured search method
+func (s *Searcher) Run() (SearchResult, error) {
+	var (
+		best     []float64
+		score    float64
+		progress []float64
+		err      error
+	)
+
+	switch s.search.Method {
+	case "hill":
+		best, score, progress, err = s.hillClimb()
+	case "genetic":
+		best, score, progress, err = s.genetic()
+	default:
+		return SearchResult{}, fmt.Errorf("unknown search method: %s", s.search.Method)
+	}
+	if err != nil {
+		return SearchResult{}, err
+	}
+
+	return SearchResult{
+		AdjusterType: s.adjusterType,
+		Metric:       s.metric,
+		Method:       s.search.Method,
+		Score:        score,
+		Evaluations:  s.evaluations,
+		Progress:     progress,
+		Scenario: scenarios.Scenario{
+			Name:        fmt.Sprintf("Worst-case %s for %s", s.metric, s.adjusterType),
+			Description: fmt.Sprintf("Gas usage sequence maximizing %s found by %s search (seed %d, score %.6f)", s.metric, s.search.Method, s.config.Simulation.Randomizer.Seed, score),
+			Blocks:      toGas(best),
+		},
+	}, nil
+}
+
+// hillClimb runs a (1+1) hill climber whose step size follows the 1/5th success rule
+func (s *Searcher) hillClimb() ([]float64, float64, []float64, error) {
+	current := s.randomSequence()
+	currentScore, err := s.evaluate(current)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+
+	step := s.search.Mutation
+	progress := make([]float64, 0, s.search.Iterations)
+	for i := 0; i < s.search.Iterations; i++ {
+		candidate := s.mutate(current, step)
+		candidateScore, err := s.evaluate(candidate)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+
+		switch {
+		case candidateScore > currentScore:
+			current, currentScore = candidate, candidateScore
+			step = math.Min(1.0, step*1.5)
+		case candidateScore == currentScore:
+			// Drift across plateaus such as zero time at the fee floor
+			current = candidate
+			step = math.Max(1e-3, step*math.Pow(1.5, -0.25))
+		default:
+			step = math.Max(1e-3, step*math.Pow(1.5, -0.25))
+		}
+		progress = append(progress, currentScore)
+	}
+
+	return current, currentScore, progress, nil
+}
+
+// individual is a member of the genetic search population
+type individual struct {
+	genes []float64
+	score float64
+}
+
+// genetic runs a genetic algorithm with tournament selection, uniform crossover and elitism
+func (s *Searcher) genetic() ([]float64, float64, []float64, error) {
+	population := make([]individual, s.search.Population)
+	for i := range population {
+		genes := s.randomSequence()
+		score, err := s.evaluate(genes)
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		population[i] = individual{genes: genes, score: score}
+	}
+
+	progress := make([]float64, 0, s.search.Iterations)
+	for generation := 0; generation < s.search.Iterations; generation++ {
+		sort.Slice(population, func(i, j int) bool { return population[i].score > population[j].score })
+
+		next := make([]individual, 0, len(population))
+		// Keep the best two unchanged
+		next = append(next, population[0], population[1])
+		for len(next) < len(population) {
+			child := s.crossover(s.tournament(population), s.tournament(population))
+			child = s.mutate(child, s.search.Mutation)
+			score, err := s.evaluate(child)
+			if err != nil {
+				return nil, 0, nil, err
+			}
+			next = append(next, individual{genes: child, score: score})
+		}
+		population = next
+
+		best := population[0].score
+		for _, ind := range population {
+			best = math.Max(best, ind.score)
+		}
+		progress = append(progress, best)
+	}
+
+	sort.Slice(population, func(i, j int) bool { return population[i].score > population[j].score })
+	return population[0].genes, population[0].score, progress, nil
+}
+
+// tournament picks the better of two random individuals
+func (s *Searcher) tournament(population []individual) individual {
+	a := population[s.rng.Intn(len(population))]
+	b := population[s.rng.Intn(len(population))]
+	if a.score > b.score {
+		return a
+	}
+	return b
+}
+
+// crossover mixes two parents gene by gene
+func (s *Searcher) crossover(a, b individual) []float64 {
+	child := make([]float64, len(a.genes))
+	for i := range child {
+		if s.rng.Float64() < 0.5 {
+			child[i] = a.genes[i]
+		} else {
+			child[i] = b.genes[i]
+		}
+	}
+	return child
+}
+
+// randomSequence returns a uniformly random sequence within the gas bounds
+func (s *Searcher) randomSequence() []float64 {
+	sequence := make([]float64, s.search.Blocks)
+	for i := range sequence {
+		sequence[i] = s.minGas + s.rng.Float64()*(s.maxGas-s.minGas)
+	}
+	return sequence
+}
+
+// mutate perturbs a random subset of blocks by gaussian noise scaled to the gas range
+func (s *Searcher) mutate(sequence []float64, step float64) []float64 {
+	mutated := make([]float64, len(sequence))
+	copy(mutated, sequence)
+
+	gasRange := s.maxGas - s.minGas
+	rate := math.Max(1.0/float64(len(sequence)), 0.1)
+	for i := range mutated {
+		if s.rng.Float64() < rate {
+			mutated[i] = simulator.ClampFloat64(mutated[i]+s.rng.NormFloat64()*step*gasRange, s.minGas, s.maxGas)
+		}
+	}
+	return mutated
+}
+
+// evaluate simulates the sequence and returns the metric score
+func (s *Searcher) evaluate(sequence []float64) (float64, error) {
+	s.evaluations++
+	baseFees, err := simulator.SimulateBaseFees(s.adjusterType, s.config, toGas(sequence))
+	if err != nil {
+		return 0, err
+	}
+	return Score(s.metric, baseFees, s.config), nil
+}
+
+// Score computes a search metric over a base fee series
+func Score(metric Metric, baseFees []uint64, cfg config.Config) float64 {
+	if len(baseFees) == 0 {
+		return 0
+	}
+
+	switch metric {
+	case MetricVolatility:
+		// Log changes keep the metric comparable across fee levels
+		changes := make([]float64, 0, len(baseFees))
+		previous := float64(cfg.InitialBaseFee)
+		for _, fee := range baseFees {
+			changes = append(changes, math.Log(math.Max(float64(fee), 1)/math.Max(previous, 1)))
+			previous = float64(fee)
+		}
+		return stats.StdDev(changes)
+
+	case MetricPeakToTrough:
+		peak, trough := baseFees[0], baseFees[0]
+		for _, fee := range baseFees[1:] {
+			if fee > peak {
+				peak = fee
+			}
+			if fee < trough {
+				trough = fee
+			}
+		}
+		return float64(peak) / math.Max(float64(trough), 1)
+
+	case MetricMinFeeTime:
+		floor := cfg.FeeFloor()
+		atFloor := 0
+		for _, fee := range baseFees {
+			if fee <= floor {
+				atFloor++
+			}
+		}
+		return float64(atFloor) / float64(len(baseFees))
+	}
+
+	return 0
+}
+
+// toGas converts a continuous sequence to gas units
+func toGas(sequence []float64) []uint64 {
+	blocks := make([]uint64, len(sequence))
+	for i, gas := range sequence {
+		blocks[i] = uint64(math.Round(gas))
+	}
+	return blocks
+}
+
+// PrintSearchResult prints a summary of a worst-case search
+func PrintSearchResult(w io.Writer, result SearchResult, cfg config.Config) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "WORST-CASE DEMAND SEARCH: %s\n", result.AdjusterType)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	fmt.Fprintf(w, "  Metric: %s\n", result.Metric)
+	fmt.Fprintf(w, "  Method: %s (%d simulations)\n", result.Method, result.Evaluations)
+	fmt.Fprintf(w, "  Worst-case score: %.6f\n", result.Score)
+	if len(result.Progress) > 0 {
+		fmt.Fprintf(w, "  Score after first iteration: %.6f\n", result.Progress[0])
+	}
+
+	var sum uint64
+	for _, gas := range result.Scenario.Blocks {
+		sum += gas
+	}
+	avgGas := float64(sum) / float64(len(result.Scenario.Blocks))
+	fmt.Fprintf(w, "  Sequence: %d blocks, average %.1f M gas (%.1f%% of target)\n",
+		len(result.Scenario.Blocks), avgGas/1e6, avgGas/float64(cfg.TargetBlockSize)*100)
+}
+
+// SearchTable returns the outcome of a search and the shape of the worst-case sequence found
+func SearchTable(result SearchResult, cfg config.Config) *output.Table {
+	table := output.NewTable("search", "Worst-Case Demand Search", "adjuster_type", "metric", "method",
+		"evaluations", "score", "first_score", "blocks", "average_gas", "average_target_percent")
+	var first interface{}
+	if len(result.Progress) > 0 {
+		first = result.Progress[0]
+	}
+	var sum uint64
+	for _, gas := range result.Scenario.Blocks {
+		sum += gas
+	}
+	avgGas := float64(sum) / float64(len(result.Scenario.Blocks))
+	table.Append(string(result.AdjusterType), string(result.Metric), result.Method, result.Evaluations, result.Score, first,
+		len(result.Scenario.Blocks), avgGas, avgGas/float64(cfg.TargetBlockSize)*100)
+	return table
+}
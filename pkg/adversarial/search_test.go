@@ -0,0 +1,80 @@
+package adversarial
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+func TestScore(t *testing.T) {
+	cfg := config.Default()
+	cfg.InitialBaseFee = 100
+
+	flat := []uint64{100, 100, 100, 100}
+	if got := Score(MetricVolatility, flat, cfg); got != 0 {
+		t.Errorf("Expected zero volatility for flat fees, got %f", got)
+	}
+	if got := Score(MetricPeakToTrough, []uint64{50, 200, 100}, cfg); got != 4 {
+		t.Errorf("Expected peak-to-trough 4, got %f", got)
+	}
+	if got := Score(MetricMinFeeTime, []uint64{1, 1, 50, 100}, cfg); got != 0.5 {
+		t.Errorf("Expected half the blocks at the fee floor, got %f", got)
+	}
+}
+
+func TestSearchImprovesScore(t *testing.T) {
+	for _, method := range []string{"hill", "genetic"} {
+		t.Run(method, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Simulation.Randomizer.Seed = 7
+			cfg.Simulation.Search.Method = method
+			cfg.Simulation.Search.Iterations = 30
+			cfg.Simulation.Search.Population = 8
+			cfg.Simulation.Search.Blocks = 40
+
+			searcher, err := NewSearcher(cfg, simulator.AdjusterTypeEIP1559)
+			if err != nil {
+				t.Fatalf("NewSearcher failed: %v", err)
+			}
+			result, err := searcher.Run()
+			if err != nil {
+				t.Fatalf("Run failed: %v", err)
+			}
+
+			if len(result.Scenario.Blocks) != cfg.Simulation.Search.Blocks {
+				t.Fatalf("Expected %d blocks, got %d", cfg.Simulation.Search.Blocks, len(result.Scenario.Blocks))
+			}
+			maxGas := simulator.CalculateMaxBlockSize(cfg.TargetBlockSize, cfg.BurstMultiplier)
+			for i, gas := range result.Scenario.Blocks {
+				if gas > maxGas {
+					t.Fatalf("Block %d exceeds burst capacity: %d", i, gas)
+				}
+			}
+			if result.Score < result.Progress[0] {
+				t.Errorf("Final score %f is worse than first iteration %f", result.Score, result.Progress[0])
+			}
+
+			// The saved scenario must reproduce the reported score
+			filename := filepath.Join(t.TempDir(), "worst.json")
+			if err := scenarios.SaveToFile(result.Scenario, filename); err != nil {
+				t.Fatalf("SaveToFile failed: %v", err)
+			}
+			loaded, err := scenarios.LoadFromFile(filename)
+			if err != nil {
+				t.Fatalf("LoadFromFile failed: %v", err)
+			}
+			baseFees, err := simulator.SimulateBaseFees(simulator.AdjusterTypeEIP1559, cfg, loaded.Blocks)
+			if err != nil {
+				t.Fatalf("SimulateBaseFees failed: %v", err)
+			}
+			if got := Score(MetricVolatility, baseFees, cfg); got != result.Score {
+				t.Errorf("Replayed score %f does not match search score %f", got, result.Score)
+			}
+			os.Remove(filename)
+		})
+	}
+}
@@ -2,18 +2,21 @@ package analysis
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
 	"math"
-	"os"
 	"strings"
 	"text/tabwriter"
 
 	"github.com/brianbland/feemarketsim/pkg/config"
 	"github.com/brianbland/feemarketsim/pkg/scenarios"
 	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/stats"
 )
 
 // Result contains detailed analysis of a simulation run
 type Result struct {
+	Label                  string // Variant or algorithm of a side-by-side comparison, empty otherwise
 	ScenarioName           string
 	TotalBlocks            int
 	AvgGasUsed             float64
@@ -30,11 +33,29 @@ type Result struct {
 	LearningRateVolatility float64
 	TargetDeviation        float64
 	ResponsivenessScore    float64
+	DominantPeriod         float64 // Strongest oscillation period of the log base fee in blocks, 0 if none
+	DominantPeriodPower    float64 // Share of the log base fee's spectral power at the dominant period
+	FeeP50                 float64 // Median base fee in wei
+	FeeP90                 float64 // 90th percentile base fee in wei
+	FeeP99                 float64 // 99th percentile base fee in wei
+	MeanAbsFeeChange       float64 // Mean absolute block-to-block base fee change in %
+	LongestMinFeeRun       int     // Longest run of consecutive blocks at or below the fee floor (see config.Config.FeeFloor)
+	LongestPeakFeeRun      int     // Longest run of consecutive blocks within PeakFeeBand of the highest base fee observed
+	BurstCapacityFraction  float64 // Fraction of blocks using the full burst capacity
+	TimeWeightedFee        float64 // Base fee averaged over blocks, each block counting equally, in wei
+	TotalFeesBurned        float64 // Sum of base fee times gas used over all blocks, in wei
+	FeeGini                float64 // Gini coefficient of the base fees paid per block
 }
 
+// PeakFeeBand is how far below the highest base fee of a run a block's fee may be and still count
+// towards LongestPeakFeeRun, as a fraction of that fee. Fees that move continuously rarely repeat the
+// exact maximum, so the run is measured over a band rather than at the peak alone.
+const PeakFeeBand = 0.05
+
 // Analyzer handles analysis operations
 type Analyzer struct {
 	config config.Config
+	logger *slog.Logger
 }
 
 // NewAnalyzer creates a new analyzer
@@ -42,13 +63,27 @@ func NewAnalyzer(cfg config.Config) *Analyzer {
 	return &Analyzer{config: cfg}
 }
 
+// SetLogger sets the logger the simulated adjuster reports its internals to; nil discards
+func (a *Analyzer) SetLogger(logger *slog.Logger) {
+	a.logger = logger
+}
+
 // RunDetailedAnalysis runs a simulation and provides comprehensive analysis
 func (a *Analyzer) RunDetailedAnalysis(scenario scenarios.Scenario) Result {
+	result, _ := a.RunDetailedAnalysisWithBaseFees(scenario)
+	return result
+}
+
+// RunDetailedAnalysisWithBaseFees runs a simulation like RunDetailedAnalysis and also returns the
+// base fee after each block, so callers charting the fees need not simulate the scenario again
+func (a *Analyzer) RunDetailedAnalysisWithBaseFees(scenario scenarios.Scenario) (Result, []uint64) {
 	adjusterType, err := simulator.ParseAdjusterType(a.config.Simulation.AdjusterType)
 	if err != nil {
 		panic(err)
 	}
-	adjuster, err := simulator.NewAdjusterFactory().CreateAdjusterWithConfigs(adjusterType, &a.config)
+	factory := simulator.NewAdjusterFactory()
+	factory.SetLogger(a.logger)
+	adjuster, err := factory.CreateAdjusterWithConfigs(adjusterType, &a.config)
 	if err != nil {
 		panic(err)
 	}
@@ -59,9 +94,17 @@ func (a *Analyzer) RunDetailedAnalysis(scenario scenarios.Scenario) Result {
 		burstUtilizations []float64
 		gasUsages         []uint64
 		targetDeviations  []float64
+		feesPaid          []float64
+		burstBlocks       int
 	)
 
 	for _, gasUsed := range scenario.Blocks {
+		// Each block pays the base fee it was built under, before the adjuster reacts to it
+		feesPaid = append(feesPaid, float64(adjuster.GetCurrentState().BaseFee)*float64(gasUsed))
+		if gasUsed >= adjuster.GetMaxBlockSize() {
+			burstBlocks++
+		}
+
 		adjuster.ProcessBlock(gasUsed)
 		state := adjuster.GetCurrentState()
 
@@ -98,7 +141,21 @@ func (a *Analyzer) RunDetailedAnalysis(scenario scenarios.Scenario) Result {
 	// Calculate responsiveness score
 	responsivenessScore := a.calculateResponsiveness(gasUsages, baseFees)
 
-	return Result{
+	// Find the strongest fee oscillation; log fees make it independent of the fee level
+	var dominant Period
+	if periods := DominantPeriods(logFees(baseFees), 1); len(periods) > 0 {
+		dominant = periods[0]
+	}
+
+	fees := convertToFloat64(baseFees)
+	floor, nearPeak := a.config.FeeFloor(), float64(maxUint64(baseFees))*(1-PeakFeeBand)
+	var totalBurned float64
+	for _, paid := range feesPaid {
+		totalBurned += paid
+	}
+
+	result := Result{
+		Label:                  a.config.Simulation.Label,
 		ScenarioName:           scenario.Name,
 		TotalBlocks:            len(scenario.Blocks),
 		AvgGasUsed:             avgGasUsed,
@@ -115,7 +172,20 @@ func (a *Analyzer) RunDetailedAnalysis(scenario scenarios.Scenario) Result {
 		LearningRateVolatility: learningRateVolatility,
 		TargetDeviation:        avgTargetDeviation,
 		ResponsivenessScore:    responsivenessScore,
+		DominantPeriod:         dominant.Blocks,
+		DominantPeriodPower:    dominant.Power,
+		FeeP50:                 stats.Percentile(fees, 50),
+		FeeP90:                 stats.Percentile(fees, 90),
+		FeeP99:                 stats.Percentile(fees, 99),
+		MeanAbsFeeChange:       meanAbsChange(baseFees),
+		LongestMinFeeRun:       longestRun(baseFees, func(fee uint64) bool { return fee <= floor }),
+		LongestPeakFeeRun:      longestRun(baseFees, func(fee uint64) bool { return float64(fee) >= nearPeak }),
+		BurstCapacityFraction:  float64(burstBlocks) / float64(len(scenario.Blocks)),
+		TimeWeightedFee:        averageUint64(baseFees),
+		TotalFeesBurned:        totalBurned,
+		FeeGini:                stats.Gini(feesPaid),
 	}
+	return result, baseFees
 }
 
 // calculateResponsiveness measures how well fees respond to demand changes
@@ -157,61 +227,82 @@ func (a *Analyzer) calculateResponsiveness(gasUsages []uint64, baseFees []uint64
 }
 
 // PrintResults prints formatted analysis results
-func PrintResults(results []Result) {
-	fmt.Printf("\n" + strings.Repeat("=", 80) + "\n")
-	fmt.Printf("COMPREHENSIVE ANALYSIS SUMMARY\n")
-	fmt.Printf(strings.Repeat("=", 80) + "\n")
+func PrintResults(w io.Writer, results []Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "COMPREHENSIVE ANALYSIS SUMMARY\n")
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "Scenario\tAvg Gas %\tFinal Fee\tFee Range\tFee Volatility\tResponsiveness")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Scenario\tAvg Gas %\tFinal Fee\tFee Range\tP99/P50\tAvg |Change|\tFee Volatility\tResponsiveness")
 
 	for _, result := range results {
 		feeChange := float64(result.FinalBaseFee) / float64(result.InitialBaseFee)
 		feeRangeStr := fmt.Sprintf("%.2fx", float64(result.MaxBaseFee)/float64(result.MinBaseFee))
-		volatilityStr := fmt.Sprintf("%.0f", result.BaseFeeVolatility/1e9) // Convert to Gwei
+		volatilityStr := config.FormatGwei(result.BaseFeeVolatility)
 		responsivenessStr := fmt.Sprintf("%.3f", result.ResponsivenessScore)
+		tailStr := "-"
+		if result.FeeP50 > 0 {
+			tailStr = fmt.Sprintf("%.2fx", result.FeeP99/result.FeeP50)
+		}
 
-		fmt.Fprintf(w, "%s\t%.1f%%\t%.2fx\t%s\t%s Gwei\t%s\n",
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%.2fx\t%s\t%s\t%.2f%%\t%s\t%s\n",
 			result.ScenarioName,
 			result.AvgGasUsedPercent,
 			feeChange,
 			feeRangeStr,
+			tailStr,
+			result.MeanAbsFeeChange,
 			volatilityStr,
 			responsivenessStr,
 		)
 	}
-	w.Flush()
+	tw.Flush()
 
 	// Detailed breakdown for each scenario
 	for _, result := range results {
-		fmt.Printf("\n" + strings.Repeat("-", 60) + "\n")
-		fmt.Printf("DETAILED ANALYSIS: %s\n", result.ScenarioName)
-		fmt.Printf(strings.Repeat("-", 60) + "\n")
-
-		fmt.Printf("Block Statistics:\n")
-		fmt.Printf("  Total Blocks: %d\n", result.TotalBlocks)
-		fmt.Printf("  Average Gas Used: %.0f (%.1f%% of burst capacity)\n", result.AvgGasUsed, result.AvgGasUsedPercent)
-		fmt.Printf("  Average Burst Utilization: %.3f\n", result.AvgBlockConsumption)
-		fmt.Printf("  Average Deviation from Target: %.1f%%\n", result.TargetDeviation*100)
-
-		fmt.Printf("\nBase Fee Evolution:\n")
-		fmt.Printf("  Initial: %.3f Gwei\n", float64(result.InitialBaseFee)/1e9)
-		fmt.Printf("  Final: %.3f Gwei (%.2fx change)\n",
-			float64(result.FinalBaseFee)/1e9,
+		fmt.Fprintf(w, "\n"+strings.Repeat("-", 60)+"\n")
+		fmt.Fprintf(w, "DETAILED ANALYSIS: %s\n", result.ScenarioName)
+		fmt.Fprintf(w, strings.Repeat("-", 60)+"\n")
+
+		fmt.Fprintf(w, "Block Statistics:\n")
+		fmt.Fprintf(w, "  Total Blocks: %d\n", result.TotalBlocks)
+		fmt.Fprintf(w, "  Average Gas Used: %.0f (%.1f%% of burst capacity)\n", result.AvgGasUsed, result.AvgGasUsedPercent)
+		fmt.Fprintf(w, "  Average Burst Utilization: %.3f\n", result.AvgBlockConsumption)
+		fmt.Fprintf(w, "  Average Deviation from Target: %.1f%%\n", result.TargetDeviation*100)
+
+		fmt.Fprintf(w, "\nBase Fee Evolution:\n")
+		fmt.Fprintf(w, "  Initial: %s\n", config.FormatGwei(float64(result.InitialBaseFee)))
+		fmt.Fprintf(w, "  Final: %s (%.2fx change)\n",
+			config.FormatGwei(float64(result.FinalBaseFee)),
 			float64(result.FinalBaseFee)/float64(result.InitialBaseFee))
-		fmt.Printf("  Range: %.3f - %.3f Gwei\n",
-			float64(result.MinBaseFee)/1e9,
-			float64(result.MaxBaseFee)/1e9)
-		fmt.Printf("  Volatility: %.3f Gwei (std dev)\n", result.BaseFeeVolatility/1e9)
-
-		fmt.Printf("\nLearning Rate Dynamics:\n")
-		fmt.Printf("  Average: %.6f\n", result.AvgLearningRate)
-		fmt.Printf("  Range: %.6f - %.6f\n", result.MinLearningRate, result.MaxLearningRate)
-		fmt.Printf("  Volatility: %.6f (std dev)\n", result.LearningRateVolatility)
-
-		fmt.Printf("\nMechanism Performance:\n")
-		fmt.Printf("  Responsiveness Score: %.3f\n", result.ResponsivenessScore)
-		fmt.Printf("  (Higher is more responsive to demand changes)\n")
+		fmt.Fprintf(w, "  Range: %s - %s\n",
+			config.FormatGwei(float64(result.MinBaseFee)),
+			config.FormatGwei(float64(result.MaxBaseFee)))
+		fmt.Fprintf(w, "  Volatility: %s (std dev)\n", config.FormatGwei(result.BaseFeeVolatility))
+		fmt.Fprintf(w, "  Percentiles: p50 %s, p90 %s, p99 %s\n",
+			config.FormatGwei(result.FeeP50), config.FormatGwei(result.FeeP90), config.FormatGwei(result.FeeP99))
+		fmt.Fprintf(w, "  Time-Weighted Average: %s\n", config.FormatGwei(result.TimeWeightedFee))
+		fmt.Fprintf(w, "  Mean Block-to-Block Change: %.2f%%\n", result.MeanAbsFeeChange)
+		fmt.Fprintf(w, "  Longest Run at the Floor: %d blocks, within %.0f%% of the Peak: %d blocks\n",
+			result.LongestMinFeeRun, PeakFeeBand*100, result.LongestPeakFeeRun)
+
+		fmt.Fprintf(w, "\nFees Paid:\n")
+		fmt.Fprintf(w, "  Total Burned: %.6f ETH\n", result.TotalFeesBurned/1e18)
+		fmt.Fprintf(w, "  Gini Across Blocks: %.3f\n", result.FeeGini)
+		fmt.Fprintf(w, "  Blocks at Burst Capacity: %.1f%%\n", result.BurstCapacityFraction*100)
+
+		fmt.Fprintf(w, "\nLearning Rate Dynamics:\n")
+		fmt.Fprintf(w, "  Average: %.6f\n", result.AvgLearningRate)
+		fmt.Fprintf(w, "  Range: %.6f - %.6f\n", result.MinLearningRate, result.MaxLearningRate)
+		fmt.Fprintf(w, "  Volatility: %.6f (std dev)\n", result.LearningRateVolatility)
+
+		fmt.Fprintf(w, "\nMechanism Performance:\n")
+		fmt.Fprintf(w, "  Responsiveness Score: %.3f\n", result.ResponsivenessScore)
+		fmt.Fprintf(w, "  (Higher is more responsive to demand changes)\n")
+		if result.DominantPeriod > 0 {
+			fmt.Fprintf(w, "  Dominant Fee Oscillation: %.1f blocks (%.0f%% of spectral power)\n",
+				result.DominantPeriod, result.DominantPeriodPower*100)
+		}
 	}
 }
 
@@ -254,6 +345,49 @@ func stdDev(values []float64) float64 {
 	return math.Sqrt(sumSquares / float64(len(values)-1))
 }
 
+// meanAbsChange returns the mean absolute block-to-block change of the fees in percent,
+// skipping changes from a zero fee
+func meanAbsChange(fees []uint64) float64 {
+	var sum float64
+	var count int
+	for i := 1; i < len(fees); i++ {
+		if fees[i-1] == 0 {
+			continue
+		}
+		sum += math.Abs(float64(fees[i])-float64(fees[i-1])) / float64(fees[i-1])
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count) * 100
+}
+
+// longestRun returns the longest run of consecutive fees for which match is true
+func longestRun(fees []uint64, match func(fee uint64) bool) int {
+	var longest, current int
+	for _, fee := range fees {
+		if match(fee) {
+			current++
+			if current > longest {
+				longest = current
+			}
+		} else {
+			current = 0
+		}
+	}
+	return longest
+}
+
+// logFees returns the natural log of each fee, treating a zero fee as 1 wei
+func logFees(fees []uint64) []float64 {
+	logs := make([]float64, len(fees))
+	for i, fee := range fees {
+		logs[i] = math.Log(math.Max(float64(fee), 1))
+	}
+	return logs
+}
+
 func convertToFloat64(values []uint64) []float64 {
 	result := make([]float64, len(values))
 	for i, v := range values {
@@ -0,0 +1,117 @@
+package analysis
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+func TestRunDetailedAnalysisQualityMetrics(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "eip1559"
+	full := uint64(float64(cfg.TargetBlockSize) * cfg.BurstMultiplier)
+
+	blocks := make([]uint64, 20)
+	for i := range blocks {
+		blocks[i] = full
+	}
+	result := NewAnalyzer(cfg).RunDetailedAnalysis(scenarios.Scenario{Name: "full", Blocks: blocks})
+
+	if result.BurstCapacityFraction != 1 {
+		t.Errorf("Expected every block at burst capacity, got %f", result.BurstCapacityFraction)
+	}
+	if math.Abs(result.MeanAbsFeeChange-12.5) > 0.01 {
+		t.Errorf("Expected 12.5%% change per full block, got %f", result.MeanAbsFeeChange)
+	}
+	if result.LongestMinFeeRun != 0 || result.LongestPeakFeeRun != 1 {
+		t.Errorf("Rising fees should never reach the floor and peak once, got runs %d and %d",
+			result.LongestMinFeeRun, result.LongestPeakFeeRun)
+	}
+	if !(result.FeeP50 < result.FeeP90 && result.FeeP90 < result.FeeP99) {
+		t.Errorf("Percentiles of rising fees should increase: %f, %f, %f", result.FeeP50, result.FeeP90, result.FeeP99)
+	}
+
+	// The first block pays the initial fee and the rest pay 12.5% more each
+	var burned float64
+	fee := float64(cfg.InitialBaseFee)
+	for range blocks {
+		burned += fee * float64(full)
+		fee *= 1.125
+	}
+	if math.Abs(result.TotalFeesBurned-burned)/burned > 1e-6 {
+		t.Errorf("Expected %.0f wei burned, got %.0f", burned, result.TotalFeesBurned)
+	}
+	if result.FeeGini <= 0 || result.FeeGini >= 1 {
+		t.Errorf("Expected a Gini strictly between 0 and 1 for rising fees, got %f", result.FeeGini)
+	}
+}
+
+func TestLongestRun(t *testing.T) {
+	fees := []uint64{5, 1, 1, 3, 1, 0, 1, 5}
+	if got := longestRun(fees, func(fee uint64) bool { return fee <= 1 }); got != 3 {
+		t.Errorf("Expected a run of 3 at or below the floor, got %d", got)
+	}
+	if got := longestRun(fees, func(fee uint64) bool { return fee == 7 }); got != 0 {
+		t.Errorf("Expected no run of an absent fee, got %d", got)
+	}
+}
+
+func TestRunDetailedAnalysisWithBaseFees(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "aimd"
+	scenario, _ := scenarios.NewGenerator(cfg.Simulation).GetByName("mixed", cfg)
+
+	result, baseFees := NewAnalyzer(cfg).RunDetailedAnalysisWithBaseFees(scenario)
+	want, err := simulator.SimulateBaseFees(simulator.AdjusterTypeAIMD, cfg, scenario.Blocks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(baseFees, want) {
+		t.Errorf("Expected the fees of a fresh simulation, got %v, want %v", baseFees, want)
+	}
+	if result.FinalBaseFee != want[len(want)-1] {
+		t.Errorf("Expected final fee %d, got %d", want[len(want)-1], result.FinalBaseFee)
+	}
+}
+
+func TestLongestMinFeeRunAtFloor(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "eip1559"
+	cfg.MinBaseFee = cfg.InitialBaseFee / 2
+
+	// Empty blocks lower the fee 12.5% per block until it is clamped at the minimum base fee
+	result := NewAnalyzer(cfg).RunDetailedAnalysis(scenarios.Scenario{Name: "empty", Blocks: make([]uint64, 20)})
+	if result.MinBaseFee != cfg.MinBaseFee {
+		t.Fatalf("Expected the fee to reach the minimum %d, got %d", cfg.MinBaseFee, result.MinBaseFee)
+	}
+	// 0.875^6 < 0.5 < 0.875^5, so the fee is at the floor from the 6th block on
+	if result.LongestMinFeeRun != 15 {
+		t.Errorf("Expected 15 blocks at the floor, got %d", result.LongestMinFeeRun)
+	}
+}
+
+func TestLongestPeakFeeRunNearPeak(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "eip1559"
+	full := uint64(float64(cfg.TargetBlockSize) * cfg.BurstMultiplier)
+
+	// Full blocks raise the fee to its peak, blocks just under target lower it 0.25% per block,
+	// and empty blocks then drop it out of the band
+	var blocks []uint64
+	for i := 0; i < 10; i++ {
+		blocks = append(blocks, full)
+	}
+	for i := 0; i < 12; i++ {
+		blocks = append(blocks, cfg.TargetBlockSize*98/100)
+	}
+	blocks = append(blocks, make([]uint64, 10)...)
+
+	result := NewAnalyzer(cfg).RunDetailedAnalysis(scenarios.Scenario{Name: "plateau", Blocks: blocks})
+	if result.LongestPeakFeeRun != 13 {
+		t.Errorf("Expected the peak and 12 blocks just below it, got %d", result.LongestPeakFeeRun)
+	}
+}
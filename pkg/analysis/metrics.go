@@ -0,0 +1,98 @@
+package analysis
+
+import "github.com/brianbland/feemarketsim/pkg/output"
+
+// MetricNames lists the numeric Result metrics in display order
+var MetricNames = []string{
+	"total_blocks",
+	"avg_gas_used",
+	"avg_gas_used_percent",
+	"avg_block_consumption",
+	"initial_base_fee",
+	"final_base_fee",
+	"min_base_fee",
+	"max_base_fee",
+	"base_fee_volatility",
+	"avg_learning_rate",
+	"min_learning_rate",
+	"max_learning_rate",
+	"learning_rate_volatility",
+	"target_deviation",
+	"responsiveness_score",
+	"dominant_period",
+	"dominant_period_power",
+	"fee_p50",
+	"fee_p90",
+	"fee_p99",
+	"mean_abs_fee_change",
+	"longest_min_fee_run",
+	"longest_peak_fee_run",
+	"burst_capacity_fraction",
+	"time_weighted_fee",
+	"total_fees_burned",
+	"fee_gini",
+}
+
+// Metrics returns every numeric metric of the result keyed by name.
+// Fee metrics are in wei, matching the Result fields.
+func (r Result) Metrics() map[string]float64 {
+	return map[string]float64{
+		"total_blocks":             float64(r.TotalBlocks),
+		"avg_gas_used":             r.AvgGasUsed,
+		"avg_gas_used_percent":     r.AvgGasUsedPercent,
+		"avg_block_consumption":    r.AvgBlockConsumption,
+		"initial_base_fee":         float64(r.InitialBaseFee),
+		"final_base_fee":           float64(r.FinalBaseFee),
+		"min_base_fee":             float64(r.MinBaseFee),
+		"max_base_fee":             float64(r.MaxBaseFee),
+		"base_fee_volatility":      r.BaseFeeVolatility,
+		"avg_learning_rate":        r.AvgLearningRate,
+		"min_learning_rate":        r.MinLearningRate,
+		"max_learning_rate":        r.MaxLearningRate,
+		"learning_rate_volatility": r.LearningRateVolatility,
+		"target_deviation":         r.TargetDeviation,
+		"responsiveness_score":     r.ResponsivenessScore,
+		"dominant_period":          r.DominantPeriod,
+		"dominant_period_power":    r.DominantPeriodPower,
+		"fee_p50":                  r.FeeP50,
+		"fee_p90":                  r.FeeP90,
+		"fee_p99":                  r.FeeP99,
+		"mean_abs_fee_change":      r.MeanAbsFeeChange,
+		"longest_min_fee_run":      float64(r.LongestMinFeeRun),
+		"longest_peak_fee_run":     float64(r.LongestPeakFeeRun),
+		"burst_capacity_fraction":  r.BurstCapacityFraction,
+		"time_weighted_fee":        r.TimeWeightedFee,
+		"total_fees_burned":        r.TotalFeesBurned,
+		"fee_gini":                 r.FeeGini,
+	}
+}
+
+// Metric returns a single metric by name
+func (r Result) Metric(name string) (float64, bool) {
+	value, ok := r.Metrics()[name]
+	return value, ok
+}
+
+// IsMetricName reports whether name is a known Result metric
+func IsMetricName(name string) bool {
+	for _, metric := range MetricNames {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultsTable returns one row per result: the scenario name, then every metric
+func ResultsTable(results []Result) *output.Table {
+	table := output.NewTable("analysis", "Analysis Summary", append([]string{"scenario"}, MetricNames...)...)
+	for _, result := range results {
+		metrics := result.Metrics()
+		row := []interface{}{result.ScenarioName}
+		for _, name := range MetricNames {
+			row = append(row, metrics[name])
+		}
+		table.Append(row...)
+	}
+	return table
+}
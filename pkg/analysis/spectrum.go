@@ -0,0 +1,130 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// Period is a peak in the power spectrum of a series
+type Period struct {
+	Blocks float64 // Length of one oscillation, in blocks
+	Power  float64 // Share of the detrended series' spectral power at this period
+}
+
+// DominantPeriods returns up to count spectral peaks of the series, strongest first.
+// The series is detrended and Hann-windowed before the FFT, and only periods that fit at
+// least twice into the series are considered.
+func DominantPeriods(series []float64, count int) []Period {
+	if len(series) < 8 {
+		return nil
+	}
+
+	power, padded := periodogram(series)
+	var total float64
+	for _, p := range power[1:] {
+		total += p
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var peaks []Period
+	for k := 1; k < len(power); k++ {
+		period := float64(padded) / float64(k)
+		if period > float64(len(series))/2 {
+			continue
+		}
+		left := power[k-1]
+		right := 0.0
+		if k+1 < len(power) {
+			right = power[k+1]
+		}
+		if power[k] > left && power[k] >= right {
+			peaks = append(peaks, Period{Blocks: period, Power: power[k] / total})
+		}
+	}
+
+	sort.SliceStable(peaks, func(i, j int) bool {
+		return peaks[i].Power > peaks[j].Power
+	})
+	if len(peaks) > count {
+		peaks = peaks[:count]
+	}
+	return peaks
+}
+
+// periodogram returns the power at frequencies 0 through n/2 cycles per n blocks, where n is
+// the series length padded to a power of two
+func periodogram(series []float64) ([]float64, int) {
+	n := 1
+	for n < len(series) {
+		n *= 2
+	}
+
+	detrended := detrend(series)
+	data := make([]complex128, n)
+	for i, value := range detrended {
+		window := 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(len(series)-1))
+		data[i] = complex(value*window, 0)
+	}
+	fft(data)
+
+	power := make([]float64, n/2+1)
+	for k := range power {
+		magnitude := cmplx.Abs(data[k])
+		power[k] = magnitude * magnitude
+	}
+	return power, n
+}
+
+// detrend removes the least-squares line from the series
+func detrend(series []float64) []float64 {
+	n := float64(len(series))
+	var sumX, sumY, sumXY, sumXX float64
+	for i, y := range series {
+		x := float64(i)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+	slope := (n*sumXY - sumX*sumY) / (n*sumXX - sumX*sumX)
+	intercept := (sumY - slope*sumX) / n
+
+	detrended := make([]float64, len(series))
+	for i, y := range series {
+		detrended[i] = y - (intercept + slope*float64(i))
+	}
+	return detrended
+}
+
+// fft computes the discrete Fourier transform in place; len(data) must be a power of two
+func fft(data []complex128) {
+	n := len(data)
+
+	// Bit-reversal permutation
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		step := cmplx.Exp(complex(0, -2*math.Pi/float64(size)))
+		for start := 0; start < n; start += size {
+			w := complex(1, 0)
+			for k := 0; k < size/2; k++ {
+				even, odd := data[start+k], data[start+k+size/2]*w
+				data[start+k] = even + odd
+				data[start+k+size/2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
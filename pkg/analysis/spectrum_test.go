@@ -0,0 +1,51 @@
+package analysis
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFTMatchesDFT(t *testing.T) {
+	data := []complex128{1, 2, 0, -1, 3, 0.5, -2, 1}
+	expected := make([]complex128, len(data))
+	for k := range expected {
+		for n, x := range data {
+			expected[k] += x * cmplx.Exp(complex(0, -2*math.Pi*float64(k*n)/float64(len(data))))
+		}
+	}
+
+	fft(data)
+	for k := range data {
+		if cmplx.Abs(data[k]-expected[k]) > 1e-9 {
+			t.Errorf("Bin %d: expected %v, got %v", k, expected[k], data[k])
+		}
+	}
+}
+
+func TestDominantPeriods(t *testing.T) {
+	// A strong 32-block cycle and a weaker 8-block cycle on top of a trend
+	series := make([]float64, 512)
+	for i := range series {
+		x := float64(i)
+		series[i] = 0.01*x + math.Sin(2*math.Pi*x/32) + 0.3*math.Sin(2*math.Pi*x/8)
+	}
+
+	periods := DominantPeriods(series, 2)
+	if len(periods) != 2 {
+		t.Fatalf("Expected 2 periods, got %v", periods)
+	}
+	if periods[0].Blocks != 32 || periods[1].Blocks != 8 {
+		t.Errorf("Expected periods 32 and 8, got %v", periods)
+	}
+	if periods[0].Power <= periods[1].Power {
+		t.Errorf("Expected the 32-block cycle to dominate, got %v", periods)
+	}
+}
+
+func TestDominantPeriodsFlatSeries(t *testing.T) {
+	series := make([]float64, 64)
+	if periods := DominantPeriods(series, 1); len(periods) != 0 {
+		t.Errorf("Expected no periods for a flat series, got %v", periods)
+	}
+}
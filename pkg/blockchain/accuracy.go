@@ -0,0 +1,193 @@
+package blockchain
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/stats"
+)
+
+// AccuracySegments is the number of contiguous block ranges errors are broken down into
+const AccuracySegments = 10
+
+// maxAccuracyLag is the largest shift in blocks searched for the best cross-correlation
+const maxAccuracyLag = 50
+
+// Accuracy measures how closely a simulated base fee series reproduces the actual one.
+// Both series hold the fee each block was priced at, so index i is the same block in each.
+type Accuracy struct {
+	Blocks               int               `json:"blocks"`
+	RMSE                 float64           `json:"rmse"`                 // Root mean square error in wei
+	MAPE                 float64           `json:"mape"`                 // Mean absolute percentage error
+	Bias                 float64           `json:"bias"`                 // Mean signed percentage error; positive means simulated fees are higher
+	Correlation          float64           `json:"correlation"`          // Pearson correlation of the fee series
+	Lag                  int               `json:"lag"`                  // Shift in blocks that maximizes correlation; positive means simulated fees lag
+	LagCorrelation       float64           `json:"lagCorrelation"`       // Correlation at that shift
+	DirectionalAgreement float64           `json:"directionalAgreement"` // Percentage of blocks where both fees move the same way
+	ActualMaxDrawdown    float64           `json:"actualMaxDrawdown"`    // Largest peak-to-trough fall of the actual fee, in %
+	SimulatedMaxDrawdown float64           `json:"simulatedMaxDrawdown"` // Largest peak-to-trough fall of the simulated fee, in %
+	Segments             []SegmentAccuracy `json:"segments"`
+}
+
+// SegmentAccuracy is the error over one contiguous block range
+type SegmentAccuracy struct {
+	StartBlock uint64  `json:"startBlock"`
+	EndBlock   uint64  `json:"endBlock"`
+	RMSE       float64 `json:"rmse"`
+	MAPE       float64 `json:"mape"`
+	Bias       float64 `json:"bias"`
+}
+
+// CompareFees computes accuracy metrics of simulated against actual fees, block by block.
+// The block numbers label the segments.
+func CompareFees(blockNumbers []uint64, actual, simulated []float64) Accuracy {
+	n := len(actual)
+	accuracy := Accuracy{Blocks: n}
+	if n == 0 || len(simulated) != n || len(blockNumbers) != n {
+		return accuracy
+	}
+
+	accuracy.RMSE, accuracy.MAPE, accuracy.Bias = feeErrors(actual, simulated)
+	accuracy.Correlation = stats.Correlation(actual, simulated)
+	accuracy.Lag, accuracy.LagCorrelation = bestLag(actual, simulated)
+	accuracy.DirectionalAgreement = directionalAgreement(actual, simulated)
+	accuracy.ActualMaxDrawdown = maxDrawdown(actual)
+	accuracy.SimulatedMaxDrawdown = maxDrawdown(simulated)
+
+	segments := AccuracySegments
+	if n < segments {
+		segments = n
+	}
+	for s := 0; s < segments; s++ {
+		start, end := s*n/segments, (s+1)*n/segments
+		segment := SegmentAccuracy{
+			StartBlock: blockNumbers[start],
+			EndBlock:   blockNumbers[end-1],
+		}
+		segment.RMSE, segment.MAPE, segment.Bias = feeErrors(actual[start:end], simulated[start:end])
+		accuracy.Segments = append(accuracy.Segments, segment)
+	}
+	return accuracy
+}
+
+// Metrics returns the scalar accuracy metrics keyed by name
+func (a Accuracy) Metrics() map[string]float64 {
+	return map[string]float64{
+		"fee_rmse":                  a.RMSE,
+		"fee_mape":                  a.MAPE,
+		"fee_bias":                  a.Bias,
+		"fee_correlation":           a.Correlation,
+		"fee_lag":                   float64(a.Lag),
+		"fee_directional_agreement": a.DirectionalAgreement,
+	}
+}
+
+// feeErrors returns the RMSE, the MAPE and the mean signed percentage error.
+// Blocks with a zero actual fee are left out of the percentage errors.
+func feeErrors(actual, simulated []float64) (float64, float64, float64) {
+	var squares, absPercent, signedPercent float64
+	var counted int
+	for i := range actual {
+		diff := simulated[i] - actual[i]
+		squares += diff * diff
+		if actual[i] > 0 {
+			absPercent += math.Abs(diff) / actual[i]
+			signedPercent += diff / actual[i]
+			counted++
+		}
+	}
+
+	rmse := math.Sqrt(squares / float64(len(actual)))
+	if counted == 0 {
+		return rmse, 0, 0
+	}
+	return rmse, absPercent / float64(counted) * 100, signedPercent / float64(counted) * 100
+}
+
+// bestLag returns the shift of the simulated series, within a quarter of the series and at most
+// maxAccuracyLag blocks, that correlates best with the actual series
+func bestLag(actual, simulated []float64) (int, float64) {
+	limit := len(actual) / 4
+	if limit > maxAccuracyLag {
+		limit = maxAccuracyLag
+	}
+
+	best, bestCorrelation := 0, stats.Correlation(actual, simulated)
+	for lag := -limit; lag <= limit; lag++ {
+		var r float64
+		if lag >= 0 {
+			r = stats.Correlation(actual[:len(actual)-lag], simulated[lag:])
+		} else {
+			r = stats.Correlation(actual[-lag:], simulated[:len(simulated)+lag])
+		}
+		if r > bestCorrelation {
+			best, bestCorrelation = lag, r
+		}
+	}
+	return best, bestCorrelation
+}
+
+// directionalAgreement returns the percentage of block-to-block moves in the same direction,
+// counting blocks where neither fee changes as agreement
+func directionalAgreement(actual, simulated []float64) float64 {
+	if len(actual) < 2 {
+		return 0
+	}
+
+	sign := func(x float64) int {
+		switch {
+		case x > 0:
+			return 1
+		case x < 0:
+			return -1
+		default:
+			return 0
+		}
+	}
+
+	agree := 0
+	for i := 1; i < len(actual); i++ {
+		if sign(actual[i]-actual[i-1]) == sign(simulated[i]-simulated[i-1]) {
+			agree++
+		}
+	}
+	return float64(agree) / float64(len(actual)-1) * 100
+}
+
+// maxDrawdown returns the largest fall from a running peak, as a percentage of that peak
+func maxDrawdown(fees []float64) float64 {
+	var peak, drawdown float64
+	for _, fee := range fees {
+		peak = math.Max(peak, fee)
+		if peak > 0 {
+			drawdown = math.Max(drawdown, (peak-fee)/peak*100)
+		}
+	}
+	return drawdown
+}
+
+// PrintAccuracy prints the accuracy metrics and the per-segment errors
+func PrintAccuracy(w io.Writer, accuracy Accuracy) {
+	fmt.Fprintf(w, "\nAccuracy (%d blocks):\n", accuracy.Blocks)
+	fmt.Fprintf(w, "  RMSE: %s\n", config.FormatGwei(accuracy.RMSE))
+	fmt.Fprintf(w, "  MAPE: %.2f%%\n", accuracy.MAPE)
+	fmt.Fprintf(w, "  Bias: %+.2f%%\n", accuracy.Bias)
+	fmt.Fprintf(w, "  Correlation: %.3f\n", accuracy.Correlation)
+	fmt.Fprintf(w, "  Best Lag: %+d blocks (correlation %.3f)\n", accuracy.Lag, accuracy.LagCorrelation)
+	fmt.Fprintf(w, "  Directional Agreement: %.1f%%\n", accuracy.DirectionalAgreement)
+	fmt.Fprintf(w, "  Max Drawdown: %.1f%% actual, %.1f%% simulated\n", accuracy.ActualMaxDrawdown, accuracy.SimulatedMaxDrawdown)
+
+	if len(accuracy.Segments) > 1 {
+		fmt.Fprintf(w, "\n  Per-segment error:\n")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Blocks\tRMSE\tMAPE %\tBias %")
+		for _, segment := range accuracy.Segments {
+			fmt.Fprintf(tw, "  %d-%d\t%s\t%.2f\t%+.2f\n",
+				segment.StartBlock, segment.EndBlock, config.FormatGwei(segment.RMSE), segment.MAPE, segment.Bias)
+		}
+		tw.Flush()
+	}
+}
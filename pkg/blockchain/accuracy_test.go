@@ -0,0 +1,97 @@
+package blockchain
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+)
+
+func accuracySeries(n, shift int) ([]uint64, []float64) {
+	blocks := make([]uint64, n)
+	fees := make([]float64, n)
+	for i := range fees {
+		blocks[i] = uint64(1000 + i)
+		t := float64(i - shift)
+		fees[i] = 1e9 * (3 + math.Sin(2*math.Pi*t/40) + math.Sin(2*math.Pi*t/67))
+	}
+	return blocks, fees
+}
+
+func TestCompareFees_Identical(t *testing.T) {
+	blocks, fees := accuracySeries(200, 0)
+	accuracy := CompareFees(blocks, fees, fees)
+
+	if accuracy.RMSE != 0 || accuracy.MAPE != 0 || accuracy.Bias != 0 {
+		t.Errorf("Expected zero error, got RMSE %v, MAPE %v, bias %v", accuracy.RMSE, accuracy.MAPE, accuracy.Bias)
+	}
+	if math.Abs(accuracy.Correlation-1) > 1e-9 || accuracy.Lag != 0 {
+		t.Errorf("Expected correlation 1 at lag 0, got %v at lag %d", accuracy.Correlation, accuracy.Lag)
+	}
+	if accuracy.DirectionalAgreement != 100 {
+		t.Errorf("Expected 100%% directional agreement, got %v", accuracy.DirectionalAgreement)
+	}
+	if len(accuracy.Segments) != AccuracySegments {
+		t.Fatalf("Expected %d segments, got %d", AccuracySegments, len(accuracy.Segments))
+	}
+	if accuracy.Segments[0].StartBlock != 1000 || accuracy.Segments[AccuracySegments-1].EndBlock != 1199 {
+		t.Errorf("Segments should cover blocks 1000-1199, got %d-%d",
+			accuracy.Segments[0].StartBlock, accuracy.Segments[AccuracySegments-1].EndBlock)
+	}
+}
+
+func TestCompareFees_Lagged(t *testing.T) {
+	blocks, actual := accuracySeries(200, 0)
+	_, simulated := accuracySeries(200, 5)
+	accuracy := CompareFees(blocks, actual, simulated)
+
+	if accuracy.Lag != 5 {
+		t.Errorf("Expected simulated fees to lag by 5 blocks, got %d", accuracy.Lag)
+	}
+	if accuracy.LagCorrelation <= accuracy.Correlation {
+		t.Errorf("Correlation at the best lag %v should exceed the unshifted %v", accuracy.LagCorrelation, accuracy.Correlation)
+	}
+	if accuracy.RMSE <= 0 || accuracy.MAPE <= 0 {
+		t.Errorf("Expected positive error, got RMSE %v, MAPE %v", accuracy.RMSE, accuracy.MAPE)
+	}
+}
+
+func TestCompareFees_Bias(t *testing.T) {
+	blocks, actual := accuracySeries(100, 0)
+	simulated := make([]float64, len(actual))
+	for i, fee := range actual {
+		simulated[i] = fee * 1.1
+	}
+	accuracy := CompareFees(blocks, actual, simulated)
+
+	if math.Abs(accuracy.MAPE-10) > 1e-9 || math.Abs(accuracy.Bias-10) > 1e-9 {
+		t.Errorf("Expected 10%% MAPE and bias, got %v and %v", accuracy.MAPE, accuracy.Bias)
+	}
+	if math.Abs(accuracy.ActualMaxDrawdown-accuracy.SimulatedMaxDrawdown) > 1e-9 {
+		t.Errorf("Scaling should not change drawdown: %v vs %v", accuracy.ActualMaxDrawdown, accuracy.SimulatedMaxDrawdown)
+	}
+}
+
+func TestMaxDrawdown(t *testing.T) {
+	if got := maxDrawdown([]float64{1, 4, 2, 3, 1, 5}); got != 75 {
+		t.Errorf("Expected 75%% drawdown, got %v", got)
+	}
+}
+
+func TestPrintAccuracy_SubGwei(t *testing.T) {
+	accuracy := Accuracy{Blocks: 200, RMSE: 2_500_000, Segments: []SegmentAccuracy{
+		{StartBlock: 1000, EndBlock: 1099, RMSE: 1_200_000},
+		{StartBlock: 1100, EndBlock: 1199, RMSE: 3_400_000},
+	}}
+	var buf bytes.Buffer
+	PrintAccuracy(&buf, accuracy)
+	text := buf.String()
+	for _, want := range []string{"RMSE: 0.00250 Gwei", "0.00120 Gwei", "0.00340 Gwei"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "0.0000") {
+		t.Errorf("sub-Gwei errors should not round to zero:\n%s", text)
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"math"
 	"math/rand"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/logging"
 )
 
 // RPCClient defines the interface for blockchain RPC operations
@@ -19,6 +22,7 @@ type RPCClient interface {
 	FetchBlockData(ctx context.Context, blockNumber uint64) (*BlockData, error)
 	FetchTransactionReceipt(ctx context.Context, txHash string) (*TransactionReceipt, error)
 	SetTimeout(timeout time.Duration)
+	SetLogger(logger *slog.Logger)
 }
 
 // BaseRPCClient implements RPCClient for Base blockchain
@@ -26,6 +30,7 @@ type BaseRPCClient struct {
 	url        string
 	httpClient *http.Client
 	timeout    time.Duration
+	logger     *slog.Logger
 }
 
 // RPCRequest represents a JSON-RPC request
@@ -83,6 +88,11 @@ func (c *BaseRPCClient) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
 }
 
+// SetLogger sets the logger skipped transactions are reported to; nil discards
+func (c *BaseRPCClient) SetLogger(logger *slog.Logger) {
+	c.logger = logger
+}
+
 // callRPC makes a JSON-RPC call with exponential backoff retry logic
 func (c *BaseRPCClient) callRPC(ctx context.Context, method string, params []interface{}) (interface{}, error) {
 	const maxRetries = 12
@@ -367,7 +377,7 @@ func (c *BaseRPCClient) parseTransactions(ctx context.Context, blockData map[str
 		transaction, err := c.parseTransaction(ctx, tx)
 		if err != nil {
 			// Log warning but continue - don't fail entire block for one transaction
-			fmt.Printf("Warning: failed to parse transaction in block %d: %v\n", blockNumber, err)
+			logging.OrDiscard(c.logger).Warn("Skipping unparsable transaction", "block", blockNumber, "index", i, "error", err)
 			continue
 		}
 
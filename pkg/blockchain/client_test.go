@@ -2,6 +2,7 @@ package blockchain
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
@@ -72,6 +73,11 @@ func (m *MockRPCClient) SetTimeout(timeout time.Duration) {
 	// No-op for mock
 }
 
+// SetLogger implements the RPCClient interface (no-op for mock)
+func (m *MockRPCClient) SetLogger(logger *slog.Logger) {
+	// No-op for mock
+}
+
 func TestHexToUint64(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/logging"
 )
 
 // BlockFetcher handles concurrent fetching of blockchain data
@@ -40,8 +43,9 @@ func NewBlockFetcher(client RPCClient, options FetchOptions) *BlockFetcher {
 
 // FetchRange fetches a range of blocks with concurrency and ensures no gaps
 func (f *BlockFetcher) FetchRange(ctx context.Context, progressCallback ProgressCallback) (*DataSet, error) {
-	fmt.Printf("Fetching Base blockchain data from block %d to %d (%d blocks)...\n",
-		f.options.StartBlock, f.options.EndBlock, f.options.EndBlock-f.options.StartBlock+1)
+	logger := logging.OrDiscard(f.options.Logger)
+	logger.Info("Fetching Base blockchain data", "start_block", f.options.StartBlock, "end_block", f.options.EndBlock,
+		"blocks", f.options.EndBlock-f.options.StartBlock+1)
 
 	totalBlocks := f.options.EndBlock - f.options.StartBlock + 1
 
@@ -61,7 +65,7 @@ func (f *BlockFetcher) FetchRange(ctx context.Context, progressCallback Progress
 
 	// Retry logic with multiple rounds
 	for round := 1; round <= f.options.MaxRetries && len(remainingBlocks) > 0; round++ {
-		fmt.Printf("\n=== Fetch Round %d: %d blocks remaining ===\n", round, len(remainingBlocks))
+		logger.Info("Fetch round started", "round", round, "remaining", len(remainingBlocks))
 
 		progress.Round = round
 		progress.Failed = len(remainingBlocks)
@@ -77,7 +81,7 @@ func (f *BlockFetcher) FetchRange(ctx context.Context, progressCallback Progress
 		// Process round results
 		for blockNum, result := range roundResults {
 			if result.Error != nil {
-				fmt.Printf("Round %d: Block %d failed: %v\n", round, blockNum, result.Error)
+				logger.Warn("Block fetch failed", "round", round, "block", blockNum, "error", result.Error)
 				// Keep this block in remainingBlocks for next round
 			} else {
 				// Successfully fetched block
@@ -98,10 +102,10 @@ func (f *BlockFetcher) FetchRange(ctx context.Context, progressCallback Progress
 		}
 
 		if len(remainingBlocks) == 0 {
-			fmt.Printf("✅ All blocks successfully fetched in %d rounds!\n", round)
+			logger.Info("All blocks fetched", "rounds", round)
 			break
 		} else if round < f.options.MaxRetries {
-			fmt.Printf("⚠️  %d blocks still missing, will retry in round %d\n", len(remainingBlocks), round+1)
+			logger.Warn("Blocks still missing, retrying", "missing", len(remainingBlocks), "next_round", round+1)
 			// Brief pause before next round
 			select {
 			case <-ctx.Done():
@@ -173,14 +177,14 @@ func (f *BlockFetcher) fetchRound(ctx context.Context, remainingBlocks map[uint6
 		} else if result.Error != nil {
 			// We need to track which block this error belongs to
 			// This is a limitation of our current design - we should improve this
-			fmt.Printf("Round %d: Received error without block number: %v\n", round, result.Error)
+			logging.OrDiscard(f.options.Logger).Warn("Fetch failed without a block number", "round", round, "error", result.Error)
 		}
 
 		// Progress reporting for this round
 		if completed%50 == 0 || completed == len(remainingBlocks) {
 			elapsed := time.Since(roundStartTime)
-			fmt.Printf("Round %d progress: %d/%d completed in %v\n",
-				round, completed, len(remainingBlocks), elapsed)
+			logging.OrDiscard(f.options.Logger).Info("Fetch round progress", "round", round, "completed", completed,
+				"total", len(remainingBlocks), "elapsed", elapsed)
 		}
 	}
 
@@ -212,19 +216,14 @@ func (f *BlockFetcher) worker(ctx context.Context, jobs <-chan BlockFetchJob, wg
 
 // handleMissingBlocks handles the case where some blocks couldn't be fetched
 func (f *BlockFetcher) handleMissingBlocks(remainingBlocks map[uint64]bool) error {
-	fmt.Printf("❌ Failed to fetch %d blocks after %d rounds\n", len(remainingBlocks), f.options.MaxRetries)
-
 	// List the specific missing blocks
 	var missingBlocks []uint64
 	for blockNum := range remainingBlocks {
 		missingBlocks = append(missingBlocks, blockNum)
 	}
-
-	if len(missingBlocks) <= 20 {
-		fmt.Printf("Missing blocks: %v\n", missingBlocks)
-	} else {
-		fmt.Printf("Missing blocks: %v... (and %d more)\n", missingBlocks[:20], len(missingBlocks)-20)
-	}
+	sort.Slice(missingBlocks, func(i, j int) bool { return missingBlocks[i] < missingBlocks[j] })
+	logging.OrDiscard(f.options.Logger).Error("Blocks could not be fetched", "missing", len(missingBlocks), "rounds", f.options.MaxRetries,
+		"blocks", firstBlocks(missingBlocks, 20))
 
 	return fmt.Errorf("unable to fetch complete dataset: %d blocks missing after %d retry rounds",
 		len(remainingBlocks), f.options.MaxRetries)
@@ -247,17 +246,13 @@ func (f *BlockFetcher) createDataSet(results map[uint64]*BlockFetchResult, first
 	}
 
 	totalBlocks := int(f.options.EndBlock - f.options.StartBlock + 1)
-	fmt.Printf("\n=== Final Results ===\n")
-	fmt.Printf("Successfully fetched: %d out of %d blocks (%.2f%%)\n",
-		successCount, totalBlocks, float64(successCount)/float64(totalBlocks)*100)
+	logger := logging.OrDiscard(f.options.Logger)
+	logger.Info("Fetch finished", "fetched", successCount, "total", totalBlocks,
+		"percent", float64(successCount)/float64(totalBlocks)*100)
 
 	if len(missingBlocks) > 0 {
-		fmt.Printf("❌ GAPS DETECTED: %d missing blocks\n", len(missingBlocks))
-		if len(missingBlocks) <= 10 {
-			fmt.Printf("Missing blocks: %v\n", missingBlocks)
-		} else {
-			fmt.Printf("Missing blocks: %v... (and %d more)\n", missingBlocks[:10], len(missingBlocks)-10)
-		}
+		logger.Error("Gaps detected in the fetched blocks", "missing", len(missingBlocks),
+			"blocks", firstBlocks(missingBlocks, 10))
 		return nil, fmt.Errorf("incomplete dataset: %d blocks missing, cannot proceed with gaps", len(missingBlocks))
 	}
 
@@ -289,10 +284,17 @@ func SaveDataSetToFile(dataset *DataSet, filename string) error {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 
-	fmt.Printf("✅ Complete dataset with NO GAPS saved to %s\n", filename)
 	return nil
 }
 
+// firstBlocks returns the first n block numbers as a log value, noting how many more there are
+func firstBlocks(blocks []uint64, n int) string {
+	if len(blocks) <= n {
+		return fmt.Sprint(blocks)
+	}
+	return fmt.Sprintf("%v... (and %d more)", blocks[:n], len(blocks)-n)
+}
+
 // LoadDataSetFromFile loads a dataset from a JSON file
 func LoadDataSetFromFile(filename string) (*DataSet, error) {
 	data, err := os.ReadFile(filename)
@@ -335,3 +337,36 @@ func ValidateDataSet(dataset *DataSet) error {
 
 	return nil
 }
+
+// SplitDataSet splits a dataset into contiguous block ranges of roughly equal size.
+// Later parts start from the actual base fee and gas limit of their first block.
+func SplitDataSet(dataset *DataSet, parts int) ([]*DataSet, error) {
+	if parts <= 0 {
+		return nil, fmt.Errorf("parts (%d) must be positive", parts)
+	}
+	if parts > len(dataset.Blocks) {
+		return nil, fmt.Errorf("cannot split %d blocks into %d parts", len(dataset.Blocks), parts)
+	}
+
+	split := make([]*DataSet, 0, parts)
+	for i := 0; i < parts; i++ {
+		start := i * len(dataset.Blocks) / parts
+		end := (i + 1) * len(dataset.Blocks) / parts
+		blocks := dataset.Blocks[start:end]
+
+		part := &DataSet{
+			StartBlock:      blocks[0].Number,
+			EndBlock:        blocks[len(blocks)-1].Number,
+			InitialBaseFee:  blocks[0].BaseFeePerGas,
+			InitialGasLimit: blocks[0].GasLimit,
+			Blocks:          blocks,
+			FetchedAt:       dataset.FetchedAt,
+		}
+		if i == 0 {
+			part.InitialBaseFee = dataset.InitialBaseFee
+			part.InitialGasLimit = dataset.InitialGasLimit
+		}
+		split = append(split, part)
+	}
+	return split, nil
+}
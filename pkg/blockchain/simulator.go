@@ -2,10 +2,15 @@ package blockchain
 
 import (
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"strings"
 
 	"github.com/brianbland/feemarketsim/pkg/analysis"
 	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/logging"
+	"github.com/brianbland/feemarketsim/pkg/output"
 	"github.com/brianbland/feemarketsim/pkg/scenarios"
 	"github.com/brianbland/feemarketsim/pkg/simulator"
 )
@@ -14,6 +19,8 @@ import (
 type Simulator struct {
 	config       config.Config
 	adjusterType simulator.AdjusterType
+	quiet        bool
+	logger       *slog.Logger
 }
 
 // NewSimulator creates a new blockchain simulator
@@ -24,6 +31,16 @@ func NewSimulator(cfg config.Config, adjusterType simulator.AdjusterType) *Simul
 	}
 }
 
+// SetQuiet suppresses the info record of each simulation, e.g. when many simulations run in parallel
+func (s *Simulator) SetQuiet(quiet bool) {
+	s.quiet = quiet
+}
+
+// SetLogger sets the logger each simulation and its adjuster report to; nil discards
+func (s *Simulator) SetLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
 // SimulateAgainstDataSet runs the AIMD mechanism against real blockchain data
 func (s *Simulator) SimulateAgainstDataSet(dataset *DataSet) (*SimulationResult, *analysis.Result, error) {
 	return s.SimulateAgainstDataSetWithOptions(dataset, false)
@@ -35,10 +52,13 @@ func (s *Simulator) SimulateAgainstDataSetWithOptions(dataset *DataSet, collectV
 		return nil, nil, fmt.Errorf("invalid dataset: %w", err)
 	}
 
-	fmt.Printf("\n=== Simulating Against Base Blockchain Data ===\n")
-	fmt.Printf("Block Range: %d - %d (%d blocks)\n", dataset.StartBlock, dataset.EndBlock, len(dataset.Blocks))
-	fmt.Printf("Initial Base Fee: %.3f Gwei\n", float64(dataset.InitialBaseFee)/1e9)
-	fmt.Printf("Initial Gas Limit: %.1f M gas\n\n", float64(dataset.InitialGasLimit)/1e6)
+	logger := logging.OrDiscard(s.logger).With("adjuster", s.adjusterType)
+	if !s.quiet {
+		logger.Info("Simulating against Base blockchain data", "start_block", dataset.StartBlock,
+			"end_block", dataset.EndBlock, "blocks", len(dataset.Blocks),
+			"initial_base_fee", config.FormatGwei(float64(dataset.InitialBaseFee)),
+			"initial_gas_limit", dataset.InitialGasLimit)
+	}
 
 	// Override config with real initial conditions
 	adjustedConfig := s.config
@@ -47,6 +67,7 @@ func (s *Simulator) SimulateAgainstDataSetWithOptions(dataset *DataSet, collectV
 
 	// Create fee adjuster using factory
 	factory := simulator.NewAdjusterFactory()
+	factory.SetLogger(s.logger)
 	adjuster, err := factory.CreateAdjusterWithConfigs(s.adjusterType, &adjustedConfig)
 
 	if err != nil {
@@ -59,6 +80,11 @@ func (s *Simulator) SimulateAgainstDataSetWithOptions(dataset *DataSet, collectV
 		baseFees  []uint64
 		gasUsages []uint64
 		compData  *ComparisonData
+
+		// Fees each block was priced at, aligned with the actual fee recorded in the block
+		blockNumbers  = make([]uint64, 0, len(dataset.Blocks))
+		actualFees    = make([]float64, 0, len(dataset.Blocks))
+		simulatedFees = make([]float64, 0, len(dataset.Blocks))
 	)
 
 	// Initialize comparison data if requested
@@ -84,6 +110,10 @@ func (s *Simulator) SimulateAgainstDataSetWithOptions(dataset *DataSet, collectV
 		totalTx += len(block.Transactions)
 		droppedTx += blockDropped
 
+		blockNumbers = append(blockNumbers, block.Number)
+		actualFees = append(actualFees, float64(block.BaseFeePerGas))
+		simulatedFees = append(simulatedFees, float64(currentBaseFee))
+
 		// Process block with effective gas usage
 		adjuster.ProcessBlock(effectiveGasUsed)
 		state := adjuster.GetCurrentState()
@@ -107,15 +137,15 @@ func (s *Simulator) SimulateAgainstDataSetWithOptions(dataset *DataSet, collectV
 			compData.LearningRates = append(compData.LearningRates, state.LearningRate)
 		}
 
-		if i < 10 || i%50 == 0 {
-			fmt.Printf("Block %d: Gas Used: %d, Base Fee: %.3f Gwei, Dropped Tx: %d\n",
-				block.Number, effectiveGasUsed, float64(state.BaseFee)/1e9, blockDropped)
-		}
+		logger.Debug("Simulated block", "block", block.Number, "gas_used", effectiveGasUsed,
+			"base_fee", config.FormatGwei(float64(state.BaseFee)), "dropped_tx", blockDropped)
 	}
 
 	// Calculate simulation results
 	simResult := s.calculateSimulationResult(totalTx, droppedTx, baseFees, gasUsages, adjustedConfig)
 	simResult.ComparisonData = compData
+	accuracy := CompareFees(blockNumbers, actualFees, simulatedFees)
+	simResult.Accuracy = &accuracy
 
 	// Create scenario for analysis
 	scenario := scenarios.Scenario{
@@ -199,28 +229,64 @@ func (s *Simulator) calculateSimulationResult(totalTx, droppedTx int, baseFees,
 }
 
 // PrintSimulationResults prints the results of blockchain simulation
-func PrintSimulationResults(simResult *SimulationResult, analysisResult *analysis.Result) {
-	fmt.Printf("\n" + strings.Repeat("=", 80) + "\n")
-	fmt.Printf("BASE BLOCKCHAIN SIMULATION RESULTS\n")
-	fmt.Printf(strings.Repeat("=", 80) + "\n")
-
-	fmt.Printf("Transaction Processing:\n")
-	fmt.Printf("  Total Transactions: %d\n", simResult.TotalTransactions)
-	fmt.Printf("  Dropped Transactions: %d (%.2f%%)\n", simResult.DroppedTransactions, simResult.DroppedPercentage)
-	fmt.Printf("  Effective Utilization: %.2f%%\n", simResult.EffectiveUtilization*100)
-
-	fmt.Printf("\nFee Market Performance:\n")
-	fmt.Printf("  Average Base Fee: %.3f Gwei\n", float64(simResult.AvgBaseFee)/1e9)
-	fmt.Printf("  Fee Range: %.3f - %.3f Gwei\n",
-		float64(simResult.MinBaseFee)/1e9, float64(simResult.MaxBaseFee)/1e9)
-	fmt.Printf("  Total Gas Processed: %.1f M gas\n", float64(simResult.TotalGasUsed)/1e6)
-
-	fmt.Printf("\nAIMD Mechanism Analysis:\n")
-	fmt.Printf("  Final Fee vs Initial: %.2fx\n",
+func PrintSimulationResults(w io.Writer, simResult *SimulationResult, analysisResult *analysis.Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "BASE BLOCKCHAIN SIMULATION RESULTS\n")
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	fmt.Fprintf(w, "Transaction Processing:\n")
+	fmt.Fprintf(w, "  Total Transactions: %d\n", simResult.TotalTransactions)
+	fmt.Fprintf(w, "  Dropped Transactions: %d (%.2f%%)\n", simResult.DroppedTransactions, simResult.DroppedPercentage)
+	fmt.Fprintf(w, "  Effective Utilization: %.2f%%\n", simResult.EffectiveUtilization*100)
+
+	fmt.Fprintf(w, "\nFee Market Performance:\n")
+	fmt.Fprintf(w, "  Average Base Fee: %s\n", config.FormatGwei(float64(simResult.AvgBaseFee)))
+	fmt.Fprintf(w, "  Fee Range: %s - %s\n",
+		config.FormatGwei(float64(simResult.MinBaseFee)), config.FormatGwei(float64(simResult.MaxBaseFee)))
+	fmt.Fprintf(w, "  Total Gas Processed: %.1f M gas\n", float64(simResult.TotalGasUsed)/1e6)
+
+	fmt.Fprintf(w, "\nAIMD Mechanism Analysis:\n")
+	fmt.Fprintf(w, "  Final Fee vs Initial: %.2fx\n",
 		float64(analysisResult.FinalBaseFee)/float64(analysisResult.InitialBaseFee))
-	fmt.Printf("  Fee Volatility: %.3f Gwei\n", analysisResult.BaseFeeVolatility/1e9)
-	fmt.Printf("  Average Learning Rate: %.6f\n", analysisResult.AvgLearningRate)
-	fmt.Printf("  Responsiveness Score: %.3f\n", analysisResult.ResponsivenessScore)
+	fmt.Fprintf(w, "  Fee Volatility: %s\n", config.FormatGwei(analysisResult.BaseFeeVolatility))
+	fmt.Fprintf(w, "  Average Learning Rate: %.6f\n", analysisResult.AvgLearningRate)
+	fmt.Fprintf(w, "  Responsiveness Score: %.3f\n", analysisResult.ResponsivenessScore)
+	if analysisResult.DominantPeriod > 0 {
+		fmt.Fprintf(w, "  Dominant Fee Oscillation: %.1f blocks (%.0f%% of spectral power)\n",
+			analysisResult.DominantPeriod, analysisResult.DominantPeriodPower*100)
+	}
+}
+
+// SimulationResultTable returns the analysis and dataset metrics of a replay as one row
+func SimulationResultTable(simResult *SimulationResult, analysisResult *analysis.Result) *output.Table {
+	metrics := analysisResult.Metrics()
+	for name, value := range simResult.Metrics() {
+		metrics[name] = value
+	}
+
+	var columns []string
+	var row []interface{}
+	for _, name := range append(append([]string{}, analysis.MetricNames...), MetricNames...) {
+		if value, ok := metrics[name]; ok {
+			columns = append(columns, name)
+			row = append(row, value)
+		}
+	}
+	table := output.NewTable("replay", "Replay Summary", columns...)
+	table.Append(row...)
+	return table
+}
+
+// BlocksTable returns the per-block fees, gas and dropped transactions of a replay.
+// Fees are in Gwei and gas in millions, as collected for the charts.
+func BlocksTable(data *ComparisonData) *output.Table {
+	table := output.NewTable("blocks", "Per-Block Replay", "block", "actual_base_fee_gwei", "simulated_base_fee_gwei",
+		"dropped_tx_percent", "actual_gas_used_mgas", "effective_gas_used_mgas", "learning_rate")
+	for i := range data.BlockNumbers {
+		table.Append(int(data.BlockNumbers[i]), data.ActualBaseFees[i], data.SimulatedBaseFees[i],
+			data.DroppedPercentages[i], data.ActualGasUsages[i], data.EffectiveGasUsages[i], data.LearningRates[i])
+	}
+	return table
 }
 
 // Utility functions for calculating statistics
@@ -267,10 +333,10 @@ func (s *Simulator) maxUint64(values []uint64) uint64 {
 }
 
 // CompareWithActualBaseFees compares simulated results with actual Base blockchain fees
-func (s *Simulator) CompareWithActualBaseFees(dataset *DataSet, simResult *SimulationResult) {
-	fmt.Printf("\n" + strings.Repeat("=", 60) + "\n")
-	fmt.Printf("SIMULATED vs ACTUAL BASE FEES COMPARISON\n")
-	fmt.Printf(strings.Repeat("=", 60) + "\n")
+func (s *Simulator) CompareWithActualBaseFees(w io.Writer, dataset *DataSet, simResult *SimulationResult) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 60)+"\n")
+	fmt.Fprintf(w, "SIMULATED vs ACTUAL BASE FEES COMPARISON\n")
+	fmt.Fprintf(w, strings.Repeat("=", 60)+"\n")
 
 	// Calculate statistics for actual Base fees
 	var actualFees []uint64
@@ -282,23 +348,35 @@ func (s *Simulator) CompareWithActualBaseFees(dataset *DataSet, simResult *Simul
 	actualMin := s.minUint64(actualFees)
 	actualMax := s.maxUint64(actualFees)
 
-	fmt.Printf("Actual Base Fees (EIP-1559):\n")
-	fmt.Printf("  Average: %.3f Gwei\n", actualAvg/1e9)
-	fmt.Printf("  Range: %.3f - %.3f Gwei\n", float64(actualMin)/1e9, float64(actualMax)/1e9)
+	fmt.Fprintf(w, "Actual Base Fees (EIP-1559):\n")
+	fmt.Fprintf(w, "  Average: %s\n", config.FormatGwei(actualAvg))
+	fmt.Fprintf(w, "  Range: %s - %s\n", config.FormatGwei(float64(actualMin)), config.FormatGwei(float64(actualMax)))
 
-	fmt.Printf("\nSimulation Results:\n")
-	fmt.Printf("  Average: %.3f Gwei\n", float64(simResult.AvgBaseFee)/1e9)
-	fmt.Printf("  Range: %.3f - %.3f Gwei\n", float64(simResult.MinBaseFee)/1e9, float64(simResult.MaxBaseFee)/1e9)
+	logActual := make([]float64, len(actualFees))
+	for i, fee := range actualFees {
+		logActual[i] = math.Log(math.Max(float64(fee), 1))
+	}
+	for _, period := range analysis.DominantPeriods(logActual, 3) {
+		fmt.Fprintf(w, "  Oscillation: %.1f blocks (%.0f%% of spectral power)\n", period.Blocks, period.Power*100)
+	}
+
+	fmt.Fprintf(w, "\nSimulation Results:\n")
+	fmt.Fprintf(w, "  Average: %s\n", config.FormatGwei(float64(simResult.AvgBaseFee)))
+	fmt.Fprintf(w, "  Range: %s - %s\n", config.FormatGwei(float64(simResult.MinBaseFee)), config.FormatGwei(float64(simResult.MaxBaseFee)))
 
-	fmt.Printf("\nComparison:\n")
+	fmt.Fprintf(w, "\nComparison:\n")
 	avgRatio := float64(simResult.AvgBaseFee) / actualAvg
-	fmt.Printf("  Simulated/Actual Average Ratio: %.3fx\n", avgRatio)
+	fmt.Fprintf(w, "  Simulated/Actual Average Ratio: %.3fx\n", avgRatio)
 
 	if avgRatio > 1.1 {
-		fmt.Printf("  → Simulated fees are %.1f%% higher than actual\n", (avgRatio-1)*100)
+		fmt.Fprintf(w, "  → Simulated fees are %.1f%% higher than actual\n", (avgRatio-1)*100)
 	} else if avgRatio < 0.9 {
-		fmt.Printf("  → Simulated fees are %.1f%% lower than actual\n", (1-avgRatio)*100)
+		fmt.Fprintf(w, "  → Simulated fees are %.1f%% lower than actual\n", (1-avgRatio)*100)
 	} else {
-		fmt.Printf("  → Simulated fees are comparable to actual (within 10%%)\n")
+		fmt.Fprintf(w, "  → Simulated fees are comparable to actual (within 10%%)\n")
+	}
+
+	if simResult.Accuracy != nil {
+		PrintAccuracy(w, *simResult.Accuracy)
 	}
 }
@@ -1,6 +1,9 @@
 package blockchain
 
-import "time"
+import (
+	"log/slog"
+	"time"
+)
 
 // BlockData represents block data from Base blockchain
 type BlockData struct {
@@ -51,10 +54,40 @@ type SimulationResult struct {
 	MinBaseFee           uint64  `json:"minBaseFee"`
 	TotalGasUsed         uint64  `json:"totalGasUsed"`
 	EffectiveUtilization float64 `json:"effectiveUtilization"`
+	// Block-by-block agreement with the actual base fees
+	Accuracy *Accuracy `json:"accuracy,omitempty"`
 	// Extended data for visualization
 	ComparisonData *ComparisonData `json:"comparisonData,omitempty"`
 }
 
+// MetricNames lists the numeric SimulationResult metrics that analysis.Result does not cover
+var MetricNames = []string{
+	"dropped_tx_percent",
+	"effective_utilization",
+	"avg_sim_base_fee",
+	"fee_rmse",
+	"fee_mape",
+	"fee_bias",
+	"fee_correlation",
+	"fee_lag",
+	"fee_directional_agreement",
+}
+
+// Metrics returns the dataset-only metrics of the result keyed by name
+func (r SimulationResult) Metrics() map[string]float64 {
+	metrics := map[string]float64{
+		"dropped_tx_percent":    r.DroppedPercentage,
+		"effective_utilization": r.EffectiveUtilization,
+		"avg_sim_base_fee":      float64(r.AvgBaseFee),
+	}
+	if r.Accuracy != nil {
+		for name, value := range r.Accuracy.Metrics() {
+			metrics[name] = value
+		}
+	}
+	return metrics
+}
+
 // ComparisonData holds detailed simulation data for visualization
 type ComparisonData struct {
 	BlockNumbers       []float64 `json:"blockNumbers"`
@@ -82,6 +115,7 @@ type FetchOptions struct {
 	Workers    int
 	MaxRetries int
 	Timeout    time.Duration
+	Logger     *slog.Logger // Receives fetch progress, retries and missing blocks; nil discards
 }
 
 // DefaultFetchOptions returns sensible defaults for fetching
@@ -0,0 +1,257 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+)
+
+// largeFetch is the block count above which fetch asks for confirmation
+const largeFetch = 10000
+
+// fetchCommand downloads a block range from a Base RPC endpoint into a dataset file
+func fetchCommand() *Command {
+	var (
+		yes     bool
+		rpcURL  string
+		timeout time.Duration
+		workers int
+	)
+	return &Command{
+		Name:       "fetch",
+		Aliases:    []string{"fetch-base"},
+		Args:       "<start_block> <end_block> <output_file>",
+		Summary:    "Fetch a range of Base blocks and their transactions into a dataset file",
+		Details:    "Example: feemarketsim fetch 12000000 12000100 base_data.json",
+		Structured: true,
+		NoManifest: true, // A rerun would fetch again, and the dataset records when it was fetched
+		Flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&yes, "yes", false, "Fetch large ranges without asking for confirmation")
+			fs.StringVar(&rpcURL, "rpc-url", "https://mainnet.base.org/", "JSON-RPC endpoint to fetch from")
+			fs.DurationVar(&timeout, "timeout", 2*time.Hour, "Give up on the whole fetch after this long")
+			fs.IntVar(&workers, "workers", blockchain.DefaultFetchOptions(0, 0).Workers, "Blocks fetched in parallel")
+		},
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) != 3 {
+				return usageErrorf("expected <start_block> <end_block> <output_file>, got %d arguments", len(args))
+			}
+
+			startBlock, err := strconv.ParseUint(args[0], 10, 64)
+			if err != nil {
+				return usageErrorf("invalid start block: %v", err)
+			}
+			endBlock, err := strconv.ParseUint(args[1], 10, 64)
+			if err != nil {
+				return usageErrorf("invalid end block: %v", err)
+			}
+			filename := args[2]
+
+			if startBlock >= endBlock {
+				return usageErrorf("start block (%d) must be less than end block (%d)", startBlock, endBlock)
+			}
+			if workers <= 0 {
+				return usageErrorf("workers (%d) must be positive", workers)
+			}
+
+			blockCount := endBlock - startBlock + 1
+			if blockCount > largeFetch && !yes {
+				env.Warnf("fetching %d blocks may take a long time and consume significant resources.", blockCount)
+				fmt.Fprintf(env.Stderr, "Consider using smaller ranges (e.g., 100-1000 blocks) for testing.\n")
+				fmt.Fprint(env.Stderr, "Continue? (y/N): ")
+				response, _ := bufio.NewReader(env.Stdin).ReadString('\n')
+				response = strings.ToLower(strings.TrimSpace(response))
+				if response != "y" && response != "yes" {
+					return errors.New("fetch cancelled")
+				}
+			}
+
+			// Create blockchain client and fetcher
+			client := blockchain.NewBaseRPCClientWithURL(rpcURL)
+			client.SetLogger(env.Logger)
+			fetchOptions := blockchain.DefaultFetchOptions(startBlock, endBlock)
+			fetchOptions.Workers = workers
+			fetchOptions.Logger = env.Logger
+			fetcher := blockchain.NewBlockFetcher(client, fetchOptions)
+
+			ctx, cancel := context.WithTimeout(env.Context, timeout)
+			defer cancel()
+
+			// Progress goes to stderr so stdout only holds the summary
+			progressCallback := func(progress blockchain.FetchProgress) {
+				if progress.Completed > 0 {
+					elapsed := time.Since(progress.StartTime)
+					rate := float64(progress.Completed) / elapsed.Seconds()
+					fmt.Fprintf(env.Stderr, "Progress: %d/%d completed (%.1f%%), %.1f blocks/sec\n",
+						progress.Completed, progress.Total,
+						float64(progress.Completed)/float64(progress.Total)*100, rate)
+				}
+			}
+
+			fmt.Fprintf(env.Stderr, "Starting blockchain data fetch...\n")
+			dataset, err := fetcher.FetchRange(ctx, progressCallback)
+			if err != nil {
+				return fmt.Errorf("failed to fetch blockchain data: %w", err)
+			}
+
+			if err := blockchain.SaveDataSetToFile(dataset, filename); err != nil {
+				return fmt.Errorf("failed to save dataset: %w", err)
+			}
+
+			fmt.Fprintf(env.Info, "\n✅ Successfully fetched and saved %d blocks to %s\n", len(dataset.Blocks), filename)
+			summary := summarizeDataSet(filename, dataset)
+			if env.Format != output.Text {
+				return output.Write(env.Out, env.Format, dataSetTable(summary))
+			}
+			printDataSetSummary(env.Out, summary)
+			return nil
+		},
+	}
+}
+
+// datasetCommand validates dataset files and summarizes their contents
+func datasetCommand() *Command {
+	return &Command{
+		Name:       "dataset",
+		Args:       "<dataset>...",
+		Summary:    "Validate dataset files and summarize their blocks, fees and transactions",
+		Details:    "Exits with a failure status when any dataset cannot be loaded or has missing blocks.",
+		Structured: true,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) == 0 {
+				return usageErrorf("expected at least one dataset file")
+			}
+
+			var summaries []dataSetSummary
+			invalid := 0
+			for _, filename := range args {
+				dataset, err := blockchain.LoadDataSetFromFile(filename)
+				if err == nil {
+					err = blockchain.ValidateDataSet(dataset)
+				}
+				if err != nil {
+					summaries = append(summaries, dataSetSummary{File: filename, Error: err.Error()})
+					invalid++
+					continue
+				}
+				summaries = append(summaries, summarizeDataSet(filename, dataset))
+			}
+
+			if env.Format != output.Text {
+				if err := output.Write(env.Out, env.Format, dataSetTable(summaries...)); err != nil {
+					return err
+				}
+			} else {
+				for i, summary := range summaries {
+					if i > 0 {
+						fmt.Fprintln(env.Out)
+					}
+					if summary.Error != "" {
+						fmt.Fprintf(env.Out, "❌ %s: %s\n", summary.File, summary.Error)
+						continue
+					}
+					fmt.Fprintf(env.Out, "✅ %s\n", summary.File)
+					printDataSetSummary(env.Out, summary)
+				}
+			}
+
+			if invalid > 0 {
+				return fmt.Errorf("%d of %d datasets are invalid", invalid, len(args))
+			}
+			return nil
+		},
+	}
+}
+
+// dataSetSummary holds the block range, fees, gas and transaction counts of a dataset file
+type dataSetSummary struct {
+	File            string
+	Error           string // Why the file could not be loaded or validated; the other fields are then empty
+	StartBlock      uint64
+	EndBlock        uint64
+	Blocks          int
+	InitialBaseFee  uint64
+	InitialGasLimit uint64
+	MinBaseFee      uint64
+	MaxBaseFee      uint64
+	GasUsedPercent  float64 // Gas used as a percentage of the gas limit
+	Transactions    int
+	FetchedAt       int64
+}
+
+// summarizeDataSet computes the summary of a loaded dataset
+func summarizeDataSet(filename string, dataset *blockchain.DataSet) dataSetSummary {
+	summary := dataSetSummary{
+		File:            filename,
+		StartBlock:      dataset.StartBlock,
+		EndBlock:        dataset.EndBlock,
+		Blocks:          len(dataset.Blocks),
+		InitialBaseFee:  dataset.InitialBaseFee,
+		InitialGasLimit: dataset.InitialGasLimit,
+		FetchedAt:       dataset.FetchedAt,
+	}
+
+	var totalGas, totalLimit uint64
+	for i, block := range dataset.Blocks {
+		summary.Transactions += len(block.Transactions)
+		totalGas += block.GasUsed
+		totalLimit += block.GasLimit
+		if i == 0 || block.BaseFeePerGas < summary.MinBaseFee {
+			summary.MinBaseFee = block.BaseFeePerGas
+		}
+		if block.BaseFeePerGas > summary.MaxBaseFee {
+			summary.MaxBaseFee = block.BaseFeePerGas
+		}
+	}
+	if totalLimit > 0 {
+		summary.GasUsedPercent = float64(totalGas) / float64(totalLimit) * 100
+	}
+	return summary
+}
+
+// printDataSetSummary prints the block range, fees, gas and transaction counts of a dataset
+func printDataSetSummary(w io.Writer, summary dataSetSummary) {
+	fmt.Fprintf(w, "Dataset contains:\n")
+	fmt.Fprintf(w, "  - Blocks: %d to %d\n", summary.StartBlock, summary.EndBlock)
+	fmt.Fprintf(w, "  - Initial Base Fee: %s\n", config.FormatGwei(float64(summary.InitialBaseFee)))
+	fmt.Fprintf(w, "  - Initial Gas Limit: %.1f M gas\n", float64(summary.InitialGasLimit)/1e6)
+	if summary.Blocks > 0 {
+		fmt.Fprintf(w, "  - Base Fee Range: %s to %s\n", config.FormatGwei(float64(summary.MinBaseFee)), config.FormatGwei(float64(summary.MaxBaseFee)))
+		if summary.GasUsedPercent > 0 {
+			fmt.Fprintf(w, "  - Gas Used: %.1f%% of the gas limit\n", summary.GasUsedPercent)
+		}
+	}
+	fmt.Fprintf(w, "  - Total Transactions: %d\n", summary.Transactions)
+	if summary.FetchedAt > 0 {
+		fmt.Fprintf(w, "  - Fetched At: %s\n", time.Unix(summary.FetchedAt, 0).UTC().Format(time.RFC3339))
+	}
+}
+
+// dataSetTable returns one row per dataset summary; fees are in wei
+func dataSetTable(summaries ...dataSetSummary) *output.Table {
+	table := output.NewTable("datasets", "Datasets", "file", "valid", "error", "start_block", "end_block", "blocks",
+		"initial_base_fee", "initial_gas_limit", "min_base_fee", "max_base_fee", "gas_used_percent", "transactions", "fetched_at")
+	for _, summary := range summaries {
+		if summary.Error != "" {
+			table.Append(summary.File, false, summary.Error)
+			continue
+		}
+		fetchedAt := ""
+		if summary.FetchedAt > 0 {
+			fetchedAt = time.Unix(summary.FetchedAt, 0).UTC().Format(time.RFC3339)
+		}
+		table.Append(summary.File, true, "", summary.StartBlock, summary.EndBlock, summary.Blocks,
+			summary.InitialBaseFee, summary.InitialGasLimit, summary.MinBaseFee, summary.MaxBaseFee,
+			summary.GasUsedPercent, summary.Transactions, fetchedAt)
+	}
+	return table
+}
@@ -0,0 +1,509 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/logging"
+	"github.com/brianbland/feemarketsim/pkg/manifest"
+	"github.com/brianbland/feemarketsim/pkg/output"
+)
+
+// Exit codes returned by App.Run
+const (
+	ExitOK      = 0 // The command succeeded
+	ExitFailure = 1 // The command ran and failed, e.g. a dataset could not be loaded
+	ExitUsage   = 2 // The command line was invalid: unknown command, flag or argument, or invalid configuration
+)
+
+// defaultCommand runs when the arguments name no command, so feemarketsim -adjuster-type=pid still works
+const defaultCommand = "run"
+
+// Env is what a command reads from and writes to
+type Env struct {
+	Context context.Context
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+
+	// Set from -output and -out-file before the command runs
+	Format output.Format
+	Out    io.Writer // Results: the -out-file, or Stdout
+	Info   io.Writer // Progress and notes: always Stderr, so stdout holds only the results
+
+	// Diagnostics of the packages the command runs: Stderr at -log-level, in -log-format
+	Logger *slog.Logger
+
+	files []string // Data files the command wrote, hashed into its manifest
+}
+
+// Wrote records a data file written by the command, so its manifest checks that a rerun writes
+// the same file. Charts and reports are not recorded: they embed random chart IDs and timestamps.
+func (e *Env) Wrote(path string) {
+	e.files = append(e.files, path)
+}
+
+// Warnf reports a problem that does not stop the command
+func (e *Env) Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(e.Stderr, "Warning: "+format+"\n", args...)
+}
+
+// Command is one subcommand. Every command accepts the shared configuration flags; Flags adds its own.
+type Command struct {
+	Name    string
+	Aliases []string // Other names accepted, e.g. the former fetch-base
+	Args    string   // Positional arguments shown in the usage line, e.g. <dataset>
+	Summary string   // One line shown in the command list
+	Details string   // Paragraph shown by the command's help
+	Flags   func(fs *flag.FlagSet)
+	Run     func(env *Env, cfg *config.Config, args []string) error
+
+	// Structured commands write their results as tables with -output=json, csv or markdown;
+	// the others only print text
+	Structured bool
+
+	// NoManifest commands cannot be reproduced from a manifest, e.g. serve, and reject -manifest
+	NoManifest bool
+}
+
+// usageError marks an invalid command line, reported with exit code ExitUsage
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string { return e.err.Error() }
+func (e usageError) Unwrap() error { return e.err }
+
+// usageErrorf returns an error reported as an invalid command line
+func usageErrorf(format string, args ...interface{}) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// App dispatches command lines to commands
+type App struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// New returns an app using the given streams
+func New(stdin io.Reader, stdout, stderr io.Writer) *App {
+	return &App{Stdin: stdin, Stdout: stdout, Stderr: stderr}
+}
+
+// commands returns every command. Commands keep their flag values in closures, so each run builds
+// a fresh set.
+func commands() []*Command {
+	return []*Command{
+		runCommand(),
+		compareCommand(),
+		replayCommand(),
+		fetchCommand(),
+		datasetCommand(),
+		reportCommand(),
+		serveCommand(),
+		sweepCommand(),
+		tuneCommand(),
+		paretoCommand(),
+		sensitivityCommand(),
+		significanceCommand(),
+		monteCarloCommand(),
+		attackCommand(),
+		searchCommand(),
+		responseCommand(),
+		frequencyCommand(),
+		equilibriumCommand(),
+		runExperimentCommand(),
+		rerunCommand(),
+	}
+}
+
+// lookup returns the command with the given name or alias
+func lookup(commands []*Command, name string) *Command {
+	for _, command := range commands {
+		if command.Name == name {
+			return command
+		}
+		for _, alias := range command.Aliases {
+			if alias == name {
+				return command
+			}
+		}
+	}
+	return nil
+}
+
+// Run runs the command line, without the program name, and returns the exit code.
+// Shared flags may come before the command name: feemarketsim -config=exp.toml sweep.
+func (a *App) Run(ctx context.Context, args []string) int {
+	env := &Env{Context: ctx, Stdin: a.Stdin, Stdout: a.Stdout, Stderr: a.Stderr}
+	all := commands()
+
+	global, name, rest := splitCommand(args)
+	if name == "" {
+		if hasHelpFlag(global) {
+			printUsage(env.Stdout, all)
+			return ExitOK
+		}
+		name = defaultCommand
+	}
+
+	if name == "help" {
+		return a.help(env, all, rest)
+	}
+
+	command := lookup(all, name)
+	if command == nil {
+		fmt.Fprintf(env.Stderr, "Error: unknown command %q\n", name)
+		fmt.Fprintf(env.Stderr, "Run 'feemarketsim help' for the list of commands.\n")
+		return ExitUsage
+	}
+
+	commandArgs := append(append([]string{}, global...), rest...)
+	if err := run(env, command, commandArgs); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printCommandHelp(env.Stdout, command)
+			return ExitOK
+		}
+		fmt.Fprintf(env.Stderr, "Error: %v\n", err)
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			fmt.Fprintf(env.Stderr, "Run 'feemarketsim %s -help' for usage.\n", command.Name)
+			return ExitUsage
+		}
+		return ExitFailure
+	}
+	return ExitOK
+}
+
+// run parses the shared and command flags and runs the command
+func run(env *Env, command *Command, args []string) error {
+	_, err := runAndRecord(env, command, args, false)
+	return err
+}
+
+// runAndRecord runs the command like run and returns its manifest. The manifest is recorded when
+// record is true or -manifest is set, and saved in the latter case; otherwise it is nil.
+func runAndRecord(env *Env, command *Command, args []string, record bool) (*manifest.Manifest, error) {
+	parser := config.NewParser()
+	fs := parser.FlagSet()
+	if command.Flags != nil {
+		command.Flags(fs)
+	}
+
+	if hasHelpFlag(args) {
+		return nil, flag.ErrHelp
+	}
+
+	cfg, err := parser.Parse(interleave(fs, args))
+	if err != nil {
+		return nil, usageError{err: err}
+	}
+	if cfg.Simulation.ShowHelp {
+		// -help set through a config file or the environment
+		return nil, flag.ErrHelp
+	}
+	if cfg.Simulation.DumpConfig {
+		return nil, dumpConfig(env, *cfg)
+	}
+
+	for _, warning := range parser.Deprecated() {
+		env.Warnf("%s", warning)
+	}
+
+	format := output.Format(cfg.Simulation.Output)
+	if format != output.Text && !command.Structured {
+		return nil, usageErrorf("the %s command only prints text, not -output=%s", command.Name, format)
+	}
+
+	// Diagnostics of the packages the command runs go to stderr, at the level set by -log-level
+	logger, err := logging.New(env.Stderr, cfg.Simulation.LogLevel, cfg.Simulation.LogFormat)
+	if err != nil {
+		return nil, usageError{err: err}
+	}
+
+	record = record || cfg.Simulation.Manifest != ""
+	if record && command.NoManifest {
+		return nil, usageErrorf("the %s command cannot be rerun from a manifest", command.Name)
+	}
+
+	var m *manifest.Manifest
+	if record {
+		m, err = manifest.New(command.Name, commandFlags(fs), parser.Args(), *cfg, inputFiles(*cfg, parser.Args()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	commandEnv := *env
+	commandEnv.Format = format
+	commandEnv.Out = env.Stdout
+	commandEnv.Info = env.Stderr
+	commandEnv.Logger = logger
+
+	var results *manifest.Writer
+	if cfg.Simulation.OutFile == "" {
+		if record {
+			results = manifest.NewWriter(commandEnv.Out)
+			commandEnv.Out = results
+		}
+		if err := command.Run(&commandEnv, cfg, parser.Args()); err != nil {
+			return nil, err
+		}
+	} else {
+		err := writeOutFile(cfg.Simulation.OutFile, func(w io.Writer) error {
+			commandEnv.Out = w
+			return command.Run(&commandEnv, cfg, parser.Args())
+		})
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(commandEnv.Info, "Results saved to %s\n", cfg.Simulation.OutFile)
+		commandEnv.Wrote(cfg.Simulation.OutFile)
+	}
+
+	if !record {
+		return nil, nil
+	}
+	if results != nil {
+		m.Outputs = append(m.Outputs, results.File(manifest.Results))
+	}
+	for _, path := range commandEnv.files {
+		file, err := manifest.HashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m.Outputs = append(m.Outputs, file)
+	}
+	if cfg.Simulation.Manifest != "" {
+		if err := manifest.SaveToFile(m, cfg.Simulation.Manifest); err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(commandEnv.Info, "Manifest saved to %s\n", cfg.Simulation.Manifest)
+	}
+	return m, nil
+}
+
+// dumpConfig writes the resolved configuration for -dump-config to the -out-file, or stdout
+func dumpConfig(env *Env, cfg config.Config) error {
+	if cfg.Simulation.OutFile == "" {
+		return config.DumpConfig(env.Stdout, cfg, config.DumpFormat(cfg))
+	}
+	return writeOutFile(cfg.Simulation.OutFile, func(w io.Writer) error {
+		return config.DumpConfig(w, cfg, config.DumpFormat(cfg))
+	})
+}
+
+// writeOutFile writes the -out-file through a temporary file next to it, renamed into place only
+// when write succeeds, so a failed command leaves the results of an earlier run untouched
+func writeOutFile(path string, write func(w io.Writer) error) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(file.Name()) // Fails harmlessly once the file is renamed
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	err = write(file)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		return fmt.Errorf("failed to write output file: %w", closeErr)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+	return nil
+}
+
+// commandFlags returns the command's own flags that were set, leaving out the shared flags
+// recorded with the configuration
+func commandFlags(fs *flag.FlagSet) map[string]string {
+	shared := config.NewParser().FlagSet()
+	flags := make(map[string]string)
+	fs.Visit(func(f *flag.Flag) {
+		if shared.Lookup(f.Name) == nil {
+			flags[f.Name] = f.Value.String()
+		}
+	})
+	return flags
+}
+
+// inputFiles returns the scenario and dataset files a command reads: -scenario-file, -dataset and
+// positional arguments naming existing files
+func inputFiles(cfg config.Config, args []string) []string {
+	var files []string
+	seen := make(map[string]bool)
+	add := func(path string) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			files = append(files, path)
+		}
+	}
+
+	add(cfg.Simulation.ScenarioFile)
+	for _, path := range cfg.Simulation.DataSets {
+		add(path)
+	}
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.Mode().IsRegular() {
+			add(arg)
+		}
+	}
+	return files
+}
+
+// help prints the command list, a command's help, or the shared flags
+func (a *App) help(env *Env, all []*Command, args []string) int {
+	if len(args) == 0 {
+		printUsage(env.Stdout, all)
+		return ExitOK
+	}
+	if args[0] == "flags" {
+		// The detailed help of the shared flags is printed by the config package
+		parser := config.NewParser()
+		parser.FlagSet()
+		parser.ShowDetailedHelp(env.Stdout)
+		return ExitOK
+	}
+	command := lookup(all, args[0])
+	if command == nil {
+		fmt.Fprintf(env.Stderr, "Error: unknown command %q\n", args[0])
+		return ExitUsage
+	}
+	printCommandHelp(env.Stdout, command)
+	return ExitOK
+}
+
+// splitCommand splits a command line into the shared flags before the command name, the command
+// name and the arguments after it. The name is empty when there is no positional argument.
+func splitCommand(args []string) (global []string, name string, rest []string) {
+	fs := config.NewParser().FlagSet()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args[:i], "", args[i+1:]
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return args[:i], arg, args[i+1:]
+		}
+		if takesValue(fs, arg) {
+			i++
+		}
+	}
+	return args, "", nil
+}
+
+// interleave moves positional arguments after the flags, so a command accepts
+// replay data.json -graph as well as replay -graph data.json
+func interleave(fs *flag.FlagSet, args []string) []string {
+	var flags, positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			positional = append(positional, arg)
+			continue
+		}
+		flags = append(flags, arg)
+		if takesValue(fs, arg) && i+1 < len(args) {
+			i++
+			flags = append(flags, args[i])
+		}
+	}
+	if len(positional) == 0 {
+		return flags
+	}
+	return append(append(flags, "--"), positional...)
+}
+
+// takesValue reports whether a flag argument is followed by a separate value argument,
+// as in -scenario full but not -scenario=full or -graph
+func takesValue(fs *flag.FlagSet, arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if strings.Contains(name, "=") {
+		return false
+	}
+	f := fs.Lookup(name)
+	if f == nil {
+		return false
+	}
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return !ok || !boolFlag.IsBoolFlag()
+}
+
+// hasHelpFlag reports whether the flags before any -- ask for help
+func hasHelpFlag(args []string) bool {
+	for _, arg := range args {
+		switch arg {
+		case "--":
+			return false
+		case "-h", "--h", "-help", "--help", "-help=true", "--help=true":
+			return true
+		}
+	}
+	return false
+}
+
+// printUsage prints the command list
+func printUsage(w io.Writer, all []*Command) {
+	fmt.Fprintln(w, "Usage: feemarketsim [shared flags] <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	sorted := append([]*Command{}, all...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	for _, command := range sorted {
+		summary := command.Summary
+		if command.Name == defaultCommand {
+			summary += " (default)"
+		}
+		fmt.Fprintf(tw, "  %s\t%s\n", command.Name, summary)
+	}
+	fmt.Fprintf(tw, "  help\tShow the help of a command, or of the shared flags with 'help flags'\n")
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Every command accepts the shared simulation flags (-adjuster-type, -config, -profile, ...),")
+	fmt.Fprintln(w, "before or after its name. Run 'feemarketsim help flags' to list them.")
+}
+
+// printCommandHelp prints a command's usage, details and own flags
+func printCommandHelp(w io.Writer, command *Command) {
+	usage := "feemarketsim " + command.Name + " [flags]"
+	if command.Args != "" {
+		usage += " " + command.Args
+	}
+	fmt.Fprintf(w, "Usage: %s\n\n%s\n", usage, command.Summary)
+	if command.Details != "" {
+		fmt.Fprintf(w, "\n%s\n", command.Details)
+	}
+	if len(command.Aliases) > 0 {
+		fmt.Fprintf(w, "\nAliases: %s\n", strings.Join(command.Aliases, ", "))
+	}
+
+	if command.Flags != nil {
+		fs := flag.NewFlagSet(command.Name, flag.ContinueOnError)
+		command.Flags(fs)
+		fs.SetOutput(w)
+		fmt.Fprintf(w, "\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	fmt.Fprintf(w, "\nShared flags: run 'feemarketsim help flags'.\n")
+}
@@ -0,0 +1,404 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/manifest"
+)
+
+// execute runs a command line in-process and returns the exit code and both outputs
+func execute(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := New(strings.NewReader(""), &stdout, &stderr).Run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+// writeDataSet saves a small valid dataset and returns its path
+func writeDataSet(t *testing.T) string {
+	t.Helper()
+	dataset := &blockchain.DataSet{StartBlock: 100, EndBlock: 102, InitialBaseFee: 1_000_000_000, InitialGasLimit: 30_000_000}
+	for i := uint64(0); i < 3; i++ {
+		dataset.Blocks = append(dataset.Blocks, blockchain.BlockData{
+			Number:        100 + i,
+			GasLimit:      30_000_000,
+			GasUsed:       15_000_000,
+			BaseFeePerGas: 1_000_000_000,
+		})
+	}
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := blockchain.SaveDataSetToFile(dataset, path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"help", []string{"help"}, ExitOK, ""},
+		{"help flag", []string{"-help"}, ExitOK, ""},
+		{"command help", []string{"fetch", "-help"}, ExitOK, ""},
+		{"unknown command", []string{"bogus"}, ExitUsage, `unknown command "bogus"`},
+		{"unknown flag", []string{"run", "-bogus"}, ExitUsage, "-bogus"},
+		{"missing argument", []string{"replay"}, ExitUsage, "expected one dataset file"},
+		{"invalid configuration", []string{"equilibrium", "-eq-blocks=10"}, ExitUsage, "eq-blocks"},
+		{"global flag before command", []string{"-eq-blocks=10", "equilibrium"}, ExitUsage, "eq-blocks"},
+		{"comparison rejected", []string{"sweep", "-adjuster-type=aimd,pid"}, ExitUsage, "one adjuster configuration"},
+		{"text-only command", []string{"report", "-output=json"}, ExitUsage, "only prints text"},
+		{"invalid output format", []string{"run", "-output=xml"}, ExitUsage, "invalid output format"},
+		{"invalid log level", []string{"run", "-log-level=trace"}, ExitUsage, "invalid log level"},
+		{"manifest rejected", []string{"serve", "-manifest=serve.json"}, ExitUsage, "cannot be rerun"},
+		{"missing manifest", []string{"rerun", "missing.json"}, ExitFailure, "failed to read manifest"},
+		{"bad block range", []string{"fetch", "20", "10", "out.json"}, ExitUsage, "must be less than"},
+		{"missing dataset", []string{"dataset", "missing.json"}, ExitFailure, "1 of 1 datasets are invalid"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			code, _, stderr := execute(test.args...)
+			if code != test.code {
+				t.Errorf("expected exit code %d, got %d (stderr: %s)", test.code, code, stderr)
+			}
+			if !strings.Contains(stderr, test.stderr) {
+				t.Errorf("expected stderr to contain %q, got %q", test.stderr, stderr)
+			}
+		})
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	args := []string{"run", "-scenario=full", "-adjuster-type=pid", "-rng-seed=1"}
+	code, _, stderr := execute(args...)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if strings.Contains(stderr, "PID effective learning rate") {
+		t.Errorf("expected no debug records at the default level, got %q", stderr)
+	}
+
+	code, _, stderr = execute(append(args, "-log-level=debug", "-log-format=json")...)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, `"level":"DEBUG","msg":"PID effective learning rate"`) {
+		t.Errorf("expected JSON debug records on stderr, got %q", stderr)
+	}
+
+	// Concurrent commands keep their own logger and stderr
+	var wg sync.WaitGroup
+	stderrs := make([]string, 2)
+	for i, level := range []string{"-log-level=debug", "-log-level=error"} {
+		wg.Add(1)
+		go func(i int, level string) {
+			defer wg.Done()
+			_, _, stderrs[i] = execute(append(args, level)...)
+		}(i, level)
+	}
+	wg.Wait()
+	if !strings.Contains(stderrs[0], "PID effective learning rate") || strings.Contains(stderrs[1], "PID effective learning rate") {
+		t.Errorf("expected debug records only from the debug command, got %q and %q", stderrs[0], stderrs[1])
+	}
+}
+
+func TestHelpListsCommands(t *testing.T) {
+	_, stdout, _ := execute("help")
+	for _, command := range commands() {
+		if !strings.Contains(stdout, "  "+command.Name+" ") {
+			t.Errorf("help does not list %s", command.Name)
+		}
+	}
+
+	_, stdout, _ = execute("help", "fetch-base")
+	if !strings.Contains(stdout, "Usage: feemarketsim fetch [flags] <start_block>") || !strings.Contains(stdout, "-rpc-url") {
+		t.Errorf("unexpected fetch help:\n%s", stdout)
+	}
+
+	_, stdout, _ = execute("help", "flags")
+	if !strings.Contains(stdout, "Complete CLI Reference") || !strings.Contains(stdout, "-dump-config") {
+		t.Errorf("expected the shared flags help on the app's stdout, got %q", stdout)
+	}
+}
+
+func TestDumpConfig(t *testing.T) {
+	code, stdout, stderr := execute("run", "-dump-config", "-aimd-gamma=0.3", "-rng-seed=5")
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	var dumped map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &dumped); err != nil {
+		t.Fatalf("expected the configuration as JSON on stdout: %v\n%s", err, stdout)
+	}
+	if dumped["aimd-gamma"] != 0.3 || dumped["rng-seed"] != 5.0 {
+		t.Errorf("unexpected dumped settings: aimd-gamma %v, rng-seed %v", dumped["aimd-gamma"], dumped["rng-seed"])
+	}
+
+	path := filepath.Join(t.TempDir(), "resolved.json")
+	code, stdout, stderr = execute("run", "-dump-config", "-out-file="+path)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if data, err := os.ReadFile(path); err != nil || !strings.Contains(string(data), `"aimd-gamma"`) || stdout != "" {
+		t.Errorf("expected the configuration in %s and nothing on stdout, got %q (%v)", path, stdout, err)
+	}
+}
+
+func TestInterleave(t *testing.T) {
+	fs := config.NewParser().FlagSet()
+	args := []string{"data.json", "-graph", "-scenario", "full", "-aimd-gamma=0.1", "extra"}
+	want := []string{"-graph", "-scenario", "full", "-aimd-gamma=0.1", "--", "data.json", "extra"}
+	if got := interleave(fs, args); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	global, name, rest := splitCommand([]string{"-scenario", "full", "-graph", "replay", "data.json"})
+	if !reflect.DeepEqual(global, []string{"-scenario", "full", "-graph"}) || name != "replay" || !reflect.DeepEqual(rest, []string{"data.json"}) {
+		t.Errorf("unexpected split: %v %q %v", global, name, rest)
+	}
+}
+
+func TestDataSetCommand(t *testing.T) {
+	path := writeDataSet(t)
+	code, stdout, stderr := execute("dataset", path)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, "Blocks: 100 to 102") {
+		t.Errorf("unexpected summary:\n%s", stdout)
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	path := writeDataSet(t)
+	code, stdout, stderr := execute("replay", path, "-output=json")
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	var tables map[string][]map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &tables); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(tables["replay"]) != 1 || len(tables["blocks"]) != 3 {
+		t.Errorf("unexpected tables: %v", tables)
+	}
+	if !strings.Contains(stderr, "Loading blockchain dataset") {
+		t.Errorf("expected progress on stderr, got %q", stderr)
+	}
+
+	// Progress and notes go to stderr in text mode too
+	output := filepath.Join(t.TempDir(), "run.txt")
+	code, stdout, stderr = execute("run", "-scenario=full", "-out-file="+output)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if stdout != "" || !strings.Contains(stderr, "Results saved to "+output) {
+		t.Errorf("expected only notes on stderr, got stdout %q and stderr %q", stdout, stderr)
+	}
+
+	output = filepath.Join(t.TempDir(), "blocks.csv")
+	code, stdout, stderr = execute("run", "-scenario=full", "-output=csv", "-out-file="+output)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("expected nothing on stdout, got %q", stdout)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("expected one readable CSV table: %v\n%s", err, data)
+	}
+	if !strings.HasPrefix(string(data), "table,scenario,block,gas_used,") || records[len(records)-1][0] != "analysis" {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+
+	code, stdout, stderr = execute("montecarlo", "-scenario=full", "-mc-runs=3", "-rng-gaussian-noise=0.1", "-output=json")
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	tables = nil
+	if err := json.Unmarshal([]byte(stdout), &tables); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(tables["montecarlo"]) != len(analysis.MetricNames) || tables["montecarlo"][0]["runs"] != 3.0 {
+		t.Errorf("unexpected tables: %v", tables)
+	}
+}
+
+func TestOutFileKeptOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	output := filepath.Join(dir, "results.csv")
+	code, _, stderr := execute("run", "-scenario=full", "-output=csv", "-out-file="+output)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	want, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _, stderr = execute("replay", filepath.Join(dir, "missing.json"), "-output=csv", "-out-file="+output)
+	if code != ExitFailure {
+		t.Fatalf("expected the replay to fail, got %d: %s", code, stderr)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("a failed command changed the earlier results:\n%s", got)
+	}
+	if entries, err := os.ReadDir(dir); err != nil || len(entries) != 1 {
+		t.Errorf("expected only the results file to remain, got %v (%v)", entries, err)
+	}
+}
+
+func TestDeprecatedOutputFlags(t *testing.T) {
+	// -sweep-output without a format keeps writing CSV
+	output := filepath.Join(t.TempDir(), "sweep.csv")
+	code, stdout, stderr := execute("sweep", "-scenario=full", "-adjuster-type=aimd", "-sweep-param=aimd-gamma=0.1,0.2",
+		"-sweep-output="+output)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	if stdout != "" || !strings.Contains(stderr, "-sweep-output is deprecated, use -out-file") {
+		t.Errorf("expected only a deprecation warning and notes, got stdout %q and stderr %q", stdout, stderr)
+	}
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "point,aimd-gamma,case,error,") {
+		t.Errorf("unexpected CSV:\n%s", data)
+	}
+
+	code, stdout, stderr = execute("compare", "-scenario=full", "-adjuster-type=aimd,pid", "-compare-format=json")
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	var tables map[string][]map[string]interface{}
+	if err := json.Unmarshal([]byte(stdout), &tables); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, stdout)
+	}
+	if len(tables["comparison"]) != 2 || !strings.Contains(stderr, "-compare-format is deprecated, use -output") {
+		t.Errorf("unexpected tables %v or stderr %q", tables, stderr)
+	}
+}
+
+func TestReportCommand(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "report.md")
+	code, _, stderr := execute("report", "-scenario=stable", "-adjuster-type=aimd,pid", "-rng-seed=7", "-report-output="+output)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Randomizer seed: 7", "| Metric | aimd | pid |", "rng-seed = 7"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+}
+
+func TestManifestRerun(t *testing.T) {
+	path := writeDataSet(t)
+	dir := t.TempDir()
+	results := filepath.Join(dir, "comparison.csv")
+	manifestFile := filepath.Join(dir, "manifest.json")
+
+	// No -rng-seed: the manifest records the seed drawn from the clock
+	code, stdout, stderr := execute("compare", path, "-scenario=stable", "-adjuster-type=aimd,pid", "-rng-gaussian-noise=0.1",
+		"-output=csv", "-out-file="+results, "-manifest="+manifestFile)
+	if code != ExitOK {
+		t.Fatalf("expected success, got %d: %s", code, stderr)
+	}
+	m, err := manifest.LoadFromFile(manifestFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Inputs) != 1 || m.Inputs[0].Path != path || len(m.Outputs) != 1 || m.Outputs[0].Path != results {
+		t.Errorf("unexpected manifest files: inputs %v, outputs %v", m.Inputs, m.Outputs)
+	}
+
+	code, rerunStdout, stderr := execute("rerun", manifestFile)
+	if code != ExitOK {
+		t.Fatalf("expected the rerun to match, got %d: %s", code, stderr)
+	}
+	if !strings.Contains(stderr, "The rerun matches the manifest") {
+		t.Errorf("unexpected rerun status: %s", stderr)
+	}
+	if rerunStdout != strings.TrimSuffix(stdout, "Manifest saved to "+manifestFile+"\n") {
+		t.Errorf("rerun printed different results:\n%s\nwant:\n%s", rerunStdout, stdout)
+	}
+
+	m.Outputs[0].SHA256 = strings.Repeat("0", 64)
+	if err := manifest.SaveToFile(m, manifestFile); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, stderr := execute("rerun", manifestFile); code != ExitFailure || !strings.Contains(stderr, results+" differs") {
+		t.Errorf("expected a changed output to fail the rerun, got %d: %s", code, stderr)
+	}
+
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if code, _, stderr := execute("rerun", manifestFile); code != ExitFailure || !strings.Contains(stderr, path+" has changed") {
+		t.Errorf("expected a changed input to fail the rerun, got %d: %s", code, stderr)
+	}
+}
+
+func TestServeRun(t *testing.T) {
+	server := httptest.NewServer(newServeHandler(t.TempDir(), nil))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/run?adjuster-type=aimd,eip1559&scenario=stable")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	var rows []compare.Row
+	if err := json.NewDecoder(resp.Body).Decode(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Label != "aimd" || rows[1].Label != "eip1559" {
+		t.Errorf("unexpected rows: %+v", rows)
+	}
+
+	for _, query := range []string{"scenario=bogus", "config=secret.toml", "bogus=1"} {
+		resp, err := http.Get(server.URL + "/api/run?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, resp.StatusCode)
+		}
+	}
+}
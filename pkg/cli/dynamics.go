@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/dynamics"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/visualization"
+)
+
+// allAdjustersCommand returns a command that takes no arguments and analyzes every algorithm
+func allAdjustersCommand(name, summary string, run func(env *Env, cfg config.Config, adjusterTypes []simulator.AdjusterType) error) *Command {
+	return &Command{
+		Name:    name,
+		Summary: summary,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 0 {
+				return usageErrorf("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			return run(env, *cfg, simulator.NewAdjusterFactory().GetAvailableTypes())
+		},
+		Structured: true,
+	}
+}
+
+// responseCommand drives every algorithm with step, impulse and ramp inputs
+func responseCommand() *Command {
+	return allAdjustersCommand("response", "Measure step, impulse and ramp responses of every algorithm", runResponse)
+}
+
+// runResponse reports control metrics of the step, impulse and ramp responses
+func runResponse(env *Env, cfg config.Config, adjusterTypes []simulator.AdjusterType) error {
+	responses, err := dynamics.Run(cfg, adjusterTypes)
+	if err != nil {
+		return fmt.Errorf("response analysis failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		dynamics.PrintResponses(env.Out, responses, cfg)
+	} else if err := output.Write(env.Out, env.Format, dynamics.ResponsesTable(responses)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		if err := visualization.NewGenerator(env.Logger).GenerateResponseChart(responses, "response.html"); err != nil {
+			env.Warnf("failed to generate response chart: %v", err)
+		}
+	}
+	return nil
+}
+
+// frequencyCommand measures every algorithm's gain and phase under sinusoidal demand
+func frequencyCommand() *Command {
+	return allAdjustersCommand("frequency", "Measure the gain and phase of every algorithm under sinusoidal demand", runFrequency)
+}
+
+// runFrequency reports the frequency response of every algorithm
+func runFrequency(env *Env, cfg config.Config, adjusterTypes []simulator.AdjusterType) error {
+	responses, err := dynamics.RunFrequencies(cfg, adjusterTypes)
+	if err != nil {
+		return fmt.Errorf("frequency analysis failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		dynamics.PrintFrequencyResponses(env.Out, responses, cfg)
+	} else if err := output.Write(env.Out, env.Format, dynamics.FrequencyTable(responses)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		if err := visualization.NewGenerator(env.Logger).GenerateBodeChart(responses, "bode.html"); err != nil {
+			env.Warnf("failed to generate Bode chart: %v", err)
+		}
+	}
+	return nil
+}
+
+// equilibriumCommand finds every algorithm's long-run fee and utilization under a constant demand curve
+func equilibriumCommand() *Command {
+	return allAdjustersCommand("equilibrium", "Find the long-run fee and utilization of every algorithm under a demand curve", runEquilibrium)
+}
+
+// runEquilibrium reports the equilibrium of every algorithm
+func runEquilibrium(env *Env, cfg config.Config, adjusterTypes []simulator.AdjusterType) error {
+	curve, results, err := dynamics.RunEquilibria(cfg, adjusterTypes)
+	if err != nil {
+		return fmt.Errorf("equilibrium analysis failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		dynamics.PrintEquilibria(env.Out, results, cfg)
+	} else if err := output.Write(env.Out, env.Format, dynamics.EquilibriaTable(results)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		if err := visualization.NewGenerator(env.Logger).GenerateEquilibriumChart(curve, cfg.TargetBlockSize, results, "equilibrium.html"); err != nil {
+			env.Warnf("failed to generate equilibrium chart: %v", err)
+		}
+	}
+	return nil
+}
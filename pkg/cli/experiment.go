@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/experiment"
+	"github.com/brianbland/feemarketsim/pkg/output"
+)
+
+// runExperimentCommand runs every combination declared in an experiment file
+func runExperimentCommand() *Command {
+	var dir string
+	var workers int
+	return &Command{
+		Name:    "run-experiment",
+		Args:    "<experiment>",
+		Summary: "Run every combination of an experiment file's scenarios, variants, parameters and seeds",
+		Details: "The experiment file (JSON, or TOML with a .toml extension) lists the scenario sets, adjuster\n" +
+			"variants, parameter grid, seeds and metrics; the shared flags set everything else. Every run\n" +
+			"sees identical demand for a given scenario and seed. The results directory holds runs.csv,\n" +
+			"summary.csv and summary.md, a per-block trace of every run, and a chart per scenario.\n" +
+			"Example: feemarketsim run-experiment -results-dir=weekly weekly.toml",
+		Structured: true,
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&dir, "results-dir", "", "Results directory (default <experiment name>_results)")
+			fs.IntVar(&workers, "workers", 0, "Scenario and seed combinations run in parallel (0 = one per CPU)")
+		},
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected one experiment file, got %d arguments", len(args))
+			}
+
+			e, err := experiment.LoadFromFile(args[0])
+			if err != nil {
+				return err
+			}
+			runner, err := experiment.NewRunner(*cfg, *e, workers)
+			if err != nil {
+				return fmt.Errorf("experiment %s: %w", e.Name, err)
+			}
+			runner.SetLogger(env.Logger)
+			if dir == "" {
+				dir = slug(e.Name) + "_results"
+			}
+
+			fmt.Fprintf(env.Info, "Running experiment %s: %d runs on %d scenario and seed combinations\n",
+				e.Name, len(runner.Labels()), runner.Jobs())
+			results, err := runner.Run(env.Context, func(completed, total int) {
+				fmt.Fprintf(env.Stderr, "Progress: %d/%d scenario and seed combinations\n", completed, total)
+			})
+			if err != nil {
+				return fmt.Errorf("experiment %s failed: %w", e.Name, err)
+			}
+
+			files, err := experiment.SaveDir(dir, results, cfg.Simulation.LogScale, env.Logger)
+			if err != nil {
+				return err
+			}
+			for _, file := range files {
+				env.Wrote(file)
+			}
+			fmt.Fprintf(env.Info, "Experiment results saved to %s\n", dir)
+			return output.Write(env.Out, env.Format, experiment.SummaryTable(results))
+		},
+	}
+}
@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/adversarial"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/montecarlo"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/pareto"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/sensitivity"
+	"github.com/brianbland/feemarketsim/pkg/significance"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+	"github.com/brianbland/feemarketsim/pkg/tune"
+	"github.com/brianbland/feemarketsim/pkg/visualization"
+)
+
+// singleAdjusterCommand returns a command that takes no arguments and runs one adjuster
+// configuration at a time, rejecting a comma-separated -adjuster-type or -variant
+func singleAdjusterCommand(name, summary string, run func(env *Env, cfg config.Config) error) *Command {
+	return &Command{
+		Name:    name,
+		Summary: summary,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 0 {
+				return usageErrorf("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			if cfg.Simulation.IsComparison() {
+				return usageErrorf("the %s command runs one adjuster configuration at a time", name)
+			}
+			return run(env, *cfg)
+		},
+		Structured: true,
+	}
+}
+
+// sweepCommand evaluates a grid or random sample of parameter values
+func sweepCommand() *Command {
+	command := singleAdjusterCommand("sweep", "Evaluate a grid or random sample of parameter values and report every metric", runSweep)
+	command.Details = "Example: feemarketsim sweep -adjuster-type=aimd -sweep-param=aimd-gamma=0.05:0.5:0.05"
+	return command
+}
+
+// runSweep runs a parameter sweep over scenarios and datasets
+func runSweep(env *Env, cfg config.Config) error {
+	params, err := sweep.ParseParams(cfg.Simulation.Sweep.Params)
+	if err != nil {
+		return usageErrorf("sweep error: %v", err)
+	}
+
+	sw, err := sweep.New(cfg, params)
+	if err != nil {
+		return fmt.Errorf("sweep error: %w", err)
+	}
+
+	points := len(sw.Points())
+	fmt.Fprintf(env.Info, "Sweeping %s: %d points x %d cases (%s mode, seed %d)\n",
+		cfg.Simulation.AdjusterType, points, len(sw.Cases()), cfg.Simulation.Sweep.Mode, cfg.Simulation.Randomizer.Seed)
+
+	rows, err := sw.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("sweep failed: %w", err)
+	}
+
+	failed := 0
+	for _, row := range rows {
+		if row.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		env.Warnf("%d of %d evaluations failed (see the error column)", failed, len(rows))
+	}
+
+	return output.Write(env.Out, env.Format, sweep.Table(params, rows))
+}
+
+// tuneCommand searches for the parameters that minimize a weighted objective
+func tuneCommand() *Command {
+	command := singleAdjusterCommand("tune", "Search for the adjuster parameters that minimize a weighted objective", runTune)
+	command.Details = "Example: feemarketsim tune -adjuster-type=pid -tune-param=pid-kp=0.001:1 -dataset=base_data.json"
+	return command
+}
+
+// runTune tunes adjuster parameters against the objective
+func runTune(env *Env, cfg config.Config) error {
+	tuner, err := tune.NewTuner(cfg)
+	if err != nil {
+		return usageErrorf("tune error: %v", err)
+	}
+
+	fmt.Fprintf(env.Info, "Tuning %s with %s (up to %d evaluations, seed %d)\n",
+		cfg.Simulation.AdjusterType, cfg.Simulation.Tune.Method, cfg.Simulation.Tune.Iterations, cfg.Simulation.Randomizer.Seed)
+
+	result, err := tuner.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("tuning failed: %w", err)
+	}
+
+	if env.Format != output.Text {
+		return output.Write(env.Out, env.Format, tune.ParamsTable(result), tune.ObjectiveTable(result))
+	}
+	tune.PrintResult(env.Out, result)
+	return nil
+}
+
+// paretoCommand finds the configurations no other configuration beats on every metric
+func paretoCommand() *Command {
+	return singleAdjusterCommand("pareto", "Find the Pareto frontier of a sweep population over two or three metrics", runPareto)
+}
+
+// runPareto explores the multi-objective frontier over a sweep population
+func runPareto(env *Env, cfg config.Config) error {
+	objectives, err := pareto.ParseObjectives(cfg.Simulation.Pareto.Metrics)
+	if err != nil {
+		return usageErrorf("pareto error: %v", err)
+	}
+
+	params, err := sweep.ParseParams(cfg.Simulation.Sweep.Params)
+	if err != nil {
+		return usageErrorf("pareto error: %v", err)
+	}
+
+	sw, err := sweep.New(cfg, params)
+	if err != nil {
+		return fmt.Errorf("pareto error: %w", err)
+	}
+
+	fmt.Fprintf(env.Info, "Evaluating %d %s configurations x %d cases (%s mode, seed %d)\n",
+		len(sw.Points()), cfg.Simulation.AdjusterType, len(sw.Cases()), cfg.Simulation.Sweep.Mode, cfg.Simulation.Randomizer.Seed)
+
+	rows, err := sw.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("evaluation failed: %w", err)
+	}
+
+	result := pareto.Analyze(cfg.Simulation.AdjusterType, params, objectives, rows)
+	if env.Format == output.Text {
+		pareto.PrintResult(env.Out, result)
+	} else if err := output.Write(env.Out, env.Format, pareto.Table(result)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		filename := fmt.Sprintf("pareto_%s.html", cfg.Simulation.AdjusterType)
+		if err := visualization.NewGenerator(env.Logger).GenerateParetoChart(result, filename); err != nil {
+			env.Warnf("failed to generate Pareto chart: %v", err)
+		}
+	}
+	return nil
+}
+
+// sensitivityCommand ranks adjuster parameters by their global influence on the chosen metrics
+func sensitivityCommand() *Command {
+	return singleAdjusterCommand("sensitivity", "Rank adjuster parameters by their global influence (Morris or Sobol)", runSensitivity)
+}
+
+// runSensitivity runs the global sensitivity analysis
+func runSensitivity(env *Env, cfg config.Config) error {
+	metrics, err := sensitivity.ParseMetrics(cfg.Simulation.Sensitivity.Metrics)
+	if err != nil {
+		return usageErrorf("sensitivity error: %v", err)
+	}
+
+	var params []sweep.Param
+	if len(cfg.Simulation.Sensitivity.Params) > 0 {
+		params, err = sweep.ParseParams(cfg.Simulation.Sensitivity.Params)
+	} else {
+		params, err = sensitivity.DefaultParams(cfg.Simulation.AdjusterType)
+	}
+	if err != nil {
+		return usageErrorf("sensitivity error: %v", err)
+	}
+
+	analyzer, err := sensitivity.NewAnalyzer(cfg, params, metrics)
+	if err != nil {
+		return fmt.Errorf("sensitivity error: %w", err)
+	}
+
+	fmt.Fprintf(env.Info, "Analyzing %d %s parameters with %s: %d evaluations x %d cases (seed %d)\n",
+		len(params), cfg.Simulation.AdjusterType, cfg.Simulation.Sensitivity.Method,
+		analyzer.Evaluations(), len(analyzer.Cases()), cfg.Simulation.Randomizer.Seed)
+
+	result, err := analyzer.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("analysis failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		sensitivity.PrintResult(env.Out, result)
+	} else if err := output.Write(env.Out, env.Format, sensitivity.Table(result)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		filename := fmt.Sprintf("sensitivity_%s_%s.html", result.Method, cfg.Simulation.AdjusterType)
+		if err := visualization.NewGenerator(env.Logger).GenerateSensitivityChart(result, filename); err != nil {
+			env.Warnf("failed to generate sensitivity chart: %v", err)
+		}
+	}
+	return nil
+}
+
+// significanceCommand runs paired significance tests between two adjusters
+func significanceCommand() *Command {
+	return singleAdjusterCommand("significance", "Test whether two adjusters differ significantly on paired seeds and dataset segments", runSignificance)
+}
+
+// runSignificance compares -adjuster-type with -sig-against
+func runSignificance(env *Env, cfg config.Config) error {
+	comparer, err := significance.NewComparer(cfg)
+	if err != nil {
+		return usageErrorf("significance error: %v", err)
+	}
+
+	randomizerCfg := cfg.Simulation.Randomizer
+	if cfg.Simulation.Scenario != "none" && randomizerCfg.GaussianNoise == 0 && randomizerCfg.BurstProbability == 0 {
+		env.Warnf("no randomizer configured (-rng-gaussian-noise, -rng-burst-probability); every seed will give the same pair")
+	}
+
+	fmt.Fprintf(env.Info, "Comparing %s with %s: %d seeds starting at %d, %d dataset segments\n",
+		cfg.Simulation.AdjusterType, cfg.Simulation.Significance.Against, cfg.Simulation.MonteCarlo.Runs,
+		randomizerCfg.Seed, cfg.Simulation.Significance.Segments*len(cfg.Simulation.DataSets))
+
+	result, err := comparer.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("significance test failed: %w", err)
+	}
+
+	if env.Format != output.Text {
+		return output.Write(env.Out, env.Format, significance.ResultTable(result))
+	}
+	significance.PrintResult(env.Out, result)
+	return nil
+}
+
+// monteCarloCommand runs many seeds and aggregates the metrics
+func monteCarloCommand() *Command {
+	return singleAdjusterCommand("montecarlo", "Run many randomizer seeds and report metric distributions", runMonteCarlo)
+}
+
+// runMonteCarlo runs a multi-seed batch with aggregated statistics
+func runMonteCarlo(env *Env, cfg config.Config) error {
+	randomizerCfg := cfg.Simulation.Randomizer
+	if randomizerCfg.GaussianNoise == 0 && randomizerCfg.BurstProbability == 0 {
+		env.Warnf("no randomizer configured (-rng-gaussian-noise, -rng-burst-probability); every run will be identical")
+	}
+
+	runner := montecarlo.NewRunner(cfg)
+	fmt.Fprintf(env.Info, "Running Monte Carlo batch: %s, %d runs starting at seed %d\n",
+		cfg.Simulation.AdjusterType, cfg.Simulation.MonteCarlo.Runs, randomizerCfg.Seed)
+
+	report, err := runner.Run(env.Context)
+	if err != nil {
+		return fmt.Errorf("monte carlo run failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		montecarlo.PrintReport(env.Out, report)
+	} else if err := output.Write(env.Out, env.Format, montecarlo.ReportTable(report)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		chartGenerator := visualization.NewGenerator(env.Logger)
+		for _, summary := range report.Scenarios {
+			filename := fmt.Sprintf("montecarlo_%s_%s.html", cfg.Simulation.AdjusterType, slug(summary.ScenarioName))
+			if err := chartGenerator.GenerateFanChart(summary, cfg.Simulation.AdjusterType, filename); err != nil {
+				env.Warnf("failed to generate fan chart for %s: %v", summary.ScenarioName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// attackCommand measures how far an adversarial block producer can move fees
+func attackCommand() *Command {
+	return &Command{
+		Name:    "attack",
+		Summary: "Measure block-stuffing and fee-draining attacks against every algorithm",
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 0 {
+				return usageErrorf("unexpected arguments: %s", strings.Join(args, " "))
+			}
+			return runAttack(env, *cfg)
+		},
+		Structured: true,
+	}
+}
+
+// runAttack runs adversarial block-stuffing and fee manipulation analysis
+func runAttack(env *Env, cfg config.Config) error {
+	scenarioGenerator := scenarios.NewGenerator(cfg.Simulation)
+	attacker := adversarial.NewAttacker(cfg)
+	chartGenerator := visualization.NewGenerator(env.Logger)
+
+	scenariosToRun, err := scenarioGenerator.Select(cfg)
+	if err != nil {
+		return fmt.Errorf("scenario error: %w", err)
+	}
+
+	fmt.Fprintf(env.Info, "Running adversarial analysis: strategy=%s, attacker share=%.1f%%, seed=%d\n",
+		cfg.Simulation.Attack.Strategy, cfg.Simulation.Attack.Share*100, cfg.Simulation.Randomizer.Seed)
+
+	table := adversarial.NewResultsTable()
+	for _, scenario := range scenariosToRun {
+		results, err := attacker.RunAll(scenario)
+		if err != nil {
+			return fmt.Errorf("attack simulation failed: %w", err)
+		}
+
+		if env.Format == output.Text {
+			adversarial.PrintResults(env.Out, results)
+		} else {
+			adversarial.AppendResults(table, results)
+		}
+
+		if cfg.Simulation.EnableGraphs {
+			filename := fmt.Sprintf("attack_%s_%s.html", cfg.Simulation.Attack.Strategy, slug(scenario.Name))
+			if err := chartGenerator.GenerateAttackChart(results, filename); err != nil {
+				env.Warnf("failed to generate attack chart for %s: %v", scenario.Name, err)
+			}
+		}
+	}
+
+	if env.Format != output.Text {
+		return output.Write(env.Out, env.Format, table)
+	}
+	return nil
+}
+
+// searchCommand searches for the demand sequence that hurts an adjuster most
+func searchCommand() *Command {
+	return singleAdjusterCommand("search", "Search for the worst-case demand sequence and save it as a scenario", runSearch)
+}
+
+// runSearch runs the worst-case demand sequence search
+func runSearch(env *Env, cfg config.Config) error {
+	adjusterType, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
+	if err != nil {
+		return usageErrorf("invalid adjuster type: %v", err)
+	}
+
+	searcher, err := adversarial.NewSearcher(cfg, adjusterType)
+	if err != nil {
+		return fmt.Errorf("failed to create searcher: %w", err)
+	}
+
+	searchCfg := cfg.Simulation.Search
+	fmt.Fprintf(env.Info, "Searching for worst-case %s under %s: %s search, %d iterations, %d blocks, seed=%d\n",
+		searchCfg.Metric, adjusterType, searchCfg.Method, searchCfg.Iterations, searchCfg.Blocks, cfg.Simulation.Randomizer.Seed)
+
+	result, err := searcher.Run()
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		adversarial.PrintSearchResult(env.Out, result, cfg)
+	} else if err := output.Write(env.Out, env.Format, adversarial.SearchTable(result, cfg)); err != nil {
+		return err
+	}
+
+	if err := scenarios.SaveToFile(result.Scenario, searchCfg.Output); err != nil {
+		return fmt.Errorf("failed to save scenario: %w", err)
+	}
+	env.Wrote(searchCfg.Output)
+	fmt.Fprintf(env.Info, "\nWorst-case scenario saved to %s\n", searchCfg.Output)
+	fmt.Fprintf(env.Info, "Replay it with: feemarketsim run -scenario-file=%s -adjuster-type=%s\n", searchCfg.Output, adjusterType)
+
+	if cfg.Simulation.EnableGraphs {
+		chartGenerator := visualization.NewGenerator(env.Logger)
+		base := fmt.Sprintf("search_%s_%s", searchCfg.Metric, adjusterType)
+		if err := chartGenerator.GenerateSearchChart(result, base+".html"); err != nil {
+			env.Warnf("failed to generate search chart: %v", err)
+		}
+		if cfg.Simulation.LogScale {
+			err = chartGenerator.GenerateChartWithLogScale(cfg, result.Scenario, base+"_fees.html")
+		} else {
+			err = chartGenerator.GenerateChart(cfg, result.Scenario, base+"_fees.html")
+		}
+		if err != nil {
+			env.Warnf("failed to generate fee chart: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/report"
+)
+
+// reportCommand runs the selected scenarios and writes the results to one document
+func reportCommand() *Command {
+	output := "report.md"
+	assets := ""
+	return &Command{
+		Name:    "report",
+		Args:    "[dataset]...",
+		Summary: "Run the selected scenarios and datasets and write the configuration, metrics and charts to one report",
+		Details: "A comma-separated -adjuster-type or -variant puts every run side by side in the tables and charts.\n" +
+			"Datasets, given as arguments or with -dataset, are replayed and their accuracy reported.\n" +
+			"A -report-output ending in .html writes one page with the charts; Markdown links to them.\n" +
+			"Charts load echarts from the go-echarts CDN unless -report-assets names a directory holding echarts.min.js.\n" +
+			"Example: feemarketsim report -adjuster-type=aimd,eip1559 -report-output=report.html base_data.json",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&output, "report-output", output, "Report file, HTML (.html) or Markdown")
+			fs.StringVar(&assets, "report-assets", assets, "Directory of chart scripts to use instead of the CDN (HTML inlines them)")
+		},
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			cfg.Simulation.DataSets = append(cfg.Simulation.DataSets, args...)
+
+			r, err := report.Build(*cfg, env.Logger)
+			if err != nil {
+				return fmt.Errorf("report failed: %w", err)
+			}
+			r.AssetsDir = assets
+			if err := report.SaveToFile(output, r); err != nil {
+				return err
+			}
+			fmt.Fprintf(env.Info, "Report (%d scenarios, %d datasets) saved to %s\n", len(r.Scenarios), len(r.DataSets), output)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/manifest"
+	"github.com/brianbland/feemarketsim/pkg/version"
+)
+
+// rerunCommand runs a command again from its manifest and checks that it writes the same outputs
+func rerunCommand() *Command {
+	return &Command{
+		Name:    "rerun",
+		Args:    "<manifest>",
+		Summary: "Run a command again from the manifest written with -manifest and check that the outputs match",
+		Details: "The command runs with the recorded configuration, seed, flags and arguments, and writes its\n" +
+			"outputs to the same paths. The rerun fails if an input file changed or an output differs.\n" +
+			"Example: feemarketsim run -scenario=all -out-file=run.csv -manifest=run.json, then feemarketsim rerun run.json",
+		NoManifest: true,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected one manifest file, got %d arguments", len(args))
+			}
+
+			m, err := manifest.LoadFromFile(args[0])
+			if err != nil {
+				return err
+			}
+			command := lookup(commands(), m.Command)
+			if command == nil || command.NoManifest {
+				return fmt.Errorf("manifest %s: the %q command cannot be rerun", args[0], m.Command)
+			}
+			if problems := manifest.Verify(m.Inputs); len(problems) > 0 {
+				return fmt.Errorf("inputs differ from the manifest: %s", strings.Join(problems, "; "))
+			}
+			if current := version.Version(); current != m.Version {
+				env.Warnf("the manifest was written by version %s, rerunning with %s", m.Version, current)
+			}
+
+			commandArgs, err := config.FlagArgs(m.Config)
+			if err != nil {
+				return fmt.Errorf("manifest %s: %w", args[0], err)
+			}
+			names := make([]string, 0, len(m.Flags))
+			for name := range m.Flags {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				commandArgs = append(commandArgs, "-"+name+"="+m.Flags[name])
+			}
+			if len(m.Args) > 0 {
+				commandArgs = append(append(commandArgs, "--"), m.Args...)
+			}
+
+			// Status goes to stderr so stdout holds exactly the results of the rerun command
+			fmt.Fprintf(env.Stderr, "Rerunning %s (seed %d) from %s\n", m.Command, m.Seed, args[0])
+			rerun, err := runAndRecord(env, command, commandArgs, true)
+			if err != nil {
+				return err
+			}
+
+			differences := manifest.Diff(m.Outputs, rerun.Outputs)
+			for _, difference := range differences {
+				fmt.Fprintf(env.Stderr, "❌ %s\n", difference)
+			}
+			if len(differences) > 0 {
+				return errors.New("the rerun does not match the manifest")
+			}
+			fmt.Fprintf(env.Stderr, "✅ The rerun matches the manifest (%d outputs)\n", len(m.Outputs))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,135 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+)
+
+// serveCommand serves generated charts and reports, and runs simulations over HTTP
+func serveCommand() *Command {
+	var addr, dir string
+	return &Command{
+		Name:       "serve",
+		Summary:    "Serve generated charts and reports, and run simulations over HTTP",
+		NoManifest: true,
+		Details: "GET /api/run runs the scenarios with the configuration given as query parameters named\n" +
+			"after the flags, e.g. /api/run?adjuster-type=aimd,pid&scenario=full, and returns the metrics\n" +
+			"of every run as JSON. Every other path serves the files in -dir.",
+		Flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+			fs.StringVar(&dir, "dir", ".", "Directory of charts and reports to serve")
+		},
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 0 {
+				return usageErrorf("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			server := &http.Server{
+				Addr:              addr,
+				Handler:           newServeHandler(dir, env.Logger),
+				ReadHeaderTimeout: 10 * time.Second,
+			}
+
+			// The server stops when the command's context is cancelled, e.g. on interrupt
+			shutdownErr := make(chan error, 1)
+			go func() {
+				<-env.Context.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				shutdownErr <- server.Shutdown(ctx)
+			}()
+
+			fmt.Fprintf(env.Stderr, "Serving %s on http://%s\n", dir, addr)
+			if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return <-shutdownErr
+		},
+	}
+}
+
+// newServeHandler returns the handler of the serve command
+func newServeHandler(dir string, logger *slog.Logger) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/run", func(w http.ResponseWriter, r *http.Request) {
+		handleRunRequest(w, r, logger)
+	})
+	mux.Handle("/", http.FileServer(http.Dir(dir)))
+	return mux
+}
+
+// handleRunRequest runs the scenarios configured by the query parameters and returns one
+// compare.Row per run and scenario. The simulations report their diagnostics to logger.
+func handleRunRequest(w http.ResponseWriter, r *http.Request, logger *slog.Logger) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	cfg, err := queryConfig(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	runs, err := compare.Runs(*cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	scenariosToRun, err := scenarios.NewGenerator(cfg.Simulation).Select(*cfg)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rows := []compare.Row{}
+	for _, scenario := range scenariosToRun {
+		comparison, err := compare.CompareScenario(runs, scenario, logger)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rows = append(rows, compare.ScenarioRows(comparison)...)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := compare.WriteJSON(w, rows); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// queryConfig parses query parameters as flags, -name=value for each parameter. Files cannot be
+// read through the API, so configuration and scenario files are rejected.
+func queryConfig(r *http.Request) (*config.Config, error) {
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []string
+	for _, name := range names {
+		switch name {
+		case "config", "profile", "dump-config", "help", "scenario-file", "dataset", "output", "out-file", "manifest",
+			"sweep-format", "sweep-output", "compare-format", "compare-output":
+			return nil, fmt.Errorf("parameter %s is not available through the API", name)
+		}
+		for _, value := range query[name] {
+			args = append(args, "-"+name+"="+value)
+		}
+	}
+	return config.NewParser().Parse(args)
+}
@@ -0,0 +1,472 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/visualization"
+)
+
+// slug turns a scenario name into a file name part, e.g. Full Blocks -> full_blocks
+func slug(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, " ", "_"))
+}
+
+// runCommand simulates the selected scenarios with one adjuster, or compares several
+func runCommand() *Command {
+	return &Command{
+		Name:    "run",
+		Summary: "Simulate the built-in or file scenarios and analyze the results",
+		Details: "A comma-separated -adjuster-type or -variant runs a side-by-side comparison instead.\n" +
+			"With -output=json, csv or markdown the results are the per-block and analysis tables.",
+		Structured: true,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 0 {
+				return usageErrorf("unexpected arguments: %s", strings.Join(args, " "))
+			}
+
+			printConfigSummary(env.Info, *cfg)
+
+			// Several algorithms or variants run side by side instead of one at a time
+			if cfg.Simulation.IsComparison() {
+				return runComparison(env, *cfg)
+			}
+			return runSimulation(env, *cfg)
+		},
+	}
+}
+
+// compareCommand compares algorithms or variants on the same scenarios or dataset
+func compareCommand() *Command {
+	return &Command{
+		Name:    "compare",
+		Args:    "[dataset]",
+		Summary: "Compare algorithms or variants side by side on identical demand",
+		Details: "Runs the algorithms of a comma-separated -adjuster-type, or each -variant, on the same scenarios,\n" +
+			"or on a dataset when one is given. Without either, every algorithm is compared.",
+		Structured: true,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) > 1 {
+				return usageErrorf("expected at most one dataset, got %d arguments", len(args))
+			}
+
+			if !cfg.Simulation.IsComparison() {
+				cfg.Simulation.AdjusterTypes = nil
+				for _, adjusterType := range simulator.NewAdjusterFactory().GetAvailableTypes() {
+					cfg.Simulation.AdjusterTypes = append(cfg.Simulation.AdjusterTypes, string(adjusterType))
+				}
+				cfg.Simulation.AdjusterType = cfg.Simulation.AdjusterTypes[0]
+			}
+
+			if len(args) == 1 {
+				dataset, err := loadDataSet(env, args[0])
+				if err != nil {
+					return err
+				}
+				return compareDataSet(env, *cfg, dataset)
+			}
+			printConfigSummary(env.Info, *cfg)
+			return runComparison(env, *cfg)
+		},
+	}
+}
+
+// replayCommand simulates an adjuster against a recorded blockchain dataset
+func replayCommand() *Command {
+	return &Command{
+		Name:    "replay",
+		Aliases: []string{"simulate-base"},
+		Args:    "<dataset>",
+		Summary: "Replay a fetched blockchain dataset and compare with the actual base fees",
+		Details: "Example: feemarketsim replay base_data.json -graph -aimd-gamma=0.1\n" +
+			"With -output=json, csv or markdown the results are the summary and per-block tables.",
+		Structured: true,
+		Run: func(env *Env, cfg *config.Config, args []string) error {
+			if len(args) != 1 {
+				return usageErrorf("expected one dataset file, got %d arguments", len(args))
+			}
+
+			dataset, err := loadDataSet(env, args[0])
+			if err != nil {
+				return err
+			}
+
+			if cfg.Simulation.IsComparison() {
+				return compareDataSet(env, *cfg, dataset)
+			}
+			return replayDataSet(env, *cfg, dataset)
+		},
+	}
+}
+
+// runSimulation runs every selected scenario with one adjuster and prints the analysis
+func runSimulation(env *Env, cfg config.Config) error {
+	scenarioGenerator := scenarios.NewGenerator(cfg.Simulation)
+	analyzer := analysis.NewAnalyzer(cfg)
+	chartGenerator := visualization.NewGenerator(env.Logger)
+
+	// Determine which scenarios to run
+	scenariosToRun, err := scenarioGenerator.Select(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Run simulations; other formats collect every scenario's blocks in one table
+	blocks := newBlocksTable()
+	for _, scenario := range scenariosToRun {
+		if env.Format == output.Text {
+			err = runBasicSimulation(env.Out, cfg, scenario, env.Logger)
+		} else {
+			err = appendBlocks(blocks, cfg, scenario, env.Logger)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Generate charts if requested
+		if cfg.Simulation.EnableGraphs {
+			if cfg.Simulation.LogScale {
+				chartGenerator.GenerateChartForScenarioWithLogScale(cfg, scenario)
+			} else {
+				chartGenerator.GenerateChartForScenario(cfg, scenario)
+			}
+		}
+	}
+
+	// Run detailed analysis
+	var analysisResults []analysis.Result
+	for _, scenario := range scenariosToRun {
+		result := analyzer.RunDetailedAnalysis(scenario)
+		analysisResults = append(analysisResults, result)
+	}
+
+	// Print comprehensive analysis
+	if env.Format == output.Text {
+		analysis.PrintResults(env.Out, analysisResults)
+	} else if err := output.Write(env.Out, env.Format, blocks, analysis.ResultsTable(analysisResults)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		fmt.Fprintf(env.Info, "\nVisualization files generated:\n")
+		for _, scenario := range scenariosToRun {
+			scaleType := "linear"
+			suffix := ""
+			if cfg.Simulation.LogScale {
+				scaleType = "logarithmic"
+				suffix = "_log"
+			}
+			filename := fmt.Sprintf("chart_%s%s.html", slug(scenario.Name), suffix)
+			fmt.Fprintf(env.Info, "  - %s (AIMD fee evolution - %s scale)\n", filename, scaleType)
+		}
+	}
+	return nil
+}
+
+// printConfigSummary prints the configuration being used
+func printConfigSummary(w io.Writer, cfg config.Config) {
+	simCfg := cfg.Simulation
+	adjusterCfg := cfg.Adjuster
+
+	fmt.Fprintf(w, "Running Fee Market Simulation with configuration:\n")
+	adjusterTypes := simCfg.AdjusterTypeList()
+	if variants, err := config.ParseVariants(simCfg.Compare.Variants); err == nil && len(variants) > 0 {
+		// Shared parameters are shown for every algorithm a variant uses; overrides follow the name
+		adjusterTypes = nil
+		seen := make(map[string]bool)
+		fmt.Fprintf(w, "  Variants:\n")
+		for _, variant := range variants {
+			fmt.Fprintf(w, "    %s: %s\n", variant.Name, strings.TrimSpace(variant.AdjusterType+" "+variant.Flags()))
+			if !seen[variant.AdjusterType] {
+				seen[variant.AdjusterType] = true
+				adjusterTypes = append(adjusterTypes, variant.AdjusterType)
+			}
+		}
+	} else {
+		fmt.Fprintf(w, "  Adjuster Type: %s\n", strings.Join(adjusterTypes, ", "))
+	}
+
+	// Core parameters (always shown)
+	fmt.Fprintf(w, "  Target Block Size: %d gas (%.1f M)\n", cfg.TargetBlockSize, float64(cfg.TargetBlockSize)/1e6)
+	fmt.Fprintf(w, "  Burst Multiplier: %.1fx (%.1f M gas max)\n", cfg.BurstMultiplier,
+		float64(cfg.TargetBlockSize)*cfg.BurstMultiplier/1e6)
+	fmt.Fprintf(w, "  Initial Base Fee: %s\n", config.FormatGwei(float64(cfg.InitialBaseFee)))
+	fmt.Fprintf(w, "  Min Base Fee: %s\n", config.FormatGwei(float64(cfg.MinBaseFee)))
+	if simCfg.Randomizer.GaussianNoise > 0 || simCfg.Randomizer.BurstProbability > 0 {
+		fmt.Fprintf(w, "  Randomizer Seed: %d\n", simCfg.Randomizer.Seed)
+		if simCfg.Randomizer.GaussianNoise > 0 {
+			fmt.Fprintf(w, "  Gaussian Noise: %.1f%%\n", simCfg.Randomizer.GaussianNoise*100)
+		}
+		if simCfg.Randomizer.BurstProbability > 0 {
+			fmt.Fprintf(w, "  Burst Probability: %.1f%%\n", simCfg.Randomizer.BurstProbability*100)
+			fmt.Fprintf(w, "  Burst Duration Min: %d blocks\n", simCfg.Randomizer.BurstDurationMin)
+			fmt.Fprintf(w, "  Burst Duration Max: %d blocks\n", simCfg.Randomizer.BurstDurationMax)
+			fmt.Fprintf(w, "  Burst Intensity: %.1f\n", simCfg.Randomizer.BurstIntensity)
+		}
+	}
+
+	// Algorithm-specific parameters
+	for _, adjusterType := range adjusterTypes {
+		switch adjusterType {
+		case "aimd":
+			fmt.Fprintf(w, "  Window Size: %d blocks\n", cfg.WindowSize)
+			fmt.Fprintf(w, "  Gamma: %.3f\n", adjusterCfg.AIMD.Gamma)
+			fmt.Fprintf(w, "  Learning Rate Range: %.6f - %.6f\n", adjusterCfg.AIMD.MinLearningRate, adjusterCfg.AIMD.MaxLearningRate)
+			fmt.Fprintf(w, "  Alpha: %.6f, Beta: %.6f\n", adjusterCfg.AIMD.Alpha, adjusterCfg.AIMD.Beta)
+			fmt.Fprintf(w, "  Delta: %.9f\n", adjusterCfg.AIMD.Delta)
+			fmt.Fprintf(w, "  Initial Learning Rate: %.6f\n", adjusterCfg.AIMD.InitialLearningRate)
+
+		case "eip1559", "eip-1559":
+			fmt.Fprintf(w, "  Max Fee Change: %.1f%% per block\n", adjusterCfg.EIP1559.MaxFeeChange*100)
+
+		case "pid":
+			fmt.Fprintf(w, "  Window Size: %d blocks\n", cfg.WindowSize)
+			fmt.Fprintf(w, "  PID Gains: Kp=%.3f, Ki=%.3f, Kd=%.3f\n", adjusterCfg.PID.Kp, adjusterCfg.PID.Ki, adjusterCfg.PID.Kd)
+			fmt.Fprintf(w, "  Max Fee Change: %.1f%% per block\n", adjusterCfg.PID.MaxFeeChange*100)
+			fmt.Fprintf(w, "  Integral Limits: %.1f to %.1f\n", adjusterCfg.PID.MinIntegral, adjusterCfg.PID.MaxIntegral)
+
+		}
+	}
+
+	fmt.Fprintf(w, "  Scenario: %s\n", simCfg.Scenario)
+	fmt.Fprintf(w, "  Generate Charts: %t\n", simCfg.EnableGraphs)
+	if simCfg.EnableGraphs {
+		scaleType := "linear"
+		if simCfg.LogScale {
+			scaleType = "logarithmic"
+		}
+		fmt.Fprintf(w, "  Chart Scale: %s\n", scaleType)
+	}
+	fmt.Fprintln(w)
+}
+
+// runBasicSimulation runs a basic simulation and prints the per-block table
+func runBasicSimulation(w io.Writer, cfg config.Config, scenario scenarios.Scenario, logger *slog.Logger) error {
+	simCfg := cfg.Simulation
+
+	fmt.Fprintf(w, "\n=== Simulation: %s ===\n", scenario.Name)
+	fmt.Fprintf(w, "Description: %s\n", scenario.Description)
+	fmt.Fprintf(w, "Adjuster Type: %s\n", simCfg.AdjusterType)
+	fmt.Fprintf(w, "Burst Capacity: %.1fx target (%.0f M gas max)\n",
+		cfg.BurstMultiplier, float64(cfg.TargetBlockSize)*cfg.BurstMultiplier/1e6)
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Block\tGas Used\tTarget %\tBurst %\tBase Fee\tLearning Rate\tTarget Util")
+
+	err := simulateBlocks(cfg, scenario, logger, func(block int, gasUsed uint64, state simulator.State) {
+		targetPercent := float64(gasUsed) / float64(cfg.TargetBlockSize) * 100
+		burstPercent := state.BurstUtilization * 100
+
+		fmt.Fprintf(tw, "%d\t%d\t%.1f%%\t%.1f%%\t%s\t%.6f\t%.3f\n",
+			block, gasUsed, targetPercent, burstPercent, config.FormatGwei(float64(state.BaseFee)),
+			state.LearningRate, state.TargetUtilization)
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// newBlocksTable returns the table appendBlocks fills
+func newBlocksTable() *output.Table {
+	return output.NewTable("blocks", "Per-Block Simulation", "scenario", "block", "gas_used", "target_percent",
+		"burst_percent", "base_fee", "learning_rate", "target_utilization")
+}
+
+// appendBlocks runs a basic simulation and adds a row per block to the table
+func appendBlocks(table *output.Table, cfg config.Config, scenario scenarios.Scenario, logger *slog.Logger) error {
+	return simulateBlocks(cfg, scenario, logger, func(block int, gasUsed uint64, state simulator.State) {
+		table.Append(scenario.Name, block, gasUsed, float64(gasUsed)/float64(cfg.TargetBlockSize)*100,
+			state.BurstUtilization*100, state.BaseFee, state.LearningRate, state.TargetUtilization)
+	})
+}
+
+// simulateBlocks runs the configured adjuster over a scenario and calls visit with the state after
+// each block; the adjuster reports its internals to logger
+func simulateBlocks(cfg config.Config, scenario scenarios.Scenario, logger *slog.Logger, visit func(block int, gasUsed uint64, state simulator.State)) error {
+	// Parse adjuster type and create adjuster
+	adjusterType, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
+	if err != nil {
+		return fmt.Errorf("invalid adjuster type: %w", err)
+	}
+
+	factory := simulator.NewAdjusterFactory()
+	factory.SetLogger(logger)
+	adjuster, err := factory.CreateAdjusterWithConfigs(adjusterType, &cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create adjuster: %w", err)
+	}
+
+	for i, gasUsed := range scenario.Blocks {
+		adjuster.ProcessBlock(gasUsed)
+		visit(i+1, gasUsed, adjuster.GetCurrentState())
+	}
+	return nil
+}
+
+// runComparison runs every selected algorithm on the same scenarios and compares them
+func runComparison(env *Env, cfg config.Config) error {
+	runs, err := compare.Runs(cfg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	// Generate (and randomize) each scenario once so every algorithm sees identical demand
+	scenariosToRun, err := scenarios.NewGenerator(cfg.Simulation).Select(cfg)
+	if err != nil {
+		return err
+	}
+
+	chartGenerator := visualization.NewGenerator(env.Logger)
+	var filenames []string
+	var rows []compare.Row
+	for _, scenario := range scenariosToRun {
+		comparison, err := compare.CompareScenario(runs, scenario, env.Logger)
+		if err != nil {
+			return fmt.Errorf("comparison failed for %s: %w", scenario.Name, err)
+		}
+		if env.Format == output.Text {
+			compare.PrintScenarioComparison(env.Out, comparison)
+		}
+		rows = append(rows, compare.ScenarioRows(comparison)...)
+
+		if cfg.Simulation.EnableGraphs {
+			filename := fmt.Sprintf("comparison_%s_%s.html", slug(scenario.Name), strings.Join(comparison.Labels, "_"))
+			if err := chartGenerator.GenerateComparisonChart(comparison, cfg.Simulation.LogScale, filename); err != nil {
+				env.Warnf("failed to generate comparison chart for %s: %v", scenario.Name, err)
+				continue
+			}
+			filenames = append(filenames, filename)
+		}
+	}
+
+	if env.Format != output.Text {
+		if err := output.Write(env.Out, env.Format, compare.Table(rows)); err != nil {
+			return err
+		}
+	}
+
+	if len(filenames) > 0 {
+		fmt.Fprintf(env.Info, "\nVisualization files generated:\n")
+		for _, filename := range filenames {
+			fmt.Fprintf(env.Info, "  - %s (Base fee of every algorithm)\n", filename)
+		}
+	}
+	return nil
+}
+
+// loadDataSet loads and validates a blockchain dataset
+func loadDataSet(env *Env, filename string) (*blockchain.DataSet, error) {
+	fmt.Fprintf(env.Info, "Loading blockchain dataset from %s...\n", filename)
+	dataset, err := blockchain.LoadDataSetFromFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dataset: %w", err)
+	}
+	if err := blockchain.ValidateDataSet(dataset); err != nil {
+		return nil, fmt.Errorf("dataset validation failed: %w", err)
+	}
+	fmt.Fprintf(env.Info, "✅ Loaded valid dataset with %d blocks\n", len(dataset.Blocks))
+	return dataset, nil
+}
+
+// replayDataSet simulates one adjuster against a dataset and compares it with the actual base fees
+func replayDataSet(env *Env, cfg config.Config, dataset *blockchain.DataSet) error {
+	adjusterType, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
+	if err != nil {
+		return usageErrorf("invalid adjuster type: %v", err)
+	}
+
+	// Create blockchain simulator and chart generator
+	blockchainSim := blockchain.NewSimulator(cfg, adjusterType)
+	blockchainSim.SetLogger(env.Logger)
+	chartGenerator := visualization.NewGenerator(env.Logger)
+
+	// Run simulation against the dataset; the per-block data also feeds the blocks table
+	collect := cfg.Simulation.EnableGraphs || env.Format != output.Text
+	simResult, analysisResult, err := blockchainSim.SimulateAgainstDataSetWithOptions(dataset, collect)
+	if err != nil {
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+
+	if env.Format == output.Text {
+		// Print results
+		blockchain.PrintSimulationResults(env.Out, simResult, analysisResult)
+
+		// Print comparison with actual Base fees
+		blockchainSim.CompareWithActualBaseFees(env.Out, dataset, simResult)
+	} else {
+		tables := []*output.Table{
+			blockchain.SimulationResultTable(simResult, analysisResult),
+			blockchain.BlocksTable(simResult.ComparisonData),
+		}
+		if err := output.Write(env.Out, env.Format, tables...); err != nil {
+			return err
+		}
+	}
+
+	if !cfg.Simulation.EnableGraphs {
+		return nil
+	}
+
+	filename := fmt.Sprintf("base_comparison_%d_%d.html", dataset.StartBlock, dataset.EndBlock)
+	if cfg.Simulation.LogScale {
+		err = chartGenerator.GenerateBaseComparisonChartWithLogScale(cfg, dataset, simResult, filename)
+	} else {
+		err = chartGenerator.GenerateBaseComparisonChart(cfg, dataset, simResult, filename)
+	}
+	if err != nil {
+		env.Warnf("failed to generate comparison charts: %v", err)
+		return nil
+	}
+
+	fmt.Fprintf(env.Info, "\nVisualization files generated:\n")
+	scaleType := "linear"
+	if cfg.Simulation.LogScale {
+		scaleType = "logarithmic"
+	}
+	fmt.Fprintf(env.Info, "  - %s (AIMD vs Base fee comparison - %s scale)\n", filename, scaleType)
+	gasFilename := fmt.Sprintf("base_comparison_%d_%d_gas.html", dataset.StartBlock, dataset.EndBlock)
+	fmt.Fprintf(env.Info, "  - %s (Gas usage analysis)\n", gasFilename)
+	accuracyFilename := fmt.Sprintf("base_comparison_%d_%d_accuracy.html", dataset.StartBlock, dataset.EndBlock)
+	fmt.Fprintf(env.Info, "  - %s (Simulation error by block range)\n", accuracyFilename)
+	return nil
+}
+
+// compareDataSet runs every selected algorithm against the same dataset and compares them
+func compareDataSet(env *Env, cfg config.Config, dataset *blockchain.DataSet) error {
+	runs, err := compare.Runs(cfg)
+	if err != nil {
+		return usageError{err: err}
+	}
+
+	comparison, err := compare.CompareDataSet(runs, dataset, env.Logger)
+	if err != nil {
+		return fmt.Errorf("simulation failed: %w", err)
+	}
+	rows := compare.DataSetRows(comparison, fmt.Sprintf("base_%d_%d", dataset.StartBlock, dataset.EndBlock))
+	if env.Format == output.Text {
+		compare.PrintDataSetComparison(env.Out, comparison)
+	} else if err := output.Write(env.Out, env.Format, compare.Table(rows)); err != nil {
+		return err
+	}
+
+	if cfg.Simulation.EnableGraphs {
+		filename := fmt.Sprintf("comparison_base_%d_%d_%s.html",
+			dataset.StartBlock, dataset.EndBlock, strings.Join(comparison.Labels, "_"))
+		if err := visualization.NewGenerator(env.Logger).GenerateDataSetComparisonChart(comparison, cfg.Simulation.LogScale, filename); err != nil {
+			env.Warnf("failed to generate comparison chart: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+package compare
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// Run is one configuration in a side-by-side comparison
+type Run struct {
+	Label  string
+	Config config.Config // Configuration running only this algorithm or variant, labelled with Label
+}
+
+// Runs returns one run per -variant, or one per adjuster type selected with -adjuster-type
+func Runs(cfg config.Config) ([]Run, error) {
+	if len(cfg.Simulation.Compare.Variants) > 0 {
+		return variantRuns(cfg)
+	}
+
+	var runs []Run
+	seen := make(map[simulator.AdjusterType]bool)
+	for _, name := range cfg.Simulation.AdjusterTypeList() {
+		adjusterType, err := simulator.ParseAdjusterType(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[adjusterType] {
+			return nil, fmt.Errorf("adjuster type %s selected more than once", adjusterType)
+		}
+		seen[adjusterType] = true
+
+		runCfg := cfg
+		runCfg.Simulation.AdjusterType = string(adjusterType)
+		runCfg.Simulation.AdjusterTypes = []string{string(adjusterType)}
+		runCfg.Simulation.Label = string(adjusterType)
+		runs = append(runs, Run{Label: string(adjusterType), Config: runCfg})
+	}
+	return runs, nil
+}
+
+// variantRuns returns one run per named variant, each with its overrides applied
+func variantRuns(cfg config.Config) ([]Run, error) {
+	variants, err := config.ParseVariants(cfg.Simulation.Compare.Variants)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]Run, 0, len(variants))
+	for _, variant := range variants {
+		if _, err := simulator.ParseAdjusterType(variant.AdjusterType); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+		runCfg, err := variant.Apply(cfg)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, Run{Label: variant.Name, Config: runCfg})
+	}
+	return runs, nil
+}
+
+// Labels returns the run labels in order
+func Labels(runs []Run) []string {
+	labels := make([]string, len(runs))
+	for i, run := range runs {
+		labels[i] = run.Label
+	}
+	return labels
+}
+
+// AdjusterTypes returns the algorithm of every run in order
+func AdjusterTypes(runs []Run) []string {
+	adjusterTypes := make([]string, len(runs))
+	for i, run := range runs {
+		adjusterTypes[i] = run.Config.Simulation.AdjusterType
+	}
+	return adjusterTypes
+}
+
+// ScenarioComparison holds every run's results on one shared scenario
+type ScenarioComparison struct {
+	Scenario      scenarios.Scenario
+	Labels        []string
+	AdjusterTypes []string
+	Results       []analysis.Result // One per run, in run order
+	BaseFees      [][]uint64        // Base fee after each block, one series per run
+}
+
+// CompareScenario replays the same gas usage sequence through every run, so all runs see
+// identical demand including any randomization applied when the scenario was generated. The
+// adjusters report their internals to logger; nil discards them.
+func CompareScenario(runs []Run, scenario scenarios.Scenario, logger *slog.Logger) (ScenarioComparison, error) {
+	comparison := ScenarioComparison{Scenario: scenario, Labels: Labels(runs), AdjusterTypes: AdjusterTypes(runs)}
+	for _, run := range runs {
+		// The analyzer panics on an unknown adjuster type; report it instead
+		if _, err := simulator.ParseAdjusterType(run.Config.Simulation.AdjusterType); err != nil {
+			return ScenarioComparison{}, err
+		}
+
+		// One simulation yields both the metrics and the charted fees
+		analyzer := analysis.NewAnalyzer(run.Config)
+		analyzer.SetLogger(logger)
+		result, baseFees := analyzer.RunDetailedAnalysisWithBaseFees(scenario)
+		comparison.Results = append(comparison.Results, result)
+		comparison.BaseFees = append(comparison.BaseFees, baseFees)
+	}
+	return comparison, nil
+}
+
+// DataSetComparison holds every run's results against one blockchain dataset
+type DataSetComparison struct {
+	DataSet       *blockchain.DataSet
+	Labels        []string
+	AdjusterTypes []string
+	SimResults    []*blockchain.SimulationResult // One per run, with comparison data for charts
+	Results       []*analysis.Result
+}
+
+// CompareDataSet simulates every run against the same dataset, reporting each simulated block to
+// logger; nil discards
+func CompareDataSet(runs []Run, dataset *blockchain.DataSet, logger *slog.Logger) (DataSetComparison, error) {
+	comparison := DataSetComparison{DataSet: dataset, Labels: Labels(runs), AdjusterTypes: AdjusterTypes(runs)}
+	for _, run := range runs {
+		adjusterType, err := simulator.ParseAdjusterType(run.Config.Simulation.AdjusterType)
+		if err != nil {
+			return DataSetComparison{}, err
+		}
+
+		sim := blockchain.NewSimulator(run.Config, adjusterType)
+		sim.SetQuiet(true)
+		sim.SetLogger(logger)
+		simResult, analysisResult, err := sim.SimulateAgainstDataSetWithOptions(dataset, true)
+		if err != nil {
+			return DataSetComparison{}, fmt.Errorf("%s: %w", run.Label, err)
+		}
+		comparison.SimResults = append(comparison.SimResults, simResult)
+		comparison.Results = append(comparison.Results, analysisResult)
+	}
+	return comparison, nil
+}
+
+// PrintScenarioComparison prints one row of headline metrics per run
+func PrintScenarioComparison(w io.Writer, comparison ScenarioComparison) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "COMPARISON: %s (%d blocks)\n", comparison.Scenario.Name, len(comparison.Scenario.Blocks))
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, runHeader(comparison.Labels, comparison.AdjusterTypes)+"\tFinal Fee\tAvg Fee\tP99/P50\tAvg |Change|\tLongest at Floor\tBurst %\tResponsiveness\tOscillation")
+	for i, result := range comparison.Results {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2f%%\t%d\t%.1f%%\t%.3f\t%s\n",
+			runName(comparison.Labels, comparison.AdjusterTypes, i),
+			config.FormatGwei(float64(result.FinalBaseFee)),
+			config.FormatGwei(result.TimeWeightedFee),
+			tailRatio(result),
+			result.MeanAbsFeeChange,
+			result.LongestMinFeeRun,
+			result.BurstCapacityFraction*100,
+			result.ResponsivenessScore,
+			oscillation(result),
+		)
+	}
+	tw.Flush()
+}
+
+// PrintDataSetComparison prints one row of fit and inclusion metrics per run
+func PrintDataSetComparison(w io.Writer, comparison DataSetComparison) {
+	dataset := comparison.DataSet
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "COMPARISON: Base blocks %d-%d (%d blocks)\n", dataset.StartBlock, dataset.EndBlock, len(dataset.Blocks))
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	var actualSum float64
+	for _, block := range dataset.Blocks {
+		actualSum += float64(block.BaseFeePerGas)
+	}
+	fmt.Fprintf(w, "Actual average base fee: %s\n\n", config.FormatGwei(actualSum/float64(len(dataset.Blocks))))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, runHeader(comparison.Labels, comparison.AdjusterTypes)+"\tAvg Fee\tDropped Tx\tUtilization\tAvg |Change|\tMAPE\tCorrelation\tLag\tDirection")
+	for i, simResult := range comparison.SimResults {
+		var accuracy blockchain.Accuracy
+		if simResult.Accuracy != nil {
+			accuracy = *simResult.Accuracy
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%.2f%%\t%.1f%%\t%.2f%%\t%.1f%%\t%.3f\t%+d\t%.1f%%\n",
+			runName(comparison.Labels, comparison.AdjusterTypes, i),
+			config.FormatGwei(float64(simResult.AvgBaseFee)),
+			simResult.DroppedPercentage,
+			simResult.EffectiveUtilization*100,
+			comparison.Results[i].MeanAbsFeeChange,
+			accuracy.MAPE,
+			accuracy.Correlation,
+			accuracy.Lag,
+			accuracy.DirectionalAgreement,
+		)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nMAPE, correlation, lag and direction compare each simulated fee with the actual fee.\n")
+}
+
+// runHeader names the leading table columns: the algorithm, or the variant and its algorithm
+func runHeader(labels, adjusterTypes []string) string {
+	if isVariantComparison(labels, adjusterTypes) {
+		return "Variant\tAlgorithm"
+	}
+	return "Algorithm"
+}
+
+// runName fills the leading table columns of run i
+func runName(labels, adjusterTypes []string, i int) string {
+	if isVariantComparison(labels, adjusterTypes) {
+		return labels[i] + "\t" + adjusterTypes[i]
+	}
+	return labels[i]
+}
+
+// isVariantComparison reports whether any run is labelled with something other than its algorithm
+func isVariantComparison(labels, adjusterTypes []string) bool {
+	for i := range labels {
+		if labels[i] != adjusterTypes[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// tailRatio formats the P99/P50 fee ratio
+func tailRatio(result analysis.Result) string {
+	if result.FeeP50 == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.2fx", result.FeeP99/result.FeeP50)
+}
+
+// oscillation formats the dominant fee oscillation period
+func oscillation(result analysis.Result) string {
+	if result.DominantPeriod == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f blocks", result.DominantPeriod)
+}
@@ -0,0 +1,113 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+)
+
+func TestRuns(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterTypes = []string{"aimd", "eip-1559", "pid"}
+
+	runs, err := Runs(cfg)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	labels := Labels(runs)
+	if len(labels) != 3 || labels[0] != "aimd" || labels[1] != "eip1559" || labels[2] != "pid" {
+		t.Fatalf("Unexpected labels %v", labels)
+	}
+	for _, run := range runs {
+		if run.Config.Simulation.AdjusterType != run.Label || len(run.Config.Simulation.AdjusterTypeList()) != 1 {
+			t.Errorf("Run %s has adjuster types %v", run.Label, run.Config.Simulation.AdjusterTypeList())
+		}
+	}
+
+	cfg.Simulation.AdjusterType = "eip1559"
+	cfg.Simulation.AdjusterTypes = []string{"eip1559", "eip-1559"}
+	if _, err := Runs(cfg); err == nil {
+		t.Error("Expected an error for a duplicated adjuster type")
+	}
+}
+
+func TestCompareScenarioSharesDemand(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterTypes = []string{"aimd", "eip1559"}
+	cfg.Simulation.Randomizer.GaussianNoise = 0.2
+	runs, err := Runs(cfg)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+
+	scenario, ok := scenarios.NewGenerator(cfg.Simulation).GetByName("mixed", cfg)
+	if !ok {
+		t.Fatal("mixed scenario not found")
+	}
+	comparison, err := CompareScenario(runs, scenario, nil)
+	if err != nil {
+		t.Fatalf("CompareScenario failed: %v", err)
+	}
+
+	if len(comparison.Results) != 2 || len(comparison.BaseFees) != 2 {
+		t.Fatalf("Expected one result per run, got %d results and %d series", len(comparison.Results), len(comparison.BaseFees))
+	}
+	for i, baseFees := range comparison.BaseFees {
+		if len(baseFees) != len(scenario.Blocks) {
+			t.Errorf("%s: expected %d fees, got %d", comparison.Labels[i], len(scenario.Blocks), len(baseFees))
+		}
+		if final := baseFees[len(baseFees)-1]; final != comparison.Results[i].FinalBaseFee {
+			t.Errorf("%s: charted fees end on %d, metrics on %d", comparison.Labels[i], final, comparison.Results[i].FinalBaseFee)
+		}
+	}
+	if comparison.Results[0].TotalBlocks != comparison.Results[1].TotalBlocks {
+		t.Error("Runs saw different scenarios")
+	}
+	if comparison.BaseFees[0][len(scenario.Blocks)-1] == comparison.BaseFees[1][len(scenario.Blocks)-1] {
+		t.Error("Expected different algorithms to end on different fees")
+	}
+}
+
+func TestVariantRuns(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.Compare.Variants = []string{
+		"conservative:aimd:aimd-gamma=0.5,window-size=20",
+		"aggressive:aimd:aimd-gamma=0.1",
+	}
+
+	runs, err := Runs(cfg)
+	if err != nil {
+		t.Fatalf("Runs failed: %v", err)
+	}
+	if len(runs) != 2 || runs[0].Label != "conservative" || runs[1].Label != "aggressive" {
+		t.Fatalf("Unexpected runs %v", Labels(runs))
+	}
+	if runs[0].Config.Adjuster.AIMD.Gamma != 0.5 || runs[0].Config.WindowSize != 20 {
+		t.Errorf("Overrides not applied: gamma %f, window %d", runs[0].Config.Adjuster.AIMD.Gamma, runs[0].Config.WindowSize)
+	}
+	if runs[1].Config.WindowSize != cfg.WindowSize || cfg.Adjuster.AIMD.Gamma != config.Default().Adjuster.AIMD.Gamma {
+		t.Error("Overrides leaked into other runs")
+	}
+
+	scenario, _ := scenarios.NewGenerator(cfg.Simulation).GetByName("full", cfg)
+	comparison, err := CompareScenario(runs, scenario, nil)
+	if err != nil {
+		t.Fatalf("CompareScenario failed: %v", err)
+	}
+	rows := ScenarioRows(comparison)
+	if len(rows) != 2 || rows[1].Label != "aggressive" || rows[1].AdjusterType != "aimd" || comparison.Results[1].Label != "aggressive" {
+		t.Errorf("Labels not carried into results: %+v", rows)
+	}
+
+	for _, bad := range []string{"noname", "x:aimd:mc-runs=5", "x:aimd:aimd-gamma", "bad name:aimd"} {
+		cfg.Simulation.Compare.Variants = []string{bad}
+		if _, err := Runs(cfg); err == nil {
+			t.Errorf("Expected an error for variant %q", bad)
+		}
+	}
+	cfg.Simulation.Compare.Variants = []string{"a:aimd", "a:pid"}
+	if _, err := Runs(cfg); err == nil {
+		t.Error("Expected an error for duplicate variant names")
+	}
+}
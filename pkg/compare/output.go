@@ -0,0 +1,83 @@
+package compare
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Row holds the metrics of one run on one scenario or dataset
+type Row struct {
+	Label        string             `json:"label"`
+	AdjusterType string             `json:"adjusterType"`
+	Case         string             `json:"case"`
+	Metrics      map[string]float64 `json:"metrics"`
+}
+
+// ScenarioRows returns one row per run of a scenario comparison
+func ScenarioRows(comparison ScenarioComparison) []Row {
+	rows := make([]Row, len(comparison.Results))
+	for i, result := range comparison.Results {
+		rows[i] = Row{
+			Label:        comparison.Labels[i],
+			AdjusterType: comparison.AdjusterTypes[i],
+			Case:         comparison.Scenario.Name,
+			Metrics:      result.Metrics(),
+		}
+	}
+	return rows
+}
+
+// DataSetRows returns one row per run of a dataset comparison, with the dataset-only metrics merged in
+func DataSetRows(comparison DataSetComparison, name string) []Row {
+	rows := make([]Row, len(comparison.Results))
+	for i, result := range comparison.Results {
+		metrics := result.Metrics()
+		for metric, value := range comparison.SimResults[i].Metrics() {
+			metrics[metric] = value
+		}
+		rows[i] = Row{
+			Label:        comparison.Labels[i],
+			AdjusterType: comparison.AdjusterTypes[i],
+			Case:         name,
+			Metrics:      metrics,
+		}
+	}
+	return rows
+}
+
+// WriteJSON writes the rows as an indented JSON array
+func WriteJSON(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(rows)
+}
+
+// Table returns the rows as a table: label, adjuster type, case, then every metric some row reports
+func Table(rows []Row) *output.Table {
+	var metricNames []string
+	for _, name := range sweep.MetricNames() {
+		for _, row := range rows {
+			if _, ok := row.Metrics[name]; ok {
+				metricNames = append(metricNames, name)
+				break
+			}
+		}
+	}
+
+	table := output.NewTable("comparison", "Comparison", append([]string{"label", "adjuster_type", "case"}, metricNames...)...)
+	for _, row := range rows {
+		values := []interface{}{row.Label, row.AdjusterType, row.Case}
+		for _, name := range metricNames {
+			if value, ok := row.Metrics[name]; ok {
+				values = append(values, value)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		table.Append(values...)
+	}
+	return table
+}
@@ -3,6 +3,11 @@ package config
 import (
 	"flag"
 	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,12 +25,35 @@ type Config struct {
 
 // SimulationConfig holds runtime configuration for simulations
 type SimulationConfig struct {
-	Scenario     string
-	EnableGraphs bool
-	LogScale     bool // Use logarithmic scale for Y-axis in charts
-	ShowHelp     bool
-	AdjusterType string // Type of fee adjuster to use
-	Randomizer   RandomizerConfig
+	Scenario      string
+	ScenarioFile  string   // Path to a JSON scenario file; overrides Scenario when set
+	DataSets      []string // Blockchain dataset files to evaluate alongside the scenarios
+	EnableGraphs  bool
+	LogScale      bool // Use logarithmic scale for Y-axis in charts
+	ShowHelp      bool
+	ConfigFile    string   // JSON or TOML configuration file applied before environment variables and flags
+	Profile       string   // Named profile of the configuration file applied on top of its base settings
+	DumpConfig    bool     // Print the resolved configuration instead of running
+	Output        string   // Format of the results: text, json, csv or markdown
+	OutFile       string   // File the results are written to instead of stdout
+	Manifest      string   // File a reproducibility manifest of the run is written to
+	LogLevel      string   // Minimum level of the diagnostics logged to stderr: debug, info, warn or error
+	LogFormat     string   // Format of the diagnostics: text or json
+	AdjusterType  string   // Type of fee adjuster to use
+	AdjusterTypes []string // Every type given to -adjuster-type as a comma-separated list; the first is AdjusterType
+	Label         string   // Name of the variant this configuration runs, empty outside comparisons
+	Randomizer    RandomizerConfig
+	Attack        AttackConfig
+	Search        SearchConfig
+	MonteCarlo    MonteCarloConfig
+	Sweep         SweepConfig
+	Tune          TuneConfig
+	Pareto        ParetoConfig
+	Sensitivity   SensitivityConfig
+	Response      ResponseConfig
+	Equilibrium   EquilibriumConfig
+	Significance  SignificanceConfig
+	Compare       CompareConfig
 }
 
 // RandomizerConfig holds configuration for randomizer
@@ -38,6 +66,97 @@ type RandomizerConfig struct {
 	BurstIntensity   float64 // Multiplier for gas usage during bursts
 }
 
+// AttackConfig holds configuration for the adversarial block producer model
+type AttackConfig struct {
+	Share       float64 // Fraction of blocks produced by the attacker (0.0 = none, 0.2 = 20%)
+	Strategy    string  // Attack strategy: stuff or drain
+	LandEvery   int     // Drain: land own transactions on every Nth attacker block
+	LandingGas  uint64  // Drain: gas of own transactions landed per landing block
+	PriorityFee uint64  // Priority fee in wei forgone per unit of honest gas excluded
+}
+
+// SearchConfig holds configuration for the worst-case demand search
+type SearchConfig struct {
+	Metric     string  // Metric to maximize: volatility, peak-to-trough or min-fee-time
+	Method     string  // Search method: hill or genetic
+	Iterations int     // Hill climbing steps or genetic generations
+	Population int     // Genetic: population size
+	Blocks     int     // Length of the searched gas usage sequence
+	MinGas     uint64  // Lower bound on gas used per block
+	MaxGas     uint64  // Upper bound on gas used per block (0 = burst capacity)
+	Mutation   float64 // Mutation step as a fraction of the gas range
+	Output     string  // File to write the worst-case scenario to
+}
+
+// MonteCarloConfig holds configuration for multi-seed batch runs
+type MonteCarloConfig struct {
+	Runs    int // Number of seeds to run, starting at the randomizer seed
+	Workers int // Number of runs executed in parallel (0 = one per CPU)
+}
+
+// SweepConfig holds configuration for parameter sweeps
+type SweepConfig struct {
+	Params  []string // Parameter ranges by flag name, e.g. aimd-gamma=0.05:0.5:0.05
+	Mode    string   // Sampling mode: grid or random
+	Samples int      // Random: number of sampled points
+	Workers int      // Number of points evaluated in parallel (0 = one per CPU)
+}
+
+// TuneConfig holds configuration for automatic parameter tuning
+type TuneConfig struct {
+	Params     []string // Parameter bounds by flag name, e.g. aimd-gamma=0.01:1
+	Objective  string   // Weighted metrics to minimize, e.g. base_fee_volatility:1,responsiveness_score:-1
+	Method     string   // Optimizer: nelder-mead or random
+	Iterations int      // Maximum number of objective evaluations
+	Holdout    float64  // Fraction of cases held out from tuning for validation
+	Segments   int      // Number of contiguous block ranges each dataset is split into
+}
+
+// ParetoConfig holds configuration for multi-objective frontier exploration
+type ParetoConfig struct {
+	Metrics string // Two or three metrics with directions, e.g. base_fee_volatility:min,responsiveness_score:max
+}
+
+// SensitivityConfig holds configuration for global sensitivity analysis
+type SensitivityConfig struct {
+	Method  string   // Method: morris (elementary effects) or sobol (variance-based indices)
+	Params  []string // Parameter bounds by flag name (empty = every parameter of the adjuster)
+	Metrics string   // Comma-separated output metrics to analyze
+	Samples int      // Morris trajectories or Sobol base samples (0 = method default)
+	Levels  int      // Morris: number of grid levels per parameter
+}
+
+// ResponseConfig holds configuration for closed-loop step, impulse and ramp response analysis
+type ResponseConfig struct {
+	Blocks     int     // Number of blocks simulated per input
+	StepSize   float64 // Demand multiple after the step (and at the end of the ramp)
+	Elasticity float64 // Price elasticity of the demand curve
+	Tolerance  float64 // Settling band as a fraction of the equilibrium fee
+	Periods    string  // Frequency response: comma-separated demand periods in blocks
+	Amplitude  float64 // Frequency response: demand oscillation as a fraction of the reference demand
+	Cycles     int     // Frequency response: periods measured after the warm-up
+}
+
+// EquilibriumConfig holds configuration for long-run equilibrium analysis under constant demand
+type EquilibriumConfig struct {
+	Demand string // Demand curve: elastic:scale:elasticity, linear:gas:choke or points:fee=gas,...
+	Blocks int    // Number of blocks simulated
+}
+
+// SignificanceConfig holds configuration for paired significance tests between two adjusters
+type SignificanceConfig struct {
+	Against    string  // Adjuster type compared with -adjuster-type
+	Metrics    string  // Comma-separated metrics to test
+	Resamples  int     // Bootstrap resamples per confidence interval
+	Confidence float64 // Confidence level of the intervals, e.g. 0.95
+	Segments   int     // Number of contiguous block ranges each dataset is split into
+}
+
+// CompareConfig holds configuration for side-by-side comparisons
+type CompareConfig struct {
+	Variants []string // Named variants, e.g. aggressive:aimd:aimd-gamma=0.1 (see ParseVariant)
+}
+
 // AdjusterConfigs holds configuration for different adjuster types
 type AdjusterConfigs struct {
 	// EIP-1559 specific config
@@ -80,10 +199,76 @@ func Default() Config {
 			EnableGraphs: false,
 			LogScale:     false,
 			ShowHelp:     false,
+			Output:       "text",
+			LogLevel:     "info",
+			LogFormat:    "text",
 			AdjusterType: "aimd",
 			Randomizer: RandomizerConfig{
 				Seed: time.Now().UnixNano(),
 			},
+			Attack: AttackConfig{
+				Share:       0.2,
+				Strategy:    "stuff",
+				LandEvery:   5,
+				LandingGas:  5_000_000,
+				PriorityFee: 10_000_000,
+			},
+			Search: SearchConfig{
+				Metric:     "volatility",
+				Method:     "hill",
+				Iterations: 500,
+				Population: 32,
+				Blocks:     100,
+				MinGas:     0,
+				MaxGas:     0,
+				Mutation:   0.1,
+				Output:     "worst_case_scenario.json",
+			},
+			MonteCarlo: MonteCarloConfig{
+				Runs:    100,
+				Workers: 0,
+			},
+			Sweep: SweepConfig{
+				Mode:    "grid",
+				Samples: 100,
+				Workers: 0,
+			},
+			Tune: TuneConfig{
+				Objective:  "base_fee_volatility:1,dropped_tx_percent:1,responsiveness_score:-1",
+				Method:     "nelder-mead",
+				Iterations: 200,
+				Holdout:    0.25,
+				Segments:   1,
+			},
+			Pareto: ParetoConfig{
+				Metrics: "base_fee_volatility:min,responsiveness_score:max",
+			},
+			Sensitivity: SensitivityConfig{
+				Method:  "morris",
+				Metrics: "base_fee_volatility,responsiveness_score",
+				Samples: 0,
+				Levels:  4,
+			},
+			Response: ResponseConfig{
+				Blocks:     200,
+				StepSize:   2.0,
+				Elasticity: 1.0,
+				Tolerance:  0.05,
+				Periods:    "2,4,8,16,32,64,128,256,512,1800",
+				Amplitude:  0.2,
+				Cycles:     8,
+			},
+			Equilibrium: EquilibriumConfig{
+				Demand: "elastic:1.5:1",
+				Blocks: 2000,
+			},
+			Significance: SignificanceConfig{
+				Against:    "eip1559",
+				Metrics:    "mean_abs_fee_change,base_fee_volatility,fee_p99,time_weighted_fee,responsiveness_score,dropped_tx_percent",
+				Resamples:  10000,
+				Confidence: 0.95,
+				Segments:   10,
+			},
 		},
 	}
 
@@ -109,15 +294,55 @@ func Default() Config {
 
 // Parser handles command-line flag parsing
 type Parser struct {
-	config  *Config
-	flagSet *flag.FlagSet
+	config     *Config
+	flagSet    *flag.FlagSet
+	registered bool // Whether RegisterFlags has run
+}
+
+// FeeFloor returns the base fee at or below which a block counts as stuck at the minimum.
+// When no minimum base fee is configured, 1% of the initial base fee is used instead.
+func (c Config) FeeFloor() uint64 {
+	if c.MinBaseFee > 0 {
+		return c.MinBaseFee
+	}
+	return c.InitialBaseFee / 100
+}
+
+// IsComparison reports whether several algorithms or named variants run side by side
+func (s SimulationConfig) IsComparison() bool {
+	return len(s.AdjusterTypeList()) > 1 || len(s.Compare.Variants) > 0
+}
+
+// AdjusterTypeList returns every adjuster type selected with -adjuster-type. Configurations
+// built outside the parser, or whose AdjusterType was changed since, select only AdjusterType.
+func (s SimulationConfig) AdjusterTypeList() []string {
+	if len(s.AdjusterTypes) > 0 && s.AdjusterTypes[0] == s.AdjusterType {
+		return s.AdjusterTypes
+	}
+	return []string{s.AdjusterType}
+}
+
+// splitAdjusterTypes splits a comma-separated AdjusterType into AdjusterTypes.
+// Single-algorithm code uses the first type.
+func (s *SimulationConfig) splitAdjusterTypes() {
+	s.AdjusterTypes = nil
+	for _, adjusterType := range strings.Split(s.AdjusterType, ",") {
+		if adjusterType = strings.TrimSpace(adjusterType); adjusterType != "" {
+			s.AdjusterTypes = append(s.AdjusterTypes, adjusterType)
+		}
+	}
+	if len(s.AdjusterTypes) > 0 {
+		s.AdjusterType = s.AdjusterTypes[0]
+	}
 }
 
 // NewParser creates a new configuration parser
 func NewParser() *Parser {
 	config := Default()
 
-	flagSet := flag.NewFlagSet("feemarketsim", flag.ExitOnError)
+	// Errors are returned to the caller, which decides how to report them and exit
+	flagSet := flag.NewFlagSet("feemarketsim", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
 
 	return &Parser{
 		config:  &config,
@@ -127,17 +352,33 @@ func NewParser() *Parser {
 
 // RegisterFlags registers all command-line flags
 func (p *Parser) RegisterFlags() {
+	p.registered = true
+
 	// Core configuration flags (apply to all algorithms)
-	p.flagSet.Uint64Var(&p.config.TargetBlockSize, "target-block-size", p.config.TargetBlockSize, "Target block size in gas units")
+	p.flagSet.Var((*gasValue)(&p.config.TargetBlockSize), "target-block-size", "Target block size in gas units, e.g. 15000000 or 15M")
 	p.flagSet.Float64Var(&p.config.BurstMultiplier, "burst-multiplier", p.config.BurstMultiplier, "Max burst capacity as multiple of target")
-	p.flagSet.Uint64Var(&p.config.InitialBaseFee, "initial-base-fee", p.config.InitialBaseFee, "Initial base fee in wei")
-	p.flagSet.Uint64Var(&p.config.MinBaseFee, "min-base-fee", p.config.MinBaseFee, "Minimum base fee in wei")
+	p.flagSet.Var((*feeValue)(&p.config.InitialBaseFee), "initial-base-fee", "Initial base fee in wei, or with a unit, e.g. 1gwei")
+	p.flagSet.Var((*feeValue)(&p.config.MinBaseFee), "min-base-fee", "Minimum base fee in wei, or with a unit, e.g. 0.05gwei")
 
 	// Simulation configuration flags
 	p.flagSet.StringVar(&p.config.Simulation.Scenario, "scenario", p.config.Simulation.Scenario, "Scenario to run: full, empty, stable, mixed, or all")
+	p.flagSet.StringVar(&p.config.Simulation.ScenarioFile, "scenario-file", p.config.Simulation.ScenarioFile, "Run a scenario loaded from a JSON file instead of -scenario")
+	p.flagSet.Var((*stringList)(&p.config.Simulation.DataSets), "dataset", "Blockchain dataset file to evaluate (repeatable; sweep, tune and significance)")
 	p.flagSet.BoolVar(&p.config.Simulation.EnableGraphs, "graph", p.config.Simulation.EnableGraphs, "Generate visualization charts (HTML files)")
 	p.flagSet.BoolVar(&p.config.Simulation.LogScale, "log-scale", p.config.Simulation.LogScale, "Use logarithmic scale for Y-axis in charts")
+	p.flagSet.StringVar(&p.config.Simulation.Output, "output", p.config.Simulation.Output, "Results format: text, json, csv or markdown")
+	p.flagSet.StringVar(&p.config.Simulation.OutFile, "out-file", p.config.Simulation.OutFile, "Write the results to a file instead of stdout")
+	for name, replacement := range deprecatedFlags {
+		f := p.flagSet.Lookup(replacement)
+		p.flagSet.Var(f.Value, name, "Deprecated: use -"+replacement)
+	}
+	p.flagSet.StringVar(&p.config.Simulation.Manifest, "manifest", p.config.Simulation.Manifest, "Write a manifest of the run for the rerun command to this file")
+	p.flagSet.StringVar(&p.config.Simulation.LogLevel, "log-level", p.config.Simulation.LogLevel, "Minimum level of the diagnostics logged to stderr: debug, info, warn or error")
+	p.flagSet.StringVar(&p.config.Simulation.LogFormat, "log-format", p.config.Simulation.LogFormat, "Format of the diagnostics logged to stderr: text or json")
 	p.flagSet.BoolVar(&p.config.Simulation.ShowHelp, "help", p.config.Simulation.ShowHelp, "Show detailed help and parameter explanations")
+	p.flagSet.StringVar(&p.config.Simulation.ConfigFile, "config", p.config.Simulation.ConfigFile, "JSON or TOML configuration file (keys are flag names)")
+	p.flagSet.StringVar(&p.config.Simulation.Profile, "profile", p.config.Simulation.Profile, "Named profile from the configuration file")
+	p.flagSet.BoolVar(&p.config.Simulation.DumpConfig, "dump-config", p.config.Simulation.DumpConfig, "Print the resolved configuration in the config file's format and exit")
 
 	// Randomizer configuration flags
 	p.flagSet.Int64Var(&p.config.Simulation.Randomizer.Seed, "rng-seed", p.config.Simulation.Randomizer.Seed, "Seed for randomizer")
@@ -147,11 +388,80 @@ func (p *Parser) RegisterFlags() {
 	p.flagSet.IntVar(&p.config.Simulation.Randomizer.BurstDurationMax, "rng-burst-duration-max", p.config.Simulation.Randomizer.BurstDurationMax, "Maximum burst duration in blocks")
 	p.flagSet.Float64Var(&p.config.Simulation.Randomizer.BurstIntensity, "rng-burst-intensity", p.config.Simulation.Randomizer.BurstIntensity, "Multiplier for gas usage during bursts")
 
+	// Attack configuration flags
+	p.flagSet.Float64Var(&p.config.Simulation.Attack.Share, "attack-share", p.config.Simulation.Attack.Share, "Fraction of blocks produced by the attacker")
+	p.flagSet.StringVar(&p.config.Simulation.Attack.Strategy, "attack-strategy", p.config.Simulation.Attack.Strategy, "Attack strategy: stuff (fill blocks to push fees up) or drain (empty blocks to push fees down)")
+	p.flagSet.IntVar(&p.config.Simulation.Attack.LandEvery, "attack-land-every", p.config.Simulation.Attack.LandEvery, "Drain: land own transactions on every Nth attacker block")
+	p.flagSet.Var((*gasValue)(&p.config.Simulation.Attack.LandingGas), "attack-landing-gas", "Drain: gas of own transactions landed per landing block")
+	p.flagSet.Var((*feeValue)(&p.config.Simulation.Attack.PriorityFee), "attack-priority-fee", "Priority fee in wei forgone per unit of honest gas the attacker excludes")
+
+	// Search configuration flags
+	p.flagSet.StringVar(&p.config.Simulation.Search.Metric, "search-metric", p.config.Simulation.Search.Metric, "Metric to maximize: volatility, peak-to-trough, min-fee-time")
+	p.flagSet.StringVar(&p.config.Simulation.Search.Method, "search-method", p.config.Simulation.Search.Method, "Search method: hill or genetic")
+	p.flagSet.IntVar(&p.config.Simulation.Search.Iterations, "search-iterations", p.config.Simulation.Search.Iterations, "Hill climbing steps or genetic generations")
+	p.flagSet.IntVar(&p.config.Simulation.Search.Population, "search-population", p.config.Simulation.Search.Population, "Genetic: population size")
+	p.flagSet.IntVar(&p.config.Simulation.Search.Blocks, "search-blocks", p.config.Simulation.Search.Blocks, "Length of the searched gas usage sequence")
+	p.flagSet.Var((*gasValue)(&p.config.Simulation.Search.MinGas), "search-min-gas", "Lower bound on gas used per block")
+	p.flagSet.Var((*gasValue)(&p.config.Simulation.Search.MaxGas), "search-max-gas", "Upper bound on gas used per block (0 = burst capacity)")
+	p.flagSet.Float64Var(&p.config.Simulation.Search.Mutation, "search-mutation", p.config.Simulation.Search.Mutation, "Mutation step as a fraction of the gas range")
+	p.flagSet.StringVar(&p.config.Simulation.Search.Output, "search-output", p.config.Simulation.Search.Output, "File to write the worst-case scenario to")
+
+	// Monte Carlo configuration flags
+	p.flagSet.IntVar(&p.config.Simulation.MonteCarlo.Runs, "mc-runs", p.config.Simulation.MonteCarlo.Runs, "Monte Carlo: number of seeds to run")
+	p.flagSet.IntVar(&p.config.Simulation.MonteCarlo.Workers, "mc-workers", p.config.Simulation.MonteCarlo.Workers, "Monte Carlo: runs executed in parallel (0 = one per CPU)")
+
+	// Sweep configuration flags
+	p.flagSet.Var((*stringList)(&p.config.Simulation.Sweep.Params), "sweep-param", "Sweep: parameter range, e.g. aimd-gamma=0.05:0.5:0.05 or pid-kp=log(0.001,1,20) (repeatable)")
+	p.flagSet.StringVar(&p.config.Simulation.Sweep.Mode, "sweep-mode", p.config.Simulation.Sweep.Mode, "Sweep: grid (every combination) or random (sampled points)")
+	p.flagSet.IntVar(&p.config.Simulation.Sweep.Samples, "sweep-samples", p.config.Simulation.Sweep.Samples, "Sweep: number of points in random mode")
+	p.flagSet.IntVar(&p.config.Simulation.Sweep.Workers, "sweep-workers", p.config.Simulation.Sweep.Workers, "Sweep: points evaluated in parallel (0 = one per CPU)")
+
+	// Tune configuration flags
+	p.flagSet.Var((*stringList)(&p.config.Simulation.Tune.Params), "tune-param", "Tune: parameter bounds, e.g. aimd-gamma=0.01:1 or pid-kp=log(0.001,1,2) (repeatable)")
+	p.flagSet.StringVar(&p.config.Simulation.Tune.Objective, "tune-objective", p.config.Simulation.Tune.Objective, "Tune: weighted metrics to minimize, metric:weight,... (negative weights maximize)")
+	p.flagSet.StringVar(&p.config.Simulation.Tune.Method, "tune-method", p.config.Simulation.Tune.Method, "Tune: optimizer, nelder-mead or random")
+	p.flagSet.IntVar(&p.config.Simulation.Tune.Iterations, "tune-iterations", p.config.Simulation.Tune.Iterations, "Tune: maximum number of objective evaluations")
+	p.flagSet.Float64Var(&p.config.Simulation.Tune.Holdout, "tune-holdout", p.config.Simulation.Tune.Holdout, "Tune: fraction of cases held out for validation")
+	p.flagSet.IntVar(&p.config.Simulation.Tune.Segments, "tune-segments", p.config.Simulation.Tune.Segments, "Tune: split each dataset into this many contiguous block ranges")
+
+	// Pareto configuration flags
+	p.flagSet.StringVar(&p.config.Simulation.Pareto.Metrics, "pareto-metrics", p.config.Simulation.Pareto.Metrics, "Pareto: two or three metrics with directions, metric:min or metric:max")
+
+	// Sensitivity configuration flags
+	p.flagSet.StringVar(&p.config.Simulation.Sensitivity.Method, "sens-method", p.config.Simulation.Sensitivity.Method, "Sensitivity: morris (elementary effects) or sobol (first-order and total indices)")
+	p.flagSet.Var((*stringList)(&p.config.Simulation.Sensitivity.Params), "sens-param", "Sensitivity: parameter bounds, e.g. aimd-gamma=0.01:1 (repeatable; default every adjuster parameter)")
+	p.flagSet.StringVar(&p.config.Simulation.Sensitivity.Metrics, "sens-metrics", p.config.Simulation.Sensitivity.Metrics, "Sensitivity: comma-separated output metrics")
+	p.flagSet.IntVar(&p.config.Simulation.Sensitivity.Samples, "sens-samples", p.config.Simulation.Sensitivity.Samples, "Sensitivity: Morris trajectories or Sobol base samples (0 = 20 for morris, 256 for sobol)")
+	p.flagSet.IntVar(&p.config.Simulation.Sensitivity.Levels, "sens-levels", p.config.Simulation.Sensitivity.Levels, "Sensitivity: Morris grid levels per parameter")
+
+	// Response configuration flags
+	p.flagSet.IntVar(&p.config.Simulation.Response.Blocks, "response-blocks", p.config.Simulation.Response.Blocks, "Response: blocks simulated per test input")
+	p.flagSet.Float64Var(&p.config.Simulation.Response.StepSize, "response-step", p.config.Simulation.Response.StepSize, "Response: demand multiple after the step and at the end of the ramp")
+	p.flagSet.Float64Var(&p.config.Simulation.Response.Elasticity, "response-elasticity", p.config.Simulation.Response.Elasticity, "Response: price elasticity of demand")
+	p.flagSet.Float64Var(&p.config.Simulation.Response.Tolerance, "response-tolerance", p.config.Simulation.Response.Tolerance, "Response: settling band as a fraction of the equilibrium fee")
+	p.flagSet.StringVar(&p.config.Simulation.Response.Periods, "response-periods", p.config.Simulation.Response.Periods, "Frequency: comma-separated demand periods in blocks")
+	p.flagSet.Float64Var(&p.config.Simulation.Response.Amplitude, "response-amplitude", p.config.Simulation.Response.Amplitude, "Frequency: demand oscillation as a fraction of the reference demand")
+	p.flagSet.IntVar(&p.config.Simulation.Response.Cycles, "response-cycles", p.config.Simulation.Response.Cycles, "Frequency: oscillation cycles measured per period")
+
+	// Equilibrium configuration flags
+	p.flagSet.StringVar(&p.config.Simulation.Equilibrium.Demand, "eq-demand", p.config.Simulation.Equilibrium.Demand, "Equilibrium: demand curve, elastic:scale:elasticity, linear:gas:choke-gwei or points:gwei=gas,... (gas in multiples of target)")
+	p.flagSet.IntVar(&p.config.Simulation.Equilibrium.Blocks, "eq-blocks", p.config.Simulation.Equilibrium.Blocks, "Equilibrium: blocks simulated")
+
+	// Significance configuration flags
+	p.flagSet.StringVar(&p.config.Simulation.Significance.Against, "sig-against", p.config.Simulation.Significance.Against, "Significance: adjuster type compared with -adjuster-type")
+	p.flagSet.StringVar(&p.config.Simulation.Significance.Metrics, "sig-metrics", p.config.Simulation.Significance.Metrics, "Significance: comma-separated metrics to test")
+	p.flagSet.IntVar(&p.config.Simulation.Significance.Resamples, "sig-resamples", p.config.Simulation.Significance.Resamples, "Significance: bootstrap resamples per confidence interval")
+	p.flagSet.Float64Var(&p.config.Simulation.Significance.Confidence, "sig-confidence", p.config.Simulation.Significance.Confidence, "Significance: confidence level of the intervals")
+	p.flagSet.IntVar(&p.config.Simulation.Significance.Segments, "sig-segments", p.config.Simulation.Significance.Segments, "Significance: block ranges each dataset is split into")
+
+	// Comparison configuration flags
+	p.flagSet.Var((*stringList)(&p.config.Simulation.Compare.Variants), "variant", "Compare: named variant, name:adjuster-type[:param=value,...] (repeatable)")
+
 	// Common controller flags
 	p.flagSet.IntVar(&p.config.WindowSize, "window-size", p.config.WindowSize, "Number of blocks to consider in the window")
 
 	// Adjuster type flags
-	p.flagSet.StringVar(&p.config.Simulation.AdjusterType, "adjuster-type", p.config.Simulation.AdjusterType, "Type of fee adjuster to use: aimd, eip1559, pid")
+	p.flagSet.StringVar(&p.config.Simulation.AdjusterType, "adjuster-type", p.config.Simulation.AdjusterType, "Type of fee adjuster to use: aimd, eip1559, pid, or a comma-separated list to compare")
 
 	// EIP-1559 specific flags
 	p.flagSet.Float64Var(&p.config.Adjuster.EIP1559.MaxFeeChange, "eip1559-max-fee-change", p.config.Adjuster.EIP1559.MaxFeeChange, "EIP-1559: Maximum fee change per block")
@@ -174,16 +484,105 @@ func (p *Parser) RegisterFlags() {
 	p.flagSet.Float64Var(&p.config.Adjuster.PID.MaxFeeChange, "pid-max-fee-change", p.config.Adjuster.PID.MaxFeeChange, "PID: Maximum fee change per block")
 }
 
+// FlagSet returns the flag set with every configuration flag registered. Commands may add their
+// own flags to it before calling Parse.
+func (p *Parser) FlagSet() *flag.FlagSet {
+	if !p.registered {
+		p.RegisterFlags()
+	}
+	return p.flagSet
+}
+
+// Args returns the arguments remaining after the flags
+func (p *Parser) Args() []string {
+	return p.flagSet.Args()
+}
+
+// deprecatedFlags maps each deprecated flag to the flag it now sets
+var deprecatedFlags = map[string]string{
+	"sweep-format":   "output",
+	"sweep-output":   "out-file",
+	"compare-format": "output",
+	"compare-output": "out-file",
+}
+
+// Deprecated returns a warning for every deprecated flag that was set, on the command line,
+// in a config file or in the environment
+func (p *Parser) Deprecated() []string {
+	var warnings []string
+	p.flagSet.Visit(func(f *flag.Flag) {
+		if replacement, ok := deprecatedFlags[f.Name]; ok {
+			warnings = append(warnings, fmt.Sprintf("-%s is deprecated, use -%s", f.Name, replacement))
+		}
+	})
+	return warnings
+}
+
+// applyDeprecated keeps the CSV default of the deprecated -sweep-output and -compare-output:
+// a results file named by one of them without any format is written as CSV
+func (p *Parser) applyDeprecated() {
+	set := make(map[string]bool)
+	p.flagSet.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	if (set["sweep-output"] || set["compare-output"]) && !set["output"] && !set["sweep-format"] && !set["compare-format"] {
+		p.config.Simulation.Output = "csv"
+	}
+}
+
+// ignoredValue is a flag value that accepts and discards any value
+type ignoredValue struct {
+	isBool bool
+}
+
+func (v ignoredValue) String() string   { return "" }
+func (v ignoredValue) Set(string) error { return nil }
+func (v ignoredValue) IsBoolFlag() bool { return v.isBool }
+
+// isBoolFlag reports whether a flag takes no value, like -graph
+func isBoolFlag(f *flag.Flag) bool {
+	boolFlag, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && boolFlag.IsBoolFlag()
+}
+
+// stringList is a flag value that collects every occurrence of a repeated flag
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, " ")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *stringList) Get() interface{} {
+	return append([]string{}, *l...)
+}
+
 // Parse parses command-line arguments and returns configuration
 func (p *Parser) Parse(args []string) (*Config, error) {
-	p.RegisterFlags()
+	if !p.registered {
+		p.RegisterFlags()
+	}
+
+	// Settings are applied from lowest to highest precedence: defaults, config file, profile,
+	// environment variables, then flags
+	if err := p.applySources(args); err != nil {
+		return nil, err
+	}
 
 	if err := p.flagSet.Parse(args); err != nil {
 		return nil, fmt.Errorf("failed to parse flags: %w", err)
 	}
 
+	p.applyDeprecated()
+	s := &p.config.Simulation
+	s.splitAdjusterTypes()
+
+	// The caller shows the help, e.g. the CLI prints the command's usage
 	if p.config.Simulation.ShowHelp {
-		p.ShowDetailedHelp()
 		return p.config, nil
 	}
 
@@ -194,344 +593,577 @@ func (p *Parser) Parse(args []string) (*Config, error) {
 	return p.config, nil
 }
 
-// Validate validates the configuration parameters
-func (p *Parser) Validate() error {
-	c := p.config
-	s := &p.config.Simulation
-	a := &p.config.Adjuster
-
-	// Validate adjuster type
-	validAdjusterTypes := []string{"aimd", "eip1559", "eip-1559", "pid"}
-	isValidAdjusterType := false
-	for _, validType := range validAdjusterTypes {
-		if s.AdjusterType == validType {
-			isValidAdjusterType = true
-			break
-		}
-	}
-	if !isValidAdjusterType {
-		return fmt.Errorf("invalid adjuster type '%s', must be one of: %v", s.AdjusterType, validAdjusterTypes)
+// applySources applies the config file, its selected profile and environment variables.
+// The file and profile are chosen by -config and -profile, or FEEMARKETSIM_CONFIG and
+// FEEMARKETSIM_PROFILE when those flags are absent.
+func (p *Parser) applySources(args []string) error {
+	// A first pass over the flags finds the file and profile, and which flags will override
+	pre := &Parser{
+		config:  &Config{},
+		flagSet: flag.NewFlagSet("pre", flag.ContinueOnError),
 	}
+	pre.flagSet.SetOutput(io.Discard)
+	pre.RegisterFlags()
+	p.flagSet.VisitAll(func(f *flag.Flag) {
+		// Flags a command added are skipped over without being set twice
+		if pre.flagSet.Lookup(f.Name) == nil {
+			pre.flagSet.Var(ignoredValue{isBool: isBoolFlag(f)}, f.Name, f.Usage)
+		}
+	})
+	_ = pre.flagSet.Parse(args) // Errors are reported by the real parse
 
-	// Core parameter validation (applies to all algorithms)
-	if c.BurstMultiplier <= 1.0 {
-		return fmt.Errorf("burst multiplier (%.3f) must be greater than 1.0", c.BurstMultiplier)
+	configFile := pre.config.Simulation.ConfigFile
+	if configFile == "" {
+		configFile = os.Getenv(EnvVar("config"))
 	}
-
-	// Randomizer validation
-	if err := p.validateRandomizerParameters(s); err != nil {
-		return err
+	profile := pre.config.Simulation.Profile
+	if profile == "" {
+		profile = os.Getenv(EnvVar("profile"))
 	}
 
-	// Algorithm-specific validation
-	switch s.AdjusterType {
-	case "aimd":
-		// Validate AIMD-specific parameters
-		if err := p.validateAIMDParameters(a); err != nil {
+	if configFile != "" {
+		file, err := LoadFile(configFile)
+		if err != nil {
 			return err
 		}
-
-	case "eip1559", "eip-1559":
-		// Validate EIP-1559 parameters
-		if a.EIP1559.MaxFeeChange <= 0 || a.EIP1559.MaxFeeChange > 1.0 {
-			return fmt.Errorf("EIP-1559 max fee change (%.3f) must be between 0 and 1.0", a.EIP1559.MaxFeeChange)
-		}
-
-	case "pid":
-		// Validate PID parameters
-		if err := p.validatePIDParameters(a); err != nil {
+		if err := applySettings(p.flagSet, configFile, file.settings); err != nil {
 			return err
 		}
-	}
-
-	// Scenario validation
-	validScenarios := []string{"all", "full", "empty", "stable", "mixed"}
-	isValid := false
-	for _, valid := range validScenarios {
-		if s.Scenario == valid {
-			isValid = true
-			break
+		if profile != "" {
+			settings, err := file.profile(profile)
+			if err != nil {
+				return err
+			}
+			if err := applySettings(p.flagSet, configFile+" profile "+profile, settings); err != nil {
+				return err
+			}
 		}
+	} else if profile != "" {
+		return fmt.Errorf("profile %s requires a config file (-config)", profile)
 	}
-	if !isValid {
-		return fmt.Errorf("invalid scenario '%s', must be one of: %v", s.Scenario, validScenarios)
+	p.config.Simulation.ConfigFile = configFile
+	p.config.Simulation.Profile = profile
+
+	if err := applySettings(p.flagSet, "environment", envSettings(p.flagSet)); err != nil {
+		return err
 	}
 
+	// Repeatable flags given on the command line replace values from every other source
+	pre.flagSet.Visit(func(f *flag.Flag) {
+		if list, ok := p.flagSet.Lookup(f.Name).Value.(*stringList); ok {
+			*list = nil
+		}
+	})
 	return nil
 }
 
-// validateAIMDParameters validates AIMD-specific parameters
-func (p *Parser) validateAIMDParameters(a *AdjusterConfigs) error {
-	if a.AIMD.Gamma < 0 || a.AIMD.Gamma > 2.0 {
-		return fmt.Errorf("gamma (%.3f) must be between 0 and 2.0", a.AIMD.Gamma)
-	}
-
-	if a.AIMD.MaxLearningRate < a.AIMD.MinLearningRate {
-		return fmt.Errorf("max learning rate (%.6f) must be >= min learning rate (%.6f)",
-			a.AIMD.MaxLearningRate, a.AIMD.MinLearningRate)
+// SetParam sets a numeric parameter by its command-line flag name.
+// Values for integer parameters are rounded to the nearest integer.
+func SetParam(cfg *Config, name string, value float64) error {
+	p := &Parser{
+		config:  cfg,
+		flagSet: flag.NewFlagSet("param", flag.ContinueOnError),
 	}
+	p.RegisterFlags()
 
-	if a.AIMD.Alpha < 0 {
-		return fmt.Errorf("alpha (%.6f) must not be negative", a.AIMD.Alpha)
+	f := p.flagSet.Lookup(name)
+	if f == nil || !isNumericFlag(f) {
+		return fmt.Errorf("unknown numeric parameter: %s", name)
 	}
 
-	if a.AIMD.Beta < 0 || a.AIMD.Beta > 1 {
-		return fmt.Errorf("beta (%.6f) must be between 0 and 1", a.AIMD.Beta)
+	text := strconv.FormatFloat(value, 'g', -1, 64)
+	if _, isFloat := f.Value.(flag.Getter).Get().(float64); !isFloat {
+		text = strconv.FormatFloat(math.Round(value), 'f', 0, 64)
 	}
 
-	if p.config.WindowSize <= 0 {
-		return fmt.Errorf("window size (%d) must be positive", p.config.WindowSize)
+	if err := f.Value.Set(text); err != nil {
+		return fmt.Errorf("invalid value %s for %s: %w", text, name, err)
 	}
-
 	return nil
 }
 
-// validatePIDParameters validates PID-specific parameters
-func (p *Parser) validatePIDParameters(a *AdjusterConfigs) error {
-	if a.PID.Kp < 0 {
-		return fmt.Errorf("PID Kp (%.6f) must not be negative", a.PID.Kp)
-	}
-	if a.PID.Ki < 0 {
-		return fmt.Errorf("PID Ki (%.6f) must not be negative", a.PID.Ki)
-	}
-	if a.PID.Kd < 0 {
-		return fmt.Errorf("PID Kd (%.6f) must not be negative", a.PID.Kd)
+// GetParam returns a numeric parameter by its command-line flag name
+func GetParam(cfg Config, name string) (float64, error) {
+	p := &Parser{
+		config:  &cfg,
+		flagSet: flag.NewFlagSet("param", flag.ContinueOnError),
 	}
-	if a.PID.MaxIntegral <= a.PID.MinIntegral {
-		return fmt.Errorf("PID max integral (%.3f) must be greater than min integral (%.3f)", a.PID.MaxIntegral, a.PID.MinIntegral)
-	}
-	if a.PID.MaxFeeChange <= 0 || a.PID.MaxFeeChange > 1.0 {
-		return fmt.Errorf("PID max fee change (%.3f) must be between 0 and 1.0", a.PID.MaxFeeChange)
+	p.RegisterFlags()
+
+	f := p.flagSet.Lookup(name)
+	if f == nil || !isNumericFlag(f) {
+		return 0, fmt.Errorf("unknown numeric parameter: %s", name)
 	}
-	if p.config.WindowSize <= 0 {
-		return fmt.Errorf("PID window size (%d) must be positive", p.config.WindowSize)
+
+	switch value := f.Value.(flag.Getter).Get().(type) {
+	case float64:
+		return value, nil
+	case int:
+		return float64(value), nil
+	case int64:
+		return float64(value), nil
+	case uint64:
+		return float64(value), nil
 	}
-	return nil
+	return 0, fmt.Errorf("unknown numeric parameter: %s", name)
 }
 
-// validateRandomizerParameters validates randomizer parameters
-func (p *Parser) validateRandomizerParameters(a *SimulationConfig) error {
-	if a.Randomizer.GaussianNoise < 0 || a.Randomizer.GaussianNoise > 1.0 {
-		return fmt.Errorf("randomizer gaussian noise (%.3f) must be between 0.0 and 1.0", a.Randomizer.GaussianNoise)
-	}
-	if a.Randomizer.BurstProbability < 0 || a.Randomizer.BurstProbability > 1.0 {
-		return fmt.Errorf("randomizer burst probability (%.3f) must be between 0.0 and 1.0", a.Randomizer.BurstProbability)
+// IsParam reports whether name is a numeric parameter accepted by SetParam
+func IsParam(name string) bool {
+	cfg := Default()
+	p := &Parser{
+		config:  &cfg,
+		flagSet: flag.NewFlagSet("param", flag.ContinueOnError),
 	}
+	p.RegisterFlags()
 
-	if a.Randomizer.BurstProbability > 0 {
-		if a.Randomizer.BurstDurationMin <= 0 {
-			return fmt.Errorf("randomizer burst duration min (%d) must be positive", a.Randomizer.BurstDurationMin)
-		}
-		if a.Randomizer.BurstDurationMax < a.Randomizer.BurstDurationMin {
-			return fmt.Errorf("randomizer burst duration max (%d) must be >= min (%d)", a.Randomizer.BurstDurationMax, a.Randomizer.BurstDurationMin)
-		}
-		if a.Randomizer.BurstIntensity <= 0 {
-			return fmt.Errorf("randomizer burst intensity (%.3f) must be positive", a.Randomizer.BurstIntensity)
-		}
+	f := p.flagSet.Lookup(name)
+	return f != nil && isNumericFlag(f)
+}
+
+// isNumericFlag reports whether a flag holds an integer or floating point value
+func isNumericFlag(f *flag.Flag) bool {
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
 	}
-	return nil
+	switch getter.Get().(type) {
+	case float64, int, int64, uint64:
+		return true
+	}
+	return false
 }
 
-// ShowDetailedHelp displays comprehensive help information
-func (p *Parser) ShowDetailedHelp() {
-	fmt.Println("AIMD Fee Market Simulation - Complete CLI Reference")
-	fmt.Println("================================================================================")
-	fmt.Println()
-
-	fmt.Println("OVERVIEW:")
-	fmt.Println("  The AIMD Fee Market Simulator provides three main operation modes:")
-	fmt.Println("  1. Basic AIMD Simulation - Test algorithm with synthetic scenarios")
-	fmt.Println("  2. Base Blockchain Data Fetching - Download real blockchain data")
-	fmt.Println("  3. Base Blockchain Simulation - Run AIMD against real data")
-	fmt.Println()
-
-	fmt.Println("COMMANDS:")
-	fmt.Println()
-
-	fmt.Println("Basic AIMD Simulation:")
-	fmt.Println("  feemarketsim [flags]                   # Run with synthetic scenarios")
-	fmt.Println("  feemarketsim -scenario=full -graph     # Run specific scenario with charts")
-	fmt.Println("  feemarketsim -help                     # Show this help")
-	fmt.Println()
-
-	fmt.Println("Base Blockchain Integration:")
-	fmt.Println("  feemarketsim fetch-base <start> <end> <file>  # Fetch blockchain data")
-	fmt.Println("    - Example: feemarketsim fetch-base 12000000 12000100 data.json")
-	fmt.Println("    - Downloads real Base blockchain data for analysis")
-	fmt.Println("    - Supports concurrent fetching with retry logic")
-	fmt.Println("    - Warns for large ranges (>10,000 blocks)")
-	fmt.Println()
-	fmt.Println("  feemarketsim simulate-base <file> [flags]     # Simulate against real data")
-	fmt.Println("    - Example: feemarketsim simulate-base data.json -graph -gamma=0.1")
-	fmt.Println("    - Runs fee adjustment algorithm against fetched blockchain data")
-	fmt.Println("    - Supports all relevant parameter flags based on selected algorithm")
-	fmt.Println("    - Generates comparison charts with -graph")
-	fmt.Println()
-
-	fmt.Println("ALGORITHM SELECTION:")
-	fmt.Println()
-	fmt.Println("  -adjuster-type=aimd          # AIMD (default) - Adaptive algorithm with learning")
-	fmt.Println("  -adjuster-type=eip1559       # EIP-1559 - Standard Ethereum mechanism")
-	fmt.Println("  -adjuster-type=pid           # PID Controller - Industrial control system")
-	fmt.Println()
-
-	fmt.Println("CORE PARAMETERS (apply to all algorithms):")
-	fmt.Println()
-
-	fmt.Println("Block Configuration:")
-	fmt.Println("  -target-block-size=15000000  Target block size in gas units")
-	fmt.Printf("                               Default: %d (%.1f M gas)\n", p.config.TargetBlockSize, float64(p.config.TargetBlockSize)/1e6)
-	fmt.Println("  -burst-multiplier=2.0        Max burst capacity as multiple of target")
-	fmt.Printf("                               Default: %.1f (%.1fx target capacity)\n", p.config.BurstMultiplier, p.config.BurstMultiplier)
-	fmt.Println()
-
-	fmt.Println("Fee Market Configuration:")
-	fmt.Println("  -initial-base-fee=1000000000 Initial base fee in wei")
-	fmt.Printf("                               Default: %d wei (%.3f Gwei)\n", p.config.InitialBaseFee, float64(p.config.InitialBaseFee)/1e9)
-	fmt.Println("  -min-base-fee=0              Minimum base fee in wei")
-	fmt.Printf("                               Default: %d wei (%.3f Gwei)\n", p.config.MinBaseFee, float64(p.config.MinBaseFee)/1e9)
-	fmt.Println()
-
-	fmt.Println("AIMD-SPECIFIC PARAMETERS (only for -adjuster-type=aimd or aimd-eip1559):")
-	fmt.Println()
-
-	fmt.Println("Learning System:")
-	fmt.Println("  -window-size=10              Number of blocks in analysis window (only appies to AIMD and PID)")
-	fmt.Printf("                               Default: %d blocks\n", p.config.WindowSize)
-	fmt.Println()
-
-	fmt.Println("AIMD Control:")
-	fmt.Println("  -aimd-alpha=0.01                  Additive increase factor")
-	fmt.Printf("                               Default: %.6f (learning rate increase)\n", p.config.Adjuster.AIMD.Alpha)
-	fmt.Println("  -aimd-beta=0.9                    Multiplicative decrease factor")
-	fmt.Printf("                               Default: %.3f (learning rate decay)\n", p.config.Adjuster.AIMD.Beta)
-	fmt.Println("  -aimd-delta=0.000001              Net gas delta coefficient")
-	fmt.Printf("                               Default: %.9f (window gas delta impact)\n", p.config.Adjuster.AIMD.Delta)
-	fmt.Println("  -aimd-gamma=0.2                   Threshold for learning rate adjustment")
-	fmt.Printf("                               Default: %.3f (deviation from target utilization)\n", p.config.Adjuster.AIMD.Gamma)
-	fmt.Println("  -aimd-initial-learning-rate=0.1   Initial learning rate")
-	fmt.Printf("                               Default: %.3f (%.1f%% initial adjustment)\n", p.config.Adjuster.AIMD.InitialLearningRate, p.config.Adjuster.AIMD.InitialLearningRate*100)
-	fmt.Println("  -aimd-max-learning-rate=0.5       Maximum learning rate")
-	fmt.Printf("                               Default: %.3f (%.1f%% maximum adjustment)\n", p.config.Adjuster.AIMD.MaxLearningRate, p.config.Adjuster.AIMD.MaxLearningRate*100)
-	fmt.Println("  -aimd-min-learning-rate=0.001     Minimum learning rate")
-	fmt.Printf("                               Default: %.6f (%.3f%% minimum adjustment)\n", p.config.Adjuster.AIMD.MinLearningRate, p.config.Adjuster.AIMD.MinLearningRate*100)
-	fmt.Println()
-
-	fmt.Println("EIP-1559 PARAMETERS (only for -adjuster-type=eip1559):")
-	fmt.Println()
-	fmt.Println("  -eip1559-max-fee-change=0.125  Maximum fee change per block")
-	fmt.Printf("                                 Default: %.3f (%.1f%% max change)\n", p.config.Adjuster.EIP1559.MaxFeeChange, p.config.Adjuster.EIP1559.MaxFeeChange*100)
-	fmt.Println()
-
-	fmt.Println("PID CONTROLLER PARAMETERS (only for -adjuster-type=pid):")
-	fmt.Println()
-	fmt.Println("  -pid-kp=0.1                  Proportional gain")
-	fmt.Printf("                               Default: %.3f\n", p.config.Adjuster.PID.Kp)
-	fmt.Println("  -pid-ki=0.01                 Integral gain")
-	fmt.Printf("                               Default: %.3f\n", p.config.Adjuster.PID.Ki)
-	fmt.Println("  -pid-kd=0.05                 Derivative gain")
-	fmt.Printf("                               Default: %.3f\n", p.config.Adjuster.PID.Kd)
-	fmt.Println("  -pid-max-fee-change=0.25     Maximum fee change per block")
-	fmt.Printf("                               Default: %.3f (%.1f%% max change)\n", p.config.Adjuster.PID.MaxFeeChange, p.config.Adjuster.PID.MaxFeeChange*100)
-	fmt.Println("  -pid-max-integral=1000       Maximum integral value (windup protection)")
-	fmt.Printf("                               Default: %.1f\n", p.config.Adjuster.PID.MaxIntegral)
-	fmt.Println("  -pid-min-integral=-1000      Minimum integral value (windup protection)")
-	fmt.Printf("                               Default: %.1f\n", p.config.Adjuster.PID.MinIntegral)
-	fmt.Println()
-
-	fmt.Println("SIMULATION CONTROL:")
-	fmt.Println()
-	fmt.Println("  -scenario=all                Scenario to run")
-	fmt.Printf("                               Default: %s\n", p.config.Simulation.Scenario)
-	fmt.Println("                               Options: full, empty, stable, mixed, all")
-	fmt.Println("                               - full:   Sustained high congestion (35 blocks)")
-	fmt.Println("                               - empty:  Sustained low demand (35 blocks)")
-	fmt.Println("                               - stable: Long-term stability (40 blocks)")
-	fmt.Println("                               - mixed:  Realistic traffic patterns (240 blocks)")
-	fmt.Println("                               - all:    Run all scenarios sequentially")
-	fmt.Println("  -graph                       Generate visualization charts (HTML files)")
-	fmt.Println("                               Creates fee evolution and comparison charts")
-	fmt.Println("  -log-scale                   Use logarithmic scale for Y-axis in charts")
-	fmt.Println("                               Useful when fees span multiple orders of magnitude")
-	fmt.Println()
-
-	fmt.Println("RANDOMIZER PARAMETERS (only when -enable-rng is used):")
-	fmt.Println()
-	fmt.Println("  -rng-seed=1234567890           Seed for randomizer")
-	fmt.Printf("                               Default: unix timestamp\n")
-	fmt.Println("  -rng-gaussian-noise=0.1       Gaussian noise level (0.0-1.0)")
-	fmt.Printf("                               Default: %.3f (%.1f%% variation)\n", p.config.Simulation.Randomizer.GaussianNoise, p.config.Simulation.Randomizer.GaussianNoise*100)
-	fmt.Println("  -rng-burst-probability=0.1    Probability of burst mode per block")
-	fmt.Printf("                               Default: %.2f (%.0f%% chance per block)\n", p.config.Simulation.Randomizer.BurstProbability, p.config.Simulation.Randomizer.BurstProbability*100)
-	fmt.Println("  -rng-burst-duration-min=2      Minimum burst duration in blocks")
-	fmt.Printf("                               Default: %d blocks\n", p.config.Simulation.Randomizer.BurstDurationMin)
-	fmt.Println("  -rng-burst-duration-max=5      Maximum burst duration in blocks")
-	fmt.Printf("                               Default: %d blocks\n", p.config.Simulation.Randomizer.BurstDurationMax)
-	fmt.Println("  -rng-burst-intensity=1.5       Gas usage multiplier during bursts")
-	fmt.Printf("                               Default: %.1f (%.0f%% of normal)\n", p.config.Simulation.Randomizer.BurstIntensity, p.config.Simulation.Randomizer.BurstIntensity*100)
-	fmt.Println()
-
-	fmt.Println("EXAMPLE WORKFLOWS:")
-	fmt.Println()
-
-	fmt.Println("Quick Start:")
-	fmt.Println("  feemarketsim                           # Run AIMD with default settings")
-	fmt.Println("  feemarketsim -adjuster-type=eip1559    # Use EIP-1559 algorithm")
-	fmt.Println("  feemarketsim -adjuster-type=pid        # Use PID controller")
-	fmt.Println("  feemarketsim -scenario=mixed -graph    # Test mixed traffic with charts")
-	fmt.Println("  feemarketsim -help                     # Show this help")
-	fmt.Println()
-
-	fmt.Println("Algorithm Comparison:")
-	fmt.Println("  # Compare different algorithms on same scenario")
-	fmt.Println("  feemarketsim -adjuster-type=aimd -scenario=mixed -graph")
-	fmt.Println("  feemarketsim -adjuster-type=eip1559 -scenario=mixed -graph")
-	fmt.Println("  feemarketsim -adjuster-type=pid -scenario=mixed -graph")
-	fmt.Println("  feemarketsim -adjuster-type=aimd-eip1559 -scenario=mixed -graph")
-	fmt.Println()
-
-	fmt.Println("AIMD Parameter Testing:")
-	fmt.Println("  # Test learning strategies")
-	fmt.Println("  feemarketsim -adjuster-type=aimd -aimd-gamma=0.1 -aimd-alpha=0.02    # Aggressive")
-	fmt.Println("  feemarketsim -adjuster-type=aimd -aimd-gamma=0.5 -aimd-alpha=0.005   # Conservative")
-	fmt.Println("  # Test window sizes")
-	fmt.Println("  feemarketsim -adjuster-type=aimd -window-size=5                      # Fast response")
-	fmt.Println("  feemarketsim -adjuster-type=aimd -window-size=20                     # Stable response")
-	fmt.Println()
-
-	fmt.Println("PID Controller Tuning:")
-	fmt.Println("  # Test different PID gains")
-	fmt.Println("  feemarketsim -adjuster-type=pid -pid-kp=0.2                # More aggressive P")
-	fmt.Println("  feemarketsim -adjuster-type=pid -pid-ki=0.05               # Higher integral gain")
-	fmt.Println("  feemarketsim -adjuster-type=pid -pid-kd=0.1                # More derivative action")
-	fmt.Println()
-
-	fmt.Println("Advanced Randomness:")
-	fmt.Println("  # Test with RNG")
-	fmt.Println("  feemarketsim -rng-gaussian-noise=0.1                                                                                  # 10% gas variation")
-	fmt.Println("  feemarketsim -rng-burst-probability=0.1 -rng-burst-duration-min=2 -rng-burst-duration-max=5 -rng-burst-intensity=1.5  # Add burst periods")
-	fmt.Println()
-
-	fmt.Println("Real Data Analysis:")
-	fmt.Println("  # 1. Fetch blockchain data")
-	fmt.Println("  feemarketsim fetch-base 12000000 12001000 analysis.json")
-	fmt.Println()
-	fmt.Println("  # 2. Test different algorithms")
-	fmt.Println("  feemarketsim simulate-base analysis.json -adjuster-type=aimd -graph")
-	fmt.Println("  feemarketsim simulate-base analysis.json -adjuster-type=eip1559 -graph")
-	fmt.Println("  feemarketsim simulate-base analysis.json -adjuster-type=pid -graph")
-	fmt.Println()
-	fmt.Println("  # 3. Fine-tune parameters")
-	fmt.Println("  feemarketsim simulate-base analysis.json -adjuster-type=aimd -aimd-gamma=0.1 -graph")
-	fmt.Println("  feemarketsim simulate-base analysis.json -adjuster-type=pid -pid-kp=0.15 -graph")
-	fmt.Println()
-
-	fmt.Println("OUTPUT FILES:")
-	fmt.Println("  When -graph is enabled, the following files are generated:")
-	fmt.Println("  - chart_<scenario>.html            Fee evolution charts")
-	fmt.Println("  - base_comparison_<range>.html     Algorithm vs Base fee comparison")
-	fmt.Println("  - base_comparison_<range>_gas.html Gas usage analysis")
-	fmt.Println()
-
-	fmt.Println("For detailed algorithm descriptions, see the project README.md file.")
+// ShowDetailedHelp writes comprehensive help information to w
+func (p *Parser) ShowDetailedHelp(w io.Writer) {
+	fmt.Fprintln(w, "AIMD Fee Market Simulation - Complete CLI Reference")
+	fmt.Fprintln(w, "================================================================================")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "OVERVIEW:")
+	fmt.Fprintln(w, "  The AIMD Fee Market Simulator provides three main operation modes:")
+	fmt.Fprintln(w, "  1. Basic AIMD Simulation - Test algorithm with synthetic scenarios")
+	fmt.Fprintln(w, "  2. Base Blockchain Data Fetching - Download real blockchain data")
+	fmt.Fprintln(w, "  3. Base Blockchain Simulation - Run AIMD against real data")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "COMMANDS:")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Basic AIMD Simulation:")
+	fmt.Fprintln(w, "  feemarketsim [run] [flags]             # Run with synthetic scenarios")
+	fmt.Fprintln(w, "  feemarketsim -scenario=full -graph     # Run specific scenario with charts")
+	fmt.Fprintln(w, "  feemarketsim help                      # List every command")
+	fmt.Fprintln(w, "  feemarketsim help flags                # Show this help")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Base Blockchain Integration:")
+	fmt.Fprintln(w, "  feemarketsim fetch <start> <end> <file>       # Fetch blockchain data")
+	fmt.Fprintln(w, "    - Example: feemarketsim fetch 12000000 12000100 data.json")
+	fmt.Fprintln(w, "    - Downloads real Base blockchain data for analysis")
+	fmt.Fprintln(w, "    - Supports concurrent fetching with retry logic")
+	fmt.Fprintln(w, "    - Warns for large ranges (>10,000 blocks)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  feemarketsim replay <file> [flags]             # Simulate against real data")
+	fmt.Fprintln(w, "    - Example: feemarketsim replay data.json -graph -aimd-gamma=0.1")
+	fmt.Fprintln(w, "    - Runs fee adjustment algorithm against fetched blockchain data")
+	fmt.Fprintln(w, "    - Supports all relevant parameter flags based on selected algorithm")
+	fmt.Fprintln(w, "    - Generates comparison charts with -graph")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Adversarial Analysis:")
+	fmt.Fprintln(w, "  feemarketsim attack [flags]                   # Simulate a block-producing attacker")
+	fmt.Fprintln(w, "    - Example: feemarketsim attack -attack-share=0.3 -attack-strategy=stuff")
+	fmt.Fprintln(w, "    - Runs every fee adjuster against the same attacker schedule")
+	fmt.Fprintln(w, "    - Reports attacker cost and base fee distortion vs an honest baseline")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim search [flags]                   # Search for worst-case demand")
+	fmt.Fprintln(w, "    - Example: feemarketsim search -adjuster-type=aimd -search-metric=peak-to-trough -graph")
+	fmt.Fprintln(w, "    - Hill climbing or genetic search over bounded gas usage sequences")
+	fmt.Fprintln(w, "    - Writes the worst sequence as a scenario file for -scenario-file")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim montecarlo [flags]               # Run many seeds and aggregate metrics")
+	fmt.Fprintln(w, "    - Example: feemarketsim montecarlo -mc-runs=500 -rng-gaussian-noise=0.1 -graph")
+	fmt.Fprintln(w, "    - Seeds run from -rng-seed to -rng-seed + runs - 1, in parallel")
+	fmt.Fprintln(w, "    - Reports mean, std dev and percentile bands with fee fan charts")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim sweep [flags]                    # Evaluate a grid or sample of parameters")
+	fmt.Fprintln(w, "    - Example: feemarketsim sweep -adjuster-type=aimd -sweep-param=aimd-gamma=0.05:0.5:0.05")
+	fmt.Fprintln(w, "    - Runs every point across the selected scenarios and -dataset files in parallel")
+	fmt.Fprintln(w, "    - Writes all analysis metrics per point and workload as CSV or JSON")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim pareto [flags]                   # Find the non-dominated parameter set")
+	fmt.Fprintln(w, "    - Example: feemarketsim pareto -adjuster-type=aimd -sweep-mode=random -sweep-param=aimd-gamma=0.01:1 -graph")
+	fmt.Fprintln(w, "    - Evaluates the sweep population and keeps points no other point beats on every metric")
+	fmt.Fprintln(w, "    - Interactive scatter with -graph; click a point to copy its flags")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim response [flags]                 # Step, impulse and ramp response of every algorithm")
+	fmt.Fprintln(w, "    - Example: feemarketsim response -response-step=3 -graph")
+	fmt.Fprintln(w, "    - Demand follows a constant-elasticity curve, so the fee has an equilibrium to settle to")
+	fmt.Fprintln(w, "    - Reports rise time, overshoot, settling time, steady-state error and decay ratio")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim frequency [flags]                # Bode-style gain and phase versus demand period")
+	fmt.Fprintln(w, "    - Example: feemarketsim frequency -response-periods=10,100,1800 -graph")
+	fmt.Fprintln(w, "    - Drives every algorithm with sinusoidal demand and measures amplification and lag")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim equilibrium [flags]              # Long-run fee and utilization under fixed demand")
+	fmt.Fprintln(w, "    - Example: feemarketsim equilibrium -eq-demand=elastic:2:0.5 -graph")
+	fmt.Fprintln(w, "    - Classifies each algorithm as converged, converging, limit cycle or diverging")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim significance [flags]             # Test whether two algorithms differ beyond noise")
+	fmt.Fprintln(w, "    - Example: feemarketsim significance -adjuster-type=aimd -sig-against=eip1559 -rng-gaussian-noise=0.1")
+	fmt.Fprintln(w, "    - Pairs both algorithms on the same Monte Carlo seeds and dataset segments")
+	fmt.Fprintln(w, "    - Reports bootstrap confidence intervals, Wilcoxon signed-rank p-values and a verdict per metric")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim sensitivity [flags]              # Rank parameters by their global influence")
+	fmt.Fprintln(w, "    - Example: feemarketsim sensitivity -adjuster-type=aimd -sens-method=sobol -graph")
+	fmt.Fprintln(w, "    - Morris elementary effects or Sobol first-order and total-effect indices")
+	fmt.Fprintln(w, "    - Varies every parameter at once, so interactions are captured")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "  feemarketsim tune [flags]                     # Optimize parameters for an objective")
+	fmt.Fprintln(w, "    - Example: feemarketsim tune -adjuster-type=pid -tune-param=pid-kp=0.001:1 -dataset=data.json")
+	fmt.Fprintln(w, "    - Nelder-Mead or random search over a weighted sum of analysis metrics")
+	fmt.Fprintln(w, "    - Reports the objective on held-out scenarios and dataset segments")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "ALGORITHM SELECTION:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -adjuster-type=aimd          # AIMD (default) - Adaptive algorithm with learning")
+	fmt.Fprintln(w, "  -adjuster-type=eip1559       # EIP-1559 - Standard Ethereum mechanism")
+	fmt.Fprintln(w, "  -adjuster-type=pid           # PID Controller - Industrial control system")
+	fmt.Fprintln(w, "  -adjuster-type=aimd,eip1559,pid")
+	fmt.Fprintln(w, "                               # Compare algorithms side by side on identical scenarios")
+	fmt.Fprintln(w, "                               # (run and replay)")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "CORE PARAMETERS (apply to all algorithms):")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Block Configuration:")
+	fmt.Fprintln(w, "  -target-block-size=15000000  Target block size in gas units (also 15M or 15Mgas)")
+	fmt.Fprintf(w, "                               Default: %d (%.1f M gas)\n", p.config.TargetBlockSize, float64(p.config.TargetBlockSize)/1e6)
+	fmt.Fprintln(w, "  -burst-multiplier=2.0        Max burst capacity as multiple of target")
+	fmt.Fprintf(w, "                               Default: %.1f (%.1fx target capacity)\n", p.config.BurstMultiplier, p.config.BurstMultiplier)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Fee Market Configuration:")
+	fmt.Fprintln(w, "  -initial-base-fee=1000000000 Initial base fee in wei")
+	fmt.Fprintf(w, "                               Default: %d wei (%s)\n", p.config.InitialBaseFee, FormatGwei(float64(p.config.InitialBaseFee)))
+	fmt.Fprintln(w, "  -min-base-fee=0              Minimum base fee in wei")
+	fmt.Fprintf(w, "                               Default: %d wei (%s)\n", p.config.MinBaseFee, FormatGwei(float64(p.config.MinBaseFee)))
+	fmt.Fprintln(w, "                               Fees also take units, e.g. 1gwei, 0.05gwei or 250000000wei")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "AIMD-SPECIFIC PARAMETERS (only for -adjuster-type=aimd or aimd-eip1559):")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Learning System:")
+	fmt.Fprintln(w, "  -window-size=10              Number of blocks in analysis window (only appies to AIMD and PID)")
+	fmt.Fprintf(w, "                               Default: %d blocks\n", p.config.WindowSize)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "AIMD Control:")
+	fmt.Fprintln(w, "  -aimd-alpha=0.01                  Additive increase factor")
+	fmt.Fprintf(w, "                               Default: %.6f (learning rate increase)\n", p.config.Adjuster.AIMD.Alpha)
+	fmt.Fprintln(w, "  -aimd-beta=0.9                    Multiplicative decrease factor")
+	fmt.Fprintf(w, "                               Default: %.3f (learning rate decay)\n", p.config.Adjuster.AIMD.Beta)
+	fmt.Fprintln(w, "  -aimd-delta=0.000001              Net gas delta coefficient")
+	fmt.Fprintf(w, "                               Default: %.9f (window gas delta impact)\n", p.config.Adjuster.AIMD.Delta)
+	fmt.Fprintln(w, "  -aimd-gamma=0.2                   Threshold for learning rate adjustment")
+	fmt.Fprintf(w, "                               Default: %.3f (deviation from target utilization)\n", p.config.Adjuster.AIMD.Gamma)
+	fmt.Fprintln(w, "  -aimd-initial-learning-rate=0.1   Initial learning rate")
+	fmt.Fprintf(w, "                               Default: %.3f (%.1f%% initial adjustment)\n", p.config.Adjuster.AIMD.InitialLearningRate, p.config.Adjuster.AIMD.InitialLearningRate*100)
+	fmt.Fprintln(w, "  -aimd-max-learning-rate=0.5       Maximum learning rate")
+	fmt.Fprintf(w, "                               Default: %.3f (%.1f%% maximum adjustment)\n", p.config.Adjuster.AIMD.MaxLearningRate, p.config.Adjuster.AIMD.MaxLearningRate*100)
+	fmt.Fprintln(w, "  -aimd-min-learning-rate=0.001     Minimum learning rate")
+	fmt.Fprintf(w, "                               Default: %.6f (%.3f%% minimum adjustment)\n", p.config.Adjuster.AIMD.MinLearningRate, p.config.Adjuster.AIMD.MinLearningRate*100)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "EIP-1559 PARAMETERS (only for -adjuster-type=eip1559):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -eip1559-max-fee-change=0.125  Maximum fee change per block")
+	fmt.Fprintf(w, "                                 Default: %.3f (%.1f%% max change)\n", p.config.Adjuster.EIP1559.MaxFeeChange, p.config.Adjuster.EIP1559.MaxFeeChange*100)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "PID CONTROLLER PARAMETERS (only for -adjuster-type=pid):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -pid-kp=0.1                  Proportional gain")
+	fmt.Fprintf(w, "                               Default: %.3f\n", p.config.Adjuster.PID.Kp)
+	fmt.Fprintln(w, "  -pid-ki=0.01                 Integral gain")
+	fmt.Fprintf(w, "                               Default: %.3f\n", p.config.Adjuster.PID.Ki)
+	fmt.Fprintln(w, "  -pid-kd=0.05                 Derivative gain")
+	fmt.Fprintf(w, "                               Default: %.3f\n", p.config.Adjuster.PID.Kd)
+	fmt.Fprintln(w, "  -pid-max-fee-change=0.25     Maximum fee change per block")
+	fmt.Fprintf(w, "                               Default: %.3f (%.1f%% max change)\n", p.config.Adjuster.PID.MaxFeeChange, p.config.Adjuster.PID.MaxFeeChange*100)
+	fmt.Fprintln(w, "  -pid-max-integral=1000       Maximum integral value (windup protection)")
+	fmt.Fprintf(w, "                               Default: %.1f\n", p.config.Adjuster.PID.MaxIntegral)
+	fmt.Fprintln(w, "  -pid-min-integral=-1000      Minimum integral value (windup protection)")
+	fmt.Fprintf(w, "                               Default: %.1f\n", p.config.Adjuster.PID.MinIntegral)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SIMULATION CONTROL:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -scenario=all                Scenario to run")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Scenario)
+	fmt.Fprintln(w, "                               Options: full, empty, stable, mixed, all")
+	fmt.Fprintln(w, "                               - full:   Sustained high congestion (35 blocks)")
+	fmt.Fprintln(w, "                               - empty:  Sustained low demand (35 blocks)")
+	fmt.Fprintln(w, "                               - stable: Long-term stability (40 blocks)")
+	fmt.Fprintln(w, "                               - mixed:  Realistic traffic patterns (240 blocks)")
+	fmt.Fprintln(w, "                               - all:    Run all scenarios sequentially")
+	fmt.Fprintln(w, "                               - none:   No synthetic scenarios (only -dataset files)")
+	fmt.Fprintln(w, "  -scenario-file=<file>        Run a scenario loaded from a JSON file")
+	fmt.Fprintln(w, "                               Overrides -scenario (e.g. output of the search command)")
+	fmt.Fprintln(w, "  -dataset=<file>              Blockchain dataset to evaluate (repeatable; sweep, tune, significance)")
+	fmt.Fprintln(w, "  -graph                       Generate visualization charts (HTML files)")
+	fmt.Fprintln(w, "                               Creates fee evolution and comparison charts")
+	fmt.Fprintln(w, "  -log-scale                   Use logarithmic scale for Y-axis in charts")
+	fmt.Fprintln(w, "                               Useful when fees span multiple orders of magnitude")
+	fmt.Fprintln(w, "  -output=text                 Results format: text, json, csv or markdown")
+	fmt.Fprintln(w, "                               Progress and notes always go to stderr")
+	fmt.Fprintln(w, "  -out-file=<file>             Write the results to a file instead of stdout")
+	fmt.Fprintln(w, "  -manifest=<file>             Record the configuration, seed, build and input and output hashes")
+	fmt.Fprintln(w, "                               of the run; 'feemarketsim rerun <file>' reproduces and checks it")
+	fmt.Fprintln(w, "  -log-level=info              Diagnostics logged to stderr: debug, info, warn or error")
+	fmt.Fprintln(w, "                               debug adds every simulated block and controller internals")
+	fmt.Fprintln(w, "  -log-format=text             Diagnostics format: text (key=value) or json (one object per line)")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "CONFIGURATION FILES:")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -config=<file>               JSON or TOML (.toml) file; keys are flag names, and nested")
+	fmt.Fprintln(w, "                               tables join with \"-\" ([aimd] gamma = 0.1 sets -aimd-gamma)")
+	fmt.Fprintln(w, "  -profile=<name>              Apply [profiles.<name>] from the file on top of its settings")
+	fmt.Fprintln(w, "  -dump-config                 Print the resolved configuration in the file's format and exit")
+	fmt.Fprintf(w, "  %-29sEnvironment variable per flag, e.g. %s\n", EnvPrefix+"<FLAG>", EnvVar("aimd-gamma"))
+	fmt.Fprintln(w, "                               Precedence: defaults < file < profile < environment < flags")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "RANDOMIZER PARAMETERS (only when -enable-rng is used):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -rng-seed=1234567890           Seed for randomizer")
+	fmt.Fprintf(w, "                               Default: unix timestamp\n")
+	fmt.Fprintln(w, "  -rng-gaussian-noise=0.1       Gaussian noise level (0.0-1.0)")
+	fmt.Fprintf(w, "                               Default: %.3f (%.1f%% variation)\n", p.config.Simulation.Randomizer.GaussianNoise, p.config.Simulation.Randomizer.GaussianNoise*100)
+	fmt.Fprintln(w, "  -rng-burst-probability=0.1    Probability of burst mode per block")
+	fmt.Fprintf(w, "                               Default: %.2f (%.0f%% chance per block)\n", p.config.Simulation.Randomizer.BurstProbability, p.config.Simulation.Randomizer.BurstProbability*100)
+	fmt.Fprintln(w, "  -rng-burst-duration-min=2      Minimum burst duration in blocks")
+	fmt.Fprintf(w, "                               Default: %d blocks\n", p.config.Simulation.Randomizer.BurstDurationMin)
+	fmt.Fprintln(w, "  -rng-burst-duration-max=5      Maximum burst duration in blocks")
+	fmt.Fprintf(w, "                               Default: %d blocks\n", p.config.Simulation.Randomizer.BurstDurationMax)
+	fmt.Fprintln(w, "  -rng-burst-intensity=1.5       Gas usage multiplier during bursts")
+	fmt.Fprintf(w, "                               Default: %.1f (%.0f%% of normal)\n", p.config.Simulation.Randomizer.BurstIntensity, p.config.Simulation.Randomizer.BurstIntensity*100)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "ATTACK PARAMETERS (only for the attack command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -attack-share=0.2              Fraction of blocks produced by the attacker")
+	fmt.Fprintf(w, "                               Default: %.2f (%.0f%% of blocks)\n", p.config.Simulation.Attack.Share, p.config.Simulation.Attack.Share*100)
+	fmt.Fprintln(w, "  -attack-strategy=stuff         stuff: fill own blocks to push fees up")
+	fmt.Fprintln(w, "                                 drain: produce empty blocks to push fees down")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Attack.Strategy)
+	fmt.Fprintln(w, "  -attack-land-every=5           Drain: land own transactions every Nth attacker block")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Attack.LandEvery)
+	fmt.Fprintln(w, "  -attack-landing-gas=5000000    Drain: gas landed per landing block")
+	fmt.Fprintf(w, "                               Default: %d gas\n", p.config.Simulation.Attack.LandingGas)
+	fmt.Fprintln(w, "  -attack-priority-fee=10000000  Tip in wei forgone per unit of excluded honest gas")
+	fmt.Fprintf(w, "                               Default: %d wei (%s)\n", p.config.Simulation.Attack.PriorityFee, FormatGwei(float64(p.config.Simulation.Attack.PriorityFee)))
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SEARCH PARAMETERS (only for the search command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -search-metric=volatility      Metric to maximize")
+	fmt.Fprintln(w, "                                 volatility:     std dev of block-to-block log fee changes")
+	fmt.Fprintln(w, "                                 peak-to-trough: max base fee / min base fee")
+	fmt.Fprintln(w, "                                 min-fee-time:   fraction of blocks at the fee floor")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Search.Metric)
+	fmt.Fprintln(w, "  -search-method=hill            hill (adaptive hill climbing) or genetic")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Search.Method)
+	fmt.Fprintln(w, "  -search-iterations=500         Hill climbing steps or genetic generations")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Search.Iterations)
+	fmt.Fprintln(w, "  -search-population=32          Genetic: population size")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Search.Population)
+	fmt.Fprintln(w, "  -search-blocks=100             Length of the searched sequence")
+	fmt.Fprintf(w, "                               Default: %d blocks\n", p.config.Simulation.Search.Blocks)
+	fmt.Fprintln(w, "  -search-min-gas=0              Lower bound on gas used per block")
+	fmt.Fprintln(w, "  -search-max-gas=0              Upper bound on gas used per block (0 = burst capacity)")
+	fmt.Fprintln(w, "  -search-mutation=0.1           Mutation step as a fraction of the gas range")
+	fmt.Fprintf(w, "                               Default: %.2f\n", p.config.Simulation.Search.Mutation)
+	fmt.Fprintln(w, "  -search-output=<file>          Scenario file to write")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Search.Output)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "MONTE CARLO PARAMETERS (only for the montecarlo command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -mc-runs=100                   Number of seeds to run")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.MonteCarlo.Runs)
+	fmt.Fprintln(w, "  -mc-workers=0                  Runs executed in parallel (0 = one per CPU)")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.MonteCarlo.Workers)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SWEEP PARAMETERS (only for the sweep command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -sweep-param=<name>=<range>    Parameter to sweep by flag name (repeatable)")
+	fmt.Fprintln(w, "                                 start:stop:step   e.g. aimd-gamma=0.05:0.5:0.05")
+	fmt.Fprintln(w, "                                 log(min,max,n)    e.g. pid-kp=log(0.001,1,20)")
+	fmt.Fprintln(w, "                                 lin(min,max,n)    e.g. aimd-beta=lin(0.5,0.95,10)")
+	fmt.Fprintln(w, "                                 v1,v2,...         e.g. window-size=5,10,20")
+	fmt.Fprintln(w, "  -sweep-mode=grid               grid (every combination) or random (sampled points)")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Sweep.Mode)
+	fmt.Fprintln(w, "  -sweep-samples=100             Random: number of sampled points")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Sweep.Samples)
+	fmt.Fprintln(w, "  -sweep-workers=0               Points evaluated in parallel (0 = one per CPU)")
+	fmt.Fprintln(w, "                               Results follow -output and -out-file, one row per point and case")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "PARETO PARAMETERS (only for the pareto command, which also uses the sweep parameters):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -pareto-metrics=<terms>        Two or three metric:min or metric:max terms")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Pareto.Metrics)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "RESPONSE PARAMETERS (only for the response and frequency commands):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -response-blocks=200           Blocks simulated per test input (input at block 20)")
+	fmt.Fprintln(w, "  -response-step=2.0             Demand multiple after the step and at the end of the ramp")
+	fmt.Fprintln(w, "  -response-elasticity=1.0       Price elasticity of demand (1% higher fee = 1% less gas)")
+	fmt.Fprintln(w, "  -response-tolerance=0.05       Settling band as a fraction of the equilibrium fee")
+	fmt.Fprintln(w, "  -response-periods=<list>       Frequency: comma-separated demand periods in blocks")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Response.Periods)
+	fmt.Fprintln(w, "  -response-amplitude=0.2        Frequency: demand oscillation as a fraction of reference demand")
+	fmt.Fprintln(w, "  -response-cycles=8             Frequency: cycles measured per period (after 2 warm-up cycles)")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "EQUILIBRIUM PARAMETERS (only for the equilibrium command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -eq-demand=<curve>             Demand curve; gas in multiples of target, fees in Gwei")
+	fmt.Fprintln(w, "                                 elastic:scale:elasticity   scale x target at the initial fee")
+	fmt.Fprintln(w, "                                 linear:gas:choke           gas x target at zero fee, none at choke")
+	fmt.Fprintln(w, "                                 points:fee=gas,...         interpolated, e.g. points:0.5=1.8,1=1.2,2=0.6")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Equilibrium.Demand)
+	fmt.Fprintln(w, "  -eq-blocks=2000                Blocks simulated")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SIGNIFICANCE PARAMETERS (only for the significance command, which also uses -mc-runs):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -sig-against=eip1559           Algorithm compared with -adjuster-type")
+	fmt.Fprintln(w, "  -sig-metrics=<metrics>         Comma-separated metrics to test")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Significance.Metrics)
+	fmt.Fprintln(w, "  -sig-resamples=10000           Bootstrap resamples per confidence interval")
+	fmt.Fprintln(w, "  -sig-confidence=0.95           Confidence level; differences need p < 1 - confidence")
+	fmt.Fprintln(w, "  -sig-segments=10               Block ranges each -dataset file is split into")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "COMPARISON PARAMETERS (several algorithms or variants in one run):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -adjuster-type=aimd,eip1559,pid  Run every listed algorithm on identical demand")
+	fmt.Fprintln(w, "  -variant=<name>:<type>[:<param>=<value>,...]")
+	fmt.Fprintln(w, "                               Named configuration with its own core and adjuster")
+	fmt.Fprintln(w, "                               parameters (repeatable), e.g. aggressive:aimd:aimd-gamma=0.1")
+	fmt.Fprintln(w, "                               -output=csv or json writes every variant's metrics per scenario or dataset")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "SENSITIVITY PARAMETERS (only for the sensitivity command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -sens-method=morris            morris (screening) or sobol (variance decomposition)")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Sensitivity.Method)
+	fmt.Fprintln(w, "  -sens-param=<name>=<min>:<max> Parameter bounds (repeatable; default every adjuster parameter)")
+	fmt.Fprintln(w, "  -sens-metrics=<metrics>        Comma-separated output metrics")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Sensitivity.Metrics)
+	fmt.Fprintln(w, "  -sens-samples=0                Morris trajectories or Sobol base samples")
+	fmt.Fprintln(w, "                                 Default: 20 for morris, 256 for sobol")
+	fmt.Fprintln(w, "  -sens-levels=4                 Morris grid levels per parameter (even)")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "TUNE PARAMETERS (only for the tune command):")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  -tune-param=<name>=<min>:<max> Parameter to tune within bounds (repeatable)")
+	fmt.Fprintln(w, "                                 log(min,max,n) tunes on a log scale; n is ignored")
+	fmt.Fprintln(w, "  -tune-objective=<terms>        Weighted metrics to minimize, metric:weight,...")
+	fmt.Fprintln(w, "                                 Each metric is scaled by its value at the starting point")
+	fmt.Fprintln(w, "                                 Negative weights reward larger values")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Tune.Objective)
+	fmt.Fprintln(w, "  -tune-method=nelder-mead       nelder-mead or random")
+	fmt.Fprintf(w, "                               Default: %s\n", p.config.Simulation.Tune.Method)
+	fmt.Fprintln(w, "  -tune-iterations=200           Maximum number of objective evaluations")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Tune.Iterations)
+	fmt.Fprintln(w, "  -tune-holdout=0.25             Fraction of cases (the last ones) held out for validation")
+	fmt.Fprintf(w, "                               Default: %.2f\n", p.config.Simulation.Tune.Holdout)
+	fmt.Fprintln(w, "  -tune-segments=1               Split each dataset into contiguous block ranges")
+	fmt.Fprintf(w, "                               Default: %d\n", p.config.Simulation.Tune.Segments)
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "EXAMPLE WORKFLOWS:")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Quick Start:")
+	fmt.Fprintln(w, "  feemarketsim                           # Run AIMD with default settings")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=eip1559    # Use EIP-1559 algorithm")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=pid        # Use PID controller")
+	fmt.Fprintln(w, "  feemarketsim -scenario=mixed -graph    # Test mixed traffic with charts")
+	fmt.Fprintln(w, "  feemarketsim -help                     # Show this help")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Algorithm Comparison:")
+	fmt.Fprintln(w, "  # Compare different algorithms on same scenario")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd -scenario=mixed -graph")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=eip1559 -scenario=mixed -graph")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=pid -scenario=mixed -graph")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd-eip1559 -scenario=mixed -graph")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "AIMD Parameter Testing:")
+	fmt.Fprintln(w, "  # Test learning strategies")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd -aimd-gamma=0.1 -aimd-alpha=0.02    # Aggressive")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd -aimd-gamma=0.5 -aimd-alpha=0.005   # Conservative")
+	fmt.Fprintln(w, "  # Test window sizes")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd -window-size=5                      # Fast response")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=aimd -window-size=20                     # Stable response")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "PID Controller Tuning:")
+	fmt.Fprintln(w, "  # Test different PID gains")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=pid -pid-kp=0.2                # More aggressive P")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=pid -pid-ki=0.05               # Higher integral gain")
+	fmt.Fprintln(w, "  feemarketsim -adjuster-type=pid -pid-kd=0.1                # More derivative action")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Advanced Randomness:")
+	fmt.Fprintln(w, "  # Test with RNG")
+	fmt.Fprintln(w, "  feemarketsim -rng-gaussian-noise=0.1                                                                                  # 10% gas variation")
+	fmt.Fprintln(w, "  feemarketsim -rng-burst-probability=0.1 -rng-burst-duration-min=2 -rng-burst-duration-max=5 -rng-burst-intensity=1.5  # Add burst periods")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "Real Data Analysis:")
+	fmt.Fprintln(w, "  # 1. Fetch blockchain data")
+	fmt.Fprintln(w, "  feemarketsim fetch 12000000 12001000 analysis.json")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  # 2. Test different algorithms")
+	fmt.Fprintln(w, "  feemarketsim replay analysis.json -adjuster-type=aimd -graph")
+	fmt.Fprintln(w, "  feemarketsim replay analysis.json -adjuster-type=eip1559 -graph")
+	fmt.Fprintln(w, "  feemarketsim replay analysis.json -adjuster-type=pid -graph")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "  # 3. Fine-tune parameters")
+	fmt.Fprintln(w, "  feemarketsim replay analysis.json -adjuster-type=aimd -aimd-gamma=0.1 -graph")
+	fmt.Fprintln(w, "  feemarketsim replay analysis.json -adjuster-type=pid -pid-kp=0.15 -graph")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "OUTPUT FILES:")
+	fmt.Fprintln(w, "  When -graph is enabled, the following files are generated:")
+	fmt.Fprintln(w, "  - chart_<scenario>.html            Fee evolution charts")
+	fmt.Fprintln(w, "  - base_comparison_<range>.html     Algorithm vs Base fee comparison")
+	fmt.Fprintln(w, "  - base_comparison_<range>_gas.html Gas usage analysis")
+	fmt.Fprintln(w)
+
+	fmt.Fprintln(w, "For detailed algorithm descriptions, see the project README.md file.")
 }
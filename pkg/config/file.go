@@ -0,0 +1,494 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment variable of every flag, e.g. FEEMARKETSIM_AIMD_GAMMA
+const EnvPrefix = "FEEMARKETSIM_"
+
+// setting is one value, or for repeatable flags several, assigned to a flag by name
+type setting struct {
+	name   string
+	values []string
+}
+
+// File is a parsed configuration file: base settings and named profiles applied on top of them.
+// Keys are flag names; nested tables or objects join their keys with "-", so [aimd] gamma = 0.1
+// sets -aimd-gamma.
+type File struct {
+	Path     string
+	Format   string // json or toml
+	settings []setting
+	profiles map[string][]setting
+}
+
+// FileFormat returns the format of a configuration file from its extension: toml, or json otherwise
+func FileFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return "toml"
+	}
+	return "json"
+}
+
+// DecodeFile reads a JSON or TOML file, chosen by FileFormat, into a tree of tables. Numbers are
+// json.Number, so other files in the same formats (e.g. experiments) can be decoded like config files.
+func DecodeFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var tree map[string]interface{}
+	switch FileFormat(path) {
+	case "toml":
+		tree, err = parseTOML(data)
+	default:
+		tree, err = parseJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return tree, nil
+}
+
+// LoadFile reads a JSON or TOML configuration file
+func LoadFile(path string) (*File, error) {
+	tree, err := DecodeFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	file := &File{Path: path, Format: FileFormat(path)}
+
+	if raw, ok := tree["profiles"]; ok {
+		profiles, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("config file %s: profiles must be a table of named profiles", path)
+		}
+		file.profiles = make(map[string][]setting)
+		for name, rawProfile := range profiles {
+			profile, ok := rawProfile.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("config file %s: profile %s must be a table", path, name)
+			}
+			if file.profiles[name], err = flatten("", profile); err != nil {
+				return nil, fmt.Errorf("config file %s, profile %s: %w", path, name, err)
+			}
+		}
+		delete(tree, "profiles")
+	}
+
+	if file.settings, err = flatten("", tree); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Profiles returns the names of the profiles defined in the file
+func (f *File) Profiles() []string {
+	names := make([]string, 0, len(f.profiles))
+	for name := range f.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// profile returns the settings of a named profile
+func (f *File) profile(name string) ([]setting, error) {
+	settings, ok := f.profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile '%s' in %s, must be one of: %v", name, f.Path, f.Profiles())
+	}
+	return settings, nil
+}
+
+// envSettings returns the settings given by FEEMARKETSIM_* environment variables
+func envSettings(flagSet *flag.FlagSet) []setting {
+	var settings []setting
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if value, ok := os.LookupEnv(EnvVar(f.Name)); ok && !isMetaFlag(f.Name) {
+			settings = append(settings, setting{name: f.Name, values: []string{value}})
+		}
+	})
+	return settings
+}
+
+// EnvVar returns the environment variable that sets a flag
+func EnvVar(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// isMetaFlag reports whether a flag controls parsing itself and so cannot come from a file or profile
+func isMetaFlag(name string) bool {
+	switch name {
+	case "config", "profile", "dump-config", "help":
+		return true
+	}
+	return false
+}
+
+// applySettings assigns settings through the flag set, so every source parses values like the command line.
+// Repeatable flags are replaced rather than appended to, so a later source overrides an earlier one.
+func applySettings(flagSet *flag.FlagSet, source string, settings []setting) error {
+	for _, s := range settings {
+		f := flagSet.Lookup(s.name)
+		if f == nil {
+			return fmt.Errorf("%s: unknown setting %s", source, s.name)
+		}
+		if isMetaFlag(s.name) {
+			return fmt.Errorf("%s: %s cannot be set here", source, s.name)
+		}
+
+		if list, ok := f.Value.(*stringList); ok {
+			*list = nil
+		} else if len(s.values) != 1 {
+			return fmt.Errorf("%s: %s takes a single value", source, s.name)
+		}
+		for _, value := range s.values {
+			if err := flagSet.Set(s.name, value); err != nil {
+				return fmt.Errorf("%s: invalid value %q for %s: %w", source, value, s.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// flatten turns a nested table into flag settings, joining nested keys with "-"
+func flatten(prefix string, tree map[string]interface{}) ([]setting, error) {
+	keys := make([]string, 0, len(tree))
+	for key := range tree {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var settings []setting
+	for _, key := range keys {
+		name := key
+		if prefix != "" {
+			name = prefix + "-" + key
+		}
+
+		switch value := tree[key].(type) {
+		case map[string]interface{}:
+			nested, err := flatten(name, value)
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, nested...)
+		case []interface{}:
+			s := setting{name: name, values: []string{}}
+			for _, item := range value {
+				text, err := scalarText(name, item)
+				if err != nil {
+					return nil, err
+				}
+				s.values = append(s.values, text)
+			}
+			settings = append(settings, s)
+		default:
+			text, err := scalarText(name, value)
+			if err != nil {
+				return nil, err
+			}
+			settings = append(settings, setting{name: name, values: []string{text}})
+		}
+	}
+	return settings, nil
+}
+
+// scalarText formats a scalar value as it would be written on the command line
+func scalarText(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	return "", fmt.Errorf("unsupported value for %s: %v", name, value)
+}
+
+// parseJSON decodes a JSON object, keeping numbers as written so large integers stay exact
+func parseJSON(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree map[string]interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// parseTOML decodes the TOML subset used by configuration files: comments, [table] and
+// [table.sub] headers, dotted keys, and single-line strings, numbers, booleans and arrays
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	root := make(map[string]interface{})
+	table := root
+
+	for i, line := range strings.Split(string(data), "\n") {
+		lineNumber := i + 1
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header %s", lineNumber, line)
+			}
+			var err error
+			if table, err = subTable(root, strings.Split(strings.Trim(line, "[]"), ".")); err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+			}
+			continue
+		}
+
+		key, rawValue, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNumber)
+		}
+		path := strings.Split(strings.TrimSpace(key), ".")
+		parent, err := subTable(table, path[:len(path)-1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		name := strings.TrimSpace(path[len(path)-1])
+		if name == "" {
+			return nil, fmt.Errorf("line %d: empty key", lineNumber)
+		}
+		if _, exists := parent[name]; exists {
+			return nil, fmt.Errorf("line %d: %s is defined more than once", lineNumber, strings.TrimSpace(key))
+		}
+
+		value, err := parseTOMLValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		parent[name] = value
+	}
+	return root, nil
+}
+
+// subTable returns the nested table at path, creating missing tables
+func subTable(table map[string]interface{}, path []string) (map[string]interface{}, error) {
+	for _, part := range path {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty table name")
+		}
+		next, exists := table[part]
+		if !exists {
+			next = make(map[string]interface{})
+			table[part] = next
+		}
+		nested, ok := next.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s is a value, not a table", part)
+		}
+		table = nested
+	}
+	return table, nil
+}
+
+// parseTOMLValue parses a string, number, boolean or array of them
+func parseTOMLValue(text string) (interface{}, error) {
+	switch {
+	case text == "":
+		return nil, fmt.Errorf("missing value")
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unterminated array %s", text)
+		}
+		items, err := splitArray(text[1 : len(text)-1])
+		if err != nil {
+			return nil, err
+		}
+		values := []interface{}{}
+		for _, item := range items {
+			value, err := parseTOMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			if _, nested := value.([]interface{}); nested {
+				return nil, fmt.Errorf("nested arrays are not supported")
+			}
+			values = append(values, value)
+		}
+		return values, nil
+	case strings.HasPrefix(text, `"`):
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", text)
+		}
+		return value, nil
+	case strings.HasPrefix(text, "'"):
+		if len(text) < 2 || !strings.HasSuffix(text, "'") || strings.Contains(text[1:len(text)-1], "'") {
+			return nil, fmt.Errorf("invalid literal string %s", text)
+		}
+		return text[1 : len(text)-1], nil
+	case text == "true" || text == "false":
+		return text == "true", nil
+	}
+
+	number := strings.ReplaceAll(text, "_", "")
+	if _, err := strconv.ParseFloat(number, 64); err != nil {
+		return nil, fmt.Errorf("invalid value %s", text)
+	}
+	return json.Number(number), nil
+}
+
+// splitArray splits the items of a single-line array at commas outside strings
+func splitArray(text string) ([]string, error) {
+	var items []string
+	var quote rune
+	start := 0
+	for i, r := range text {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || !escaped(text, i)) {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items = append(items, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated string in array")
+	}
+	// A trailing comma is allowed
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		items = append(items, last)
+	}
+	for _, item := range items {
+		if item == "" {
+			return nil, fmt.Errorf("empty array item")
+		}
+	}
+	return items, nil
+}
+
+// escaped reports whether the character at i is preceded by an odd number of backslashes
+func escaped(text string, i int) bool {
+	backslashes := 0
+	for j := i - 1; j >= 0 && text[j] == '\\'; j-- {
+		backslashes++
+	}
+	return backslashes%2 == 1
+}
+
+// stripComment removes a # comment that is not inside a string
+func stripComment(line string) string {
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote && (quote == '\'' || !escaped(line, i)) {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// DumpFormat returns the format -dump-config writes: the configuration file's format, or JSON
+func DumpFormat(cfg Config) string {
+	if cfg.Simulation.ConfigFile != "" {
+		return FileFormat(cfg.Simulation.ConfigFile)
+	}
+	return "json"
+}
+
+// DumpConfig writes every setting of the resolved configuration in a format LoadFile reads back
+func DumpConfig(w io.Writer, cfg Config, format string) error {
+	p := &Parser{
+		config:  &cfg,
+		flagSet: flag.NewFlagSet("dump", flag.ContinueOnError),
+	}
+	p.RegisterFlags()
+
+	values := make(map[string]interface{})
+	var names []string
+	p.flagSet.VisitAll(func(f *flag.Flag) {
+		if _, deprecated := deprecatedFlags[f.Name]; deprecated || isMetaFlag(f.Name) {
+			return
+		}
+		names = append(names, f.Name)
+		values[f.Name] = f.Value.(flag.Getter).Get()
+	})
+	// Parsing keeps only the first type in AdjusterType, so dump the full list
+	values["adjuster-type"] = strings.Join(cfg.Simulation.AdjusterTypeList(), ",")
+
+	switch format {
+	case "toml":
+		for _, name := range names {
+			if _, err := fmt.Fprintf(w, "%s = %s\n", name, tomlValue(values[name])); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "json":
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+	return fmt.Errorf("unknown config format: %s", format)
+}
+
+// FlagArgs returns command-line arguments that set every setting of a configuration decoded
+// from DumpConfig's JSON. Numbers must be decoded as json.Number so large integers stay exact.
+func FlagArgs(settings map[string]interface{}) ([]string, error) {
+	flattened, err := flatten("", settings)
+	if err != nil {
+		return nil, err
+	}
+	var args []string
+	for _, s := range flattened {
+		for _, value := range s.values {
+			args = append(args, "-"+s.name+"="+value)
+		}
+	}
+	return args, nil
+}
+
+// tomlValue formats a flag value as a TOML value
+func tomlValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case float64:
+		text := strconv.FormatFloat(v, 'g', -1, 64)
+		if !strings.ContainsAny(text, ".eEIN") {
+			text += ".0"
+		}
+		return text
+	}
+	return fmt.Sprint(value)
+}
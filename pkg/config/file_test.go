@@ -0,0 +1,174 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testTOML = `# Shared experiment settings
+adjuster-type = "pid"
+dataset = ["a.json", 'b.json',]  # trailing comma
+target-block-size = 30_000_000
+
+[rng]
+seed = 42
+
+[pid]
+kp = 0.05
+ki = 1e-4
+
+[profiles.fast]
+pid.kp = 0.2
+scenario = "full"
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileTOML(t *testing.T) {
+	file, err := LoadFile(writeFile(t, "exp.toml", testTOML))
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if profiles := file.Profiles(); len(profiles) != 1 || profiles[0] != "fast" {
+		t.Errorf("Unexpected profiles %v", profiles)
+	}
+
+	values := make(map[string][]string)
+	for _, s := range file.settings {
+		values[s.name] = s.values
+	}
+	expected := map[string][]string{
+		"adjuster-type":     {"pid"},
+		"dataset":           {"a.json", "b.json"},
+		"target-block-size": {"30000000"},
+		"rng-seed":          {"42"},
+		"pid-kp":            {"0.05"},
+		"pid-ki":            {"1e-4"},
+	}
+	for name, want := range expected {
+		got := values[name]
+		if len(got) != len(want) {
+			t.Errorf("%s: expected %v, got %v", name, want, got)
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s: expected %v, got %v", name, want, got)
+			}
+		}
+	}
+
+	for _, bad := range []string{"key", "a = [1, [2]]", "a = \"open", "[t]\nx = 1\n[t.x]", "a = 1\na = 2"} {
+		if _, err := parseTOML([]byte(bad)); err == nil {
+			t.Errorf("Expected an error for %q", bad)
+		}
+	}
+}
+
+func TestParseMergeOrder(t *testing.T) {
+	path := writeFile(t, "exp.toml", testTOML)
+
+	cfg, err := NewParser().Parse([]string{"-config=" + path})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Adjuster.PID.Kp != 0.05 || cfg.TargetBlockSize != 30_000_000 || cfg.Simulation.Randomizer.Seed != 42 {
+		t.Errorf("File settings not applied: kp %f, target %d, seed %d",
+			cfg.Adjuster.PID.Kp, cfg.TargetBlockSize, cfg.Simulation.Randomizer.Seed)
+	}
+
+	// Profile beats the file
+	cfg, err = NewParser().Parse([]string{"-config=" + path, "-profile=fast"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Adjuster.PID.Kp != 0.2 || cfg.Simulation.Scenario != "full" || cfg.Adjuster.PID.Ki != 1e-4 {
+		t.Errorf("Profile not applied on top of the file: kp %f, scenario %s", cfg.Adjuster.PID.Kp, cfg.Simulation.Scenario)
+	}
+
+	// Environment beats the profile, flags beat the environment
+	t.Setenv(EnvVar("pid-kp"), "0.3")
+	t.Setenv(EnvVar("rng-seed"), "7")
+	cfg, err = NewParser().Parse([]string{"-config=" + path, "-profile=fast", "-rng-seed=9", "-dataset=c.json"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.Adjuster.PID.Kp != 0.3 || cfg.Simulation.Randomizer.Seed != 9 {
+		t.Errorf("Expected env kp 0.3 and flag seed 9, got %f and %d", cfg.Adjuster.PID.Kp, cfg.Simulation.Randomizer.Seed)
+	}
+	if len(cfg.Simulation.DataSets) != 1 || cfg.Simulation.DataSets[0] != "c.json" {
+		t.Errorf("Expected flags to replace the file's datasets, got %v", cfg.Simulation.DataSets)
+	}
+
+	if _, err := NewParser().Parse([]string{"-config=" + path, "-profile=missing"}); err == nil {
+		t.Error("Expected an error for an unknown profile")
+	}
+	bad := writeFile(t, "bad.json", `{"aimd": {"gamma": 0.1}, "not-a-flag": 1}`)
+	if _, err := NewParser().Parse([]string{"-config=" + bad}); err == nil {
+		t.Error("Expected an error for an unknown setting")
+	}
+}
+
+func TestDumpConfigRoundTrip(t *testing.T) {
+	for _, format := range []string{"json", "toml"} {
+		cfg := Default()
+		cfg.Simulation.AdjusterType = "eip1559"
+		cfg.Simulation.AdjusterTypes = []string{"eip1559", "pid"}
+		cfg.Simulation.DataSets = []string{"a.json"}
+		cfg.Adjuster.AIMD.Gamma = 0.125
+
+		var dumped bytes.Buffer
+		if err := DumpConfig(&dumped, cfg, format); err != nil {
+			t.Fatalf("%s: DumpConfig failed: %v", format, err)
+		}
+		path := writeFile(t, "dump."+format, dumped.String())
+
+		loaded, err := NewParser().Parse([]string{"-config=" + path})
+		if err != nil {
+			t.Fatalf("%s: Parse failed: %v", format, err)
+		}
+		if loaded.Adjuster.AIMD.Gamma != 0.125 || loaded.Simulation.Randomizer.Seed != cfg.Simulation.Randomizer.Seed ||
+			len(loaded.Simulation.AdjusterTypeList()) != 2 || len(loaded.Simulation.DataSets) != 1 {
+			t.Errorf("%s: configuration did not round trip", format)
+		}
+	}
+}
+
+func TestFlagArgsRoundTrip(t *testing.T) {
+	cfg := Default()
+	cfg.Simulation.AdjusterTypes = []string{"aimd", "pid"}
+	cfg.Simulation.DataSets = []string{"a.json", "b.json"}
+	cfg.Simulation.Randomizer.Seed = 1_700_000_000_123_456_789 // Beyond float64 precision
+	cfg.Adjuster.PID.Kp = 0.3
+
+	var dumped bytes.Buffer
+	if err := DumpConfig(&dumped, cfg, "json"); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := parseJSON(dumped.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	args, err := FlagArgs(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := NewParser().Parse(args)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if loaded.Simulation.Randomizer.Seed != cfg.Simulation.Randomizer.Seed || loaded.Adjuster.PID.Kp != 0.3 ||
+		len(loaded.Simulation.AdjusterTypeList()) != 2 || len(loaded.Simulation.DataSets) != 2 {
+		t.Errorf("configuration did not round trip through flags: %v", args)
+	}
+}
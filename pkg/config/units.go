@@ -0,0 +1,164 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Multipliers of the gas and fee units accepted by ParseGas and ParseFee
+var (
+	gasUnits = []unit{{"k", 1e3}, {"m", 1e6}, {"g", 1e9}}
+	feeUnits = []unit{{"gwei", 1e9}, {"wei", 1}}
+)
+
+// unit is a case-insensitive suffix and the amount it multiplies by
+type unit struct {
+	suffix     string
+	multiplier int64
+}
+
+// ParseGas parses a gas amount such as 15000000, 15_000_000, 15M, 30Mgas or 1.5 M gas
+func ParseGas(text string) (uint64, error) {
+	number := normalizeAmount(text)
+	number = strings.TrimSuffix(number, "gas")
+	amount, err := parseAmount(number, gasUnits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid gas amount %q: %w", text, err)
+	}
+	return amount, nil
+}
+
+// ParseFee parses a fee in wei, such as 1000000000, 1gwei, 0.05 gwei or 250000000wei.
+// Numbers without a unit are wei.
+func ParseFee(text string) (uint64, error) {
+	amount, err := parseAmount(normalizeAmount(text), feeUnits)
+	if err != nil {
+		return 0, fmt.Errorf("invalid fee %q: %w", text, err)
+	}
+	return amount, nil
+}
+
+// normalizeAmount lower-cases an amount and removes spaces and digit separators
+func normalizeAmount(text string) string {
+	text = strings.ToLower(strings.TrimSpace(text))
+	return strings.NewReplacer(" ", "", "_", "").Replace(text)
+}
+
+// parseAmount parses a non-negative decimal number with an optional unit suffix.
+// The number is exact, so 0.1gwei is exactly 100000000 wei, and must scale to a whole amount.
+func parseAmount(text string, units []unit) (uint64, error) {
+	multiplier := int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(text, u.suffix) {
+			text = strings.TrimSuffix(text, u.suffix)
+			multiplier = u.multiplier
+			break
+		}
+	}
+
+	value, ok := new(big.Rat).SetString(text)
+	if !ok || text == "" {
+		return 0, fmt.Errorf("not a number")
+	}
+	value.Mul(value, new(big.Rat).SetInt64(multiplier))
+	switch {
+	case value.Sign() < 0:
+		return 0, fmt.Errorf("must not be negative")
+	case !value.IsInt():
+		return 0, fmt.Errorf("must be a whole number of units")
+	case !value.Num().IsUint64():
+		return 0, fmt.Errorf("too large")
+	}
+	return value.Num().Uint64(), nil
+}
+
+// FormatGwei formats a fee in wei as Gwei with adaptive precision: three decimals from 1 Gwei,
+// whole Gwei from 1000 Gwei and three significant digits below 1 Gwei, down to single wei
+func FormatGwei(wei float64) string {
+	gwei := wei / 1e9
+	abs := math.Abs(gwei)
+	switch {
+	case abs == 0:
+		return "0 Gwei"
+	case abs >= 1000:
+		return fmt.Sprintf("%.0f Gwei", gwei)
+	case abs >= 1:
+		return fmt.Sprintf("%.3f Gwei", gwei)
+	}
+	precision := 2 - int(math.Floor(math.Log10(abs)))
+	if precision > 9 {
+		precision = 9
+	}
+	return fmt.Sprintf("%.*f Gwei", precision, gwei)
+}
+
+// gasValue is a flag value holding gas that accepts unit suffixes
+type gasValue uint64
+
+func (v *gasValue) String() string {
+	return strconv.FormatUint(uint64(*v), 10)
+}
+
+func (v *gasValue) Set(text string) error {
+	amount, err := ParseGas(text)
+	if err != nil {
+		return err
+	}
+	*v = gasValue(amount)
+	return nil
+}
+
+func (v *gasValue) Get() interface{} {
+	return uint64(*v)
+}
+
+// feeValue is a flag value holding a fee in wei that accepts wei and gwei units
+type feeValue uint64
+
+func (v *feeValue) String() string {
+	return strconv.FormatUint(uint64(*v), 10)
+}
+
+func (v *feeValue) Set(text string) error {
+	amount, err := ParseFee(text)
+	if err != nil {
+		return err
+	}
+	*v = feeValue(amount)
+	return nil
+}
+
+func (v *feeValue) Get() interface{} {
+	return uint64(*v)
+}
+
+// ParseParamValue parses a value of a numeric parameter, accepting the units of gas and fee parameters
+func ParseParamValue(name, text string) (float64, error) {
+	cfg := Default()
+	p := &Parser{
+		config:  &cfg,
+		flagSet: flag.NewFlagSet("param", flag.ContinueOnError),
+	}
+	p.RegisterFlags()
+
+	if f := p.flagSet.Lookup(name); f != nil {
+		switch f.Value.(type) {
+		case *gasValue:
+			gas, err := ParseGas(text)
+			return float64(gas), err
+		case *feeValue:
+			fee, err := ParseFee(text)
+			return float64(fee), err
+		}
+	}
+
+	value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", text)
+	}
+	return value, nil
+}
@@ -0,0 +1,92 @@
+package config
+
+import "testing"
+
+func TestParseGas(t *testing.T) {
+	cases := map[string]uint64{
+		"15000000":   15_000_000,
+		"15_000_000": 15_000_000,
+		"15M":        15_000_000,
+		"30Mgas":     30_000_000,
+		"1.5 M gas":  1_500_000,
+		"250k":       250_000,
+		"2G":         2_000_000_000,
+		"21000gas":   21_000,
+	}
+	for text, want := range cases {
+		got, err := ParseGas(text)
+		if err != nil || got != want {
+			t.Errorf("ParseGas(%q) = %d, %v, expected %d", text, got, err, want)
+		}
+	}
+	for _, bad := range []string{"", "M", "-5M", "1.5", "15Mwei", "abc"} {
+		if _, err := ParseGas(bad); err == nil {
+			t.Errorf("Expected an error for gas %q", bad)
+		}
+	}
+}
+
+func TestParseFee(t *testing.T) {
+	cases := map[string]uint64{
+		"1000000000":   1_000_000_000,
+		"1gwei":        1_000_000_000,
+		"0.05 gwei":    50_000_000,
+		"0.1GWEI":      100_000_000,
+		"250000000wei": 250_000_000,
+		"0":            0,
+	}
+	for text, want := range cases {
+		got, err := ParseFee(text)
+		if err != nil || got != want {
+			t.Errorf("ParseFee(%q) = %d, %v, expected %d", text, got, err, want)
+		}
+	}
+	for _, bad := range []string{"1.5wei", "0.0000000001gwei", "-1gwei", "1eth", "99999999999gwei"} {
+		if _, err := ParseFee(bad); err == nil {
+			t.Errorf("Expected an error for fee %q", bad)
+		}
+	}
+}
+
+func TestFormatGwei(t *testing.T) {
+	cases := map[float64]string{
+		0:         "0 Gwei",
+		1e9:       "1.000 Gwei",
+		2.5e12:    "2500 Gwei",
+		5e8:       "0.500 Gwei",
+		5.234e7:   "0.0523 Gwei",
+		1234:      "0.00000123 Gwei",
+		1:         "0.000000001 Gwei",
+		123.456e9: "123.456 Gwei",
+	}
+	for wei, want := range cases {
+		if got := FormatGwei(wei); got != want {
+			t.Errorf("FormatGwei(%g) = %q, expected %q", wei, got, want)
+		}
+	}
+}
+
+func TestUnitsInFlagsAndParams(t *testing.T) {
+	cfg, err := NewParser().Parse([]string{"-target-block-size=30M", "-initial-base-fee=0.05gwei", "-min-base-fee=1000wei"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if cfg.TargetBlockSize != 30_000_000 || cfg.InitialBaseFee != 50_000_000 || cfg.MinBaseFee != 1000 {
+		t.Errorf("Units not applied: %d, %d, %d", cfg.TargetBlockSize, cfg.InitialBaseFee, cfg.MinBaseFee)
+	}
+
+	if value, err := ParseParamValue("initial-base-fee", "2gwei"); err != nil || value != 2e9 {
+		t.Errorf("ParseParamValue(initial-base-fee, 2gwei) = %g, %v", value, err)
+	}
+	if value, err := ParseParamValue("aimd-gamma", "0.25"); err != nil || value != 0.25 {
+		t.Errorf("ParseParamValue(aimd-gamma, 0.25) = %g, %v", value, err)
+	}
+	if _, err := ParseParamValue("aimd-gamma", "1gwei"); err == nil {
+		t.Error("Expected an error for a unit on a plain parameter")
+	}
+
+	defaults := Default()
+	if err := SetParam(&defaults, "target-block-size", 2e7); err != nil || defaults.TargetBlockSize != 20_000_000 {
+		t.Errorf("SetParam on a gas parameter failed: %v", err)
+	}
+}
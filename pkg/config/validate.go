@@ -0,0 +1,509 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Allowed values of the enumerated settings
+var (
+	validAdjusterTypes = []string{"aimd", "eip1559", "eip-1559", "pid"}
+	validScenarios     = []string{"all", "full", "empty", "stable", "mixed", "none"}
+	validSearchMetrics = []string{"volatility", "peak-to-trough", "min-fee-time"}
+	validOutputFormats = []string{"text", "json", "csv", "markdown"}
+	validLogLevels     = []string{"debug", "info", "warn", "error"}
+	validLogFormats    = []string{"text", "json"}
+)
+
+// FieldError is one configuration value that violates a constraint
+type FieldError struct {
+	Field   string `json:"field"`   // Path of the field in Config, e.g. Adjuster.AIMD.Gamma (empty for unknown settings)
+	Flag    string `json:"flag"`    // Flag name, which is also the config file key, e.g. aimd-gamma
+	Value   string `json:"value"`   // Offending value as written on the command line
+	Allowed string `json:"allowed"` // Allowed values, e.g. [0, 2], > 1 or one of: csv, json
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("-%s: %s", e.Flag, e.Message)
+	}
+	return fmt.Sprintf("%s (-%s): %s", e.Field, e.Flag, e.Message)
+}
+
+// ValidationError lists every constraint a configuration violates
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 1 {
+		return e.Errors[0].Error()
+	}
+	lines := []string{fmt.Sprintf("%d problems:", len(e.Errors))}
+	for _, fieldErr := range e.Errors {
+		lines = append(lines, "  "+fieldErr.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Validate checks every section of a configuration, including the adjusters it does not run, and
+// returns a *ValidationError listing each violated constraint. It validates configurations built
+// outside the parser, e.g. by a parameter sweep.
+func Validate(cfg Config) error {
+	p := &Parser{config: &cfg}
+	return p.Validate()
+}
+
+// ValidateFile checks a configuration file, and the named profile when not empty, applied on top of
+// the defaults. Unknown keys and unparsable values are reported together with violated constraints.
+func ValidateFile(path, profile string) error {
+	file, err := LoadFile(path)
+	if err != nil {
+		return err
+	}
+	settings := file.settings
+	if profile != "" {
+		profileSettings, err := file.profile(profile)
+		if err != nil {
+			return err
+		}
+		settings = append(append([]setting{}, settings...), profileSettings...)
+	}
+
+	cfg := Default()
+	v := newValidator(&cfg)
+	for _, s := range settings {
+		if err := applySettings(v.flagSet, path, []setting{s}); err != nil {
+			v.addValue(s.name, strings.Join(s.values, ","), "", "%v", err)
+		}
+	}
+	cfg.Simulation.splitAdjusterTypes()
+
+	v.validate(&cfg)
+	return v.err()
+}
+
+// Validate checks every section of the parsed configuration and returns a *ValidationError
+// listing each violated constraint, or nil
+func (p *Parser) Validate() error {
+	v := newValidator(p.config)
+	v.validate(p.config)
+	return v.err()
+}
+
+// validator collects the violated constraints of one configuration
+type validator struct {
+	flagSet *flag.FlagSet     // Flags bound to the validated configuration, for values and names
+	fields  map[string]string // Field path of each flag
+	errors  []FieldError
+}
+
+// newValidator returns a validator for cfg
+func newValidator(cfg *Config) *validator {
+	p := &Parser{
+		config:  cfg,
+		flagSet: flag.NewFlagSet("validate", flag.ContinueOnError),
+	}
+	p.flagSet.SetOutput(io.Discard)
+	p.RegisterFlags()
+	return &validator{flagSet: p.flagSet, fields: fieldPaths(cfg, p.flagSet)}
+}
+
+// fieldPaths maps each flag to the path of the Config field it is bound to
+func fieldPaths(cfg *Config, flagSet *flag.FlagSet) map[string]string {
+	addresses := make(map[uintptr]string)
+	var walk func(value reflect.Value, prefix string)
+	walk = func(value reflect.Value, prefix string) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Field(i)
+			path := prefix + value.Type().Field(i).Name
+			if field.Kind() == reflect.Struct {
+				walk(field, path+".")
+				continue
+			}
+			addresses[field.UnsafeAddr()] = path
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "")
+
+	paths := make(map[string]string)
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if path, ok := addresses[reflect.ValueOf(f.Value).Pointer()]; ok {
+			paths[f.Name] = path
+		}
+	})
+	return paths
+}
+
+// add records a violated constraint of the flag's current value
+func (v *validator) add(name, allowed, format string, args ...interface{}) {
+	value := ""
+	if f := v.flagSet.Lookup(name); f != nil {
+		value = f.Value.String()
+	}
+	v.addValue(name, value, allowed, format, args...)
+}
+
+// addValue records a violated constraint of an explicit value, e.g. one entry of a list
+func (v *validator) addValue(name, value, allowed, format string, args ...interface{}) {
+	v.errors = append(v.errors, FieldError{
+		Field:   v.fields[name],
+		Flag:    name,
+		Value:   value,
+		Allowed: allowed,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// err returns the collected violations as a *ValidationError, or nil when there are none
+func (v *validator) err() error {
+	if len(v.errors) == 0 {
+		return nil
+	}
+	return &ValidationError{Errors: v.errors}
+}
+
+// oneOf formats the allowed values of an enumerated setting
+func oneOf(values []string) string {
+	return "one of: " + strings.Join(values, ", ")
+}
+
+// contains reports whether values contains value
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+// validate checks every section of cfg
+func (v *validator) validate(cfg *Config) {
+	s := &cfg.Simulation
+
+	v.validateCoreParameters(cfg)
+	v.validateAdjusterTypes(s)
+	v.validateEIP1559Parameters(&cfg.Adjuster)
+	v.validateAIMDParameters(&cfg.Adjuster)
+	v.validatePIDParameters(&cfg.Adjuster)
+	v.validateRandomizerParameters(s)
+	v.validateScenarioParameters(s)
+	v.validateOutputParameters(s)
+	v.validateAttackParameters(s)
+	v.validateSearchParameters(s)
+	v.validateMonteCarloParameters(s)
+	v.validateSweepParameters(s)
+	v.validateTuneParameters(s)
+	v.validateSensitivityParameters(s)
+	v.validateResponseParameters(s)
+	v.validateEquilibriumParameters(s)
+	v.validateSignificanceParameters(s)
+
+	// Variants are checked last so they only report problems the shared configuration does not have
+	v.validateCompareParameters(*cfg)
+}
+
+// validateCoreParameters validates the parameters shared by all algorithms
+func (v *validator) validateCoreParameters(c *Config) {
+	if c.TargetBlockSize == 0 {
+		v.add("target-block-size", "> 0", "target block size must be positive")
+	}
+	if c.BurstMultiplier <= 1.0 {
+		v.add("burst-multiplier", "> 1", "burst multiplier (%.3f) must be greater than 1.0", c.BurstMultiplier)
+	}
+	if c.InitialBaseFee < c.MinBaseFee {
+		v.add("initial-base-fee", fmt.Sprintf(">= %d", c.MinBaseFee),
+			"initial base fee (%d) must be >= min base fee (%d)", c.InitialBaseFee, c.MinBaseFee)
+	}
+	if c.WindowSize <= 0 {
+		v.add("window-size", "> 0", "window size (%d) must be positive", c.WindowSize)
+	}
+}
+
+// validateAdjusterTypes validates every selected adjuster type
+func (v *validator) validateAdjusterTypes(s *SimulationConfig) {
+	for _, adjusterType := range s.AdjusterTypeList() {
+		if !contains(validAdjusterTypes, adjusterType) {
+			v.addValue("adjuster-type", adjusterType, oneOf(validAdjusterTypes),
+				"invalid adjuster type '%s', must be one of: %v", adjusterType, validAdjusterTypes)
+		}
+	}
+}
+
+// validateEIP1559Parameters validates EIP-1559 parameters
+func (v *validator) validateEIP1559Parameters(a *AdjusterConfigs) {
+	if a.EIP1559.MaxFeeChange <= 0 || a.EIP1559.MaxFeeChange > 1.0 {
+		v.add("eip1559-max-fee-change", "(0, 1]", "EIP-1559 max fee change (%.3f) must be between 0 and 1.0", a.EIP1559.MaxFeeChange)
+	}
+}
+
+// validateAIMDParameters validates AIMD-specific parameters
+func (v *validator) validateAIMDParameters(a *AdjusterConfigs) {
+	if a.AIMD.Gamma < 0 || a.AIMD.Gamma > 2.0 {
+		v.add("aimd-gamma", "[0, 2]", "gamma (%.3f) must be between 0 and 2.0", a.AIMD.Gamma)
+	}
+	if a.AIMD.MinLearningRate < 0 {
+		v.add("aimd-min-learning-rate", ">= 0", "min learning rate (%.6f) must not be negative", a.AIMD.MinLearningRate)
+	}
+	if a.AIMD.MaxLearningRate < a.AIMD.MinLearningRate {
+		v.add("aimd-max-learning-rate", fmt.Sprintf(">= %g", a.AIMD.MinLearningRate),
+			"max learning rate (%.6f) must be >= min learning rate (%.6f)", a.AIMD.MaxLearningRate, a.AIMD.MinLearningRate)
+	} else if a.AIMD.InitialLearningRate < a.AIMD.MinLearningRate || a.AIMD.InitialLearningRate > a.AIMD.MaxLearningRate {
+		v.add("aimd-initial-learning-rate", fmt.Sprintf("[%g, %g]", a.AIMD.MinLearningRate, a.AIMD.MaxLearningRate),
+			"initial learning rate (%.6f) must be between the min (%.6f) and max (%.6f) learning rates",
+			a.AIMD.InitialLearningRate, a.AIMD.MinLearningRate, a.AIMD.MaxLearningRate)
+	}
+	if a.AIMD.Alpha < 0 {
+		v.add("aimd-alpha", ">= 0", "alpha (%.6f) must not be negative", a.AIMD.Alpha)
+	}
+	if a.AIMD.Beta < 0 || a.AIMD.Beta > 1 {
+		v.add("aimd-beta", "[0, 1]", "beta (%.6f) must be between 0 and 1", a.AIMD.Beta)
+	}
+}
+
+// validatePIDParameters validates PID-specific parameters
+func (v *validator) validatePIDParameters(a *AdjusterConfigs) {
+	if a.PID.Kp < 0 {
+		v.add("pid-kp", ">= 0", "PID Kp (%.6f) must not be negative", a.PID.Kp)
+	}
+	if a.PID.Ki < 0 {
+		v.add("pid-ki", ">= 0", "PID Ki (%.6f) must not be negative", a.PID.Ki)
+	}
+	if a.PID.Kd < 0 {
+		v.add("pid-kd", ">= 0", "PID Kd (%.6f) must not be negative", a.PID.Kd)
+	}
+	if a.PID.MaxIntegral <= a.PID.MinIntegral {
+		v.add("pid-max-integral", fmt.Sprintf("> %g", a.PID.MinIntegral),
+			"PID max integral (%.3f) must be greater than min integral (%.3f)", a.PID.MaxIntegral, a.PID.MinIntegral)
+	}
+	if a.PID.MaxFeeChange <= 0 || a.PID.MaxFeeChange > 1.0 {
+		v.add("pid-max-fee-change", "(0, 1]", "PID max fee change (%.3f) must be between 0 and 1.0", a.PID.MaxFeeChange)
+	}
+}
+
+// validateRandomizerParameters validates randomizer parameters
+func (v *validator) validateRandomizerParameters(s *SimulationConfig) {
+	r := s.Randomizer
+	if r.GaussianNoise < 0 || r.GaussianNoise > 1.0 {
+		v.add("rng-gaussian-noise", "[0, 1]", "randomizer gaussian noise (%.3f) must be between 0.0 and 1.0", r.GaussianNoise)
+	}
+	if r.BurstProbability < 0 || r.BurstProbability > 1.0 {
+		v.add("rng-burst-probability", "[0, 1]", "randomizer burst probability (%.3f) must be between 0.0 and 1.0", r.BurstProbability)
+	}
+	if r.BurstDurationMax < r.BurstDurationMin {
+		v.add("rng-burst-duration-max", fmt.Sprintf(">= %d", r.BurstDurationMin),
+			"randomizer burst duration max (%d) must be >= min (%d)", r.BurstDurationMax, r.BurstDurationMin)
+	}
+
+	// Bursts only need a duration and intensity when they can happen
+	if r.BurstProbability > 0 {
+		if r.BurstDurationMin <= 0 {
+			v.add("rng-burst-duration-min", "> 0", "randomizer burst duration min (%d) must be positive", r.BurstDurationMin)
+		}
+		if r.BurstIntensity <= 0 {
+			v.add("rng-burst-intensity", "> 0", "randomizer burst intensity (%.3f) must be positive", r.BurstIntensity)
+		}
+	}
+}
+
+// validateScenarioParameters validates the scenario selection
+func (v *validator) validateScenarioParameters(s *SimulationConfig) {
+	if !contains(validScenarios, s.Scenario) {
+		v.add("scenario", oneOf(validScenarios), "invalid scenario '%s', must be one of: %v", s.Scenario, validScenarios)
+	}
+}
+
+// validateOutputParameters validates the results format and the log settings
+func (v *validator) validateOutputParameters(s *SimulationConfig) {
+	if !contains(validOutputFormats, s.Output) {
+		v.add("output", oneOf(validOutputFormats), "invalid output format '%s', must be one of: %v", s.Output, validOutputFormats)
+	}
+	if !contains(validLogLevels, s.LogLevel) {
+		v.add("log-level", oneOf(validLogLevels), "invalid log level '%s', must be one of: %v", s.LogLevel, validLogLevels)
+	}
+	if !contains(validLogFormats, s.LogFormat) {
+		v.add("log-format", oneOf(validLogFormats), "invalid log format '%s', must be one of: %v", s.LogFormat, validLogFormats)
+	}
+}
+
+// validateAttackParameters validates adversarial attacker parameters
+func (v *validator) validateAttackParameters(s *SimulationConfig) {
+	if s.Attack.Share < 0 || s.Attack.Share > 1.0 {
+		v.add("attack-share", "[0, 1]", "attack share (%.3f) must be between 0.0 and 1.0", s.Attack.Share)
+	}
+	if s.Attack.Strategy != "stuff" && s.Attack.Strategy != "drain" {
+		v.add("attack-strategy", "one of: stuff, drain", "invalid attack strategy '%s', must be one of: [stuff drain]", s.Attack.Strategy)
+	}
+	if s.Attack.LandEvery <= 0 {
+		v.add("attack-land-every", "> 0", "attack land every (%d) must be positive", s.Attack.LandEvery)
+	}
+}
+
+// validateSearchParameters validates worst-case search parameters
+func (v *validator) validateSearchParameters(s *SimulationConfig) {
+	if !contains(validSearchMetrics, s.Search.Metric) {
+		v.add("search-metric", oneOf(validSearchMetrics), "invalid search metric '%s', must be one of: %v", s.Search.Metric, validSearchMetrics)
+	}
+	if s.Search.Method != "hill" && s.Search.Method != "genetic" {
+		v.add("search-method", "one of: hill, genetic", "invalid search method '%s', must be one of: [hill genetic]", s.Search.Method)
+	}
+	if s.Search.Iterations <= 0 {
+		v.add("search-iterations", "> 0", "search iterations (%d) must be positive", s.Search.Iterations)
+	}
+	if s.Search.Population < 2 {
+		v.add("search-population", ">= 2", "search population (%d) must be at least 2", s.Search.Population)
+	}
+	if s.Search.Blocks <= 0 {
+		v.add("search-blocks", "> 0", "search blocks (%d) must be positive", s.Search.Blocks)
+	}
+	if s.Search.MaxGas != 0 && s.Search.MaxGas < s.Search.MinGas {
+		v.add("search-max-gas", fmt.Sprintf("0 or >= %d", s.Search.MinGas),
+			"search max gas (%d) must be >= min gas (%d)", s.Search.MaxGas, s.Search.MinGas)
+	}
+	if s.Search.Mutation <= 0 || s.Search.Mutation > 1.0 {
+		v.add("search-mutation", "(0, 1]", "search mutation (%.3f) must be between 0 and 1.0", s.Search.Mutation)
+	}
+}
+
+// validateMonteCarloParameters validates multi-seed batch settings
+func (v *validator) validateMonteCarloParameters(s *SimulationConfig) {
+	if s.MonteCarlo.Runs <= 0 {
+		v.add("mc-runs", "> 0", "monte carlo runs (%d) must be positive", s.MonteCarlo.Runs)
+	}
+	if s.MonteCarlo.Workers < 0 {
+		v.add("mc-workers", ">= 0", "monte carlo workers (%d) must not be negative", s.MonteCarlo.Workers)
+	}
+}
+
+// validateSweepParameters validates parameter sweep settings
+func (v *validator) validateSweepParameters(s *SimulationConfig) {
+	if s.Sweep.Mode != "grid" && s.Sweep.Mode != "random" {
+		v.add("sweep-mode", "one of: grid, random", "invalid sweep mode '%s', must be one of: [grid random]", s.Sweep.Mode)
+	}
+	if s.Sweep.Samples <= 0 {
+		v.add("sweep-samples", "> 0", "sweep samples (%d) must be positive", s.Sweep.Samples)
+	}
+	if s.Sweep.Workers < 0 {
+		v.add("sweep-workers", ">= 0", "sweep workers (%d) must not be negative", s.Sweep.Workers)
+	}
+}
+
+// validateTuneParameters validates parameter tuning settings
+func (v *validator) validateTuneParameters(s *SimulationConfig) {
+	if s.Tune.Method != "nelder-mead" && s.Tune.Method != "random" {
+		v.add("tune-method", "one of: nelder-mead, random", "invalid tune method '%s', must be one of: [nelder-mead random]", s.Tune.Method)
+	}
+	if s.Tune.Iterations <= 0 {
+		v.add("tune-iterations", "> 0", "tune iterations (%d) must be positive", s.Tune.Iterations)
+	}
+	if s.Tune.Holdout < 0 || s.Tune.Holdout >= 1.0 {
+		v.add("tune-holdout", "[0, 1)", "tune holdout (%.3f) must be between 0.0 and 1.0 (exclusive)", s.Tune.Holdout)
+	}
+	if s.Tune.Segments <= 0 {
+		v.add("tune-segments", "> 0", "tune segments (%d) must be positive", s.Tune.Segments)
+	}
+}
+
+// validateSensitivityParameters validates global sensitivity analysis settings
+func (v *validator) validateSensitivityParameters(s *SimulationConfig) {
+	if s.Sensitivity.Method != "morris" && s.Sensitivity.Method != "sobol" {
+		v.add("sens-method", "one of: morris, sobol", "invalid sensitivity method '%s', must be one of: [morris sobol]", s.Sensitivity.Method)
+	}
+	if s.Sensitivity.Samples < 0 {
+		v.add("sens-samples", ">= 0", "sensitivity samples (%d) must not be negative", s.Sensitivity.Samples)
+	}
+	if s.Sensitivity.Levels < 2 || s.Sensitivity.Levels%2 != 0 {
+		v.add("sens-levels", "even, >= 2", "sensitivity levels (%d) must be an even number of at least 2", s.Sensitivity.Levels)
+	}
+}
+
+// validateResponseParameters validates closed-loop response analysis settings
+func (v *validator) validateResponseParameters(s *SimulationConfig) {
+	if s.Response.Blocks < 50 {
+		v.add("response-blocks", ">= 50", "response blocks (%d) must be at least 50", s.Response.Blocks)
+	}
+	if s.Response.StepSize <= 0 {
+		v.add("response-step", "> 0", "response step (%.3f) must be positive", s.Response.StepSize)
+	}
+	if s.Response.Elasticity <= 0 {
+		v.add("response-elasticity", "> 0", "response elasticity (%.3f) must be positive", s.Response.Elasticity)
+	}
+	if s.Response.Tolerance <= 0 || s.Response.Tolerance >= 1 {
+		v.add("response-tolerance", "(0, 1)", "response tolerance (%.3f) must be between 0 and 1", s.Response.Tolerance)
+	}
+	if s.Response.Amplitude <= 0 || s.Response.Amplitude >= 1 {
+		v.add("response-amplitude", "(0, 1)", "response amplitude (%.3f) must be between 0 and 1", s.Response.Amplitude)
+	}
+	if s.Response.Cycles <= 0 {
+		v.add("response-cycles", "> 0", "response cycles (%d) must be positive", s.Response.Cycles)
+	}
+}
+
+// validateEquilibriumParameters validates long-run equilibrium analysis settings
+func (v *validator) validateEquilibriumParameters(s *SimulationConfig) {
+	if s.Equilibrium.Blocks < 100 {
+		v.add("eq-blocks", ">= 100", "equilibrium blocks (%d) must be at least 100", s.Equilibrium.Blocks)
+	}
+}
+
+// validateSignificanceParameters validates paired significance test settings
+func (v *validator) validateSignificanceParameters(s *SimulationConfig) {
+	if !contains(validAdjusterTypes, s.Significance.Against) {
+		v.add("sig-against", oneOf(validAdjusterTypes), "invalid significance adjuster '%s', must be one of: [aimd eip1559 pid]", s.Significance.Against)
+	}
+	if s.Significance.Resamples < 100 {
+		v.add("sig-resamples", ">= 100", "significance resamples (%d) must be at least 100", s.Significance.Resamples)
+	}
+	if s.Significance.Confidence <= 0 || s.Significance.Confidence >= 1 {
+		v.add("sig-confidence", "(0, 1)", "significance confidence (%.3f) must be between 0 and 1", s.Significance.Confidence)
+	}
+	if s.Significance.Segments < 2 {
+		v.add("sig-segments", ">= 2", "significance segments (%d) must be at least 2", s.Significance.Segments)
+	}
+}
+
+// validateCompareParameters validates comparison settings and every variant's configuration.
+// A variant reports only the problems its own adjuster type and overrides introduce.
+func (v *validator) validateCompareParameters(cfg Config) {
+	s := &cfg.Simulation
+	if len(s.Compare.Variants) == 0 {
+		return
+	}
+	if len(s.AdjusterTypeList()) > 1 {
+		v.add("variant", "a single -adjuster-type",
+			"-variant cannot be combined with a comma-separated -adjuster-type; define one variant per algorithm")
+		return
+	}
+
+	variants, err := ParseVariants(s.Compare.Variants)
+	if err != nil {
+		v.add("variant", "name:adjuster-type[:param=value,...]", "%v", err)
+		return
+	}
+
+	reported := make(map[FieldError]bool, len(v.errors))
+	for _, fieldErr := range v.errors {
+		reported[fieldErr] = true
+	}
+	for _, variant := range variants {
+		variantCfg, err := variant.Apply(cfg)
+		if err != nil {
+			v.add("variant", "", "%v", err)
+			continue
+		}
+		variantValidator := newValidator(&variantCfg)
+		variantValidator.validate(&variantCfg)
+		for _, fieldErr := range variantValidator.errors {
+			if reported[fieldErr] {
+				continue
+			}
+			fieldErr.Message = fmt.Sprintf("variant %s: %s", variant.Name, fieldErr.Message)
+			v.errors = append(v.errors, fieldErr)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+// flagsOf returns the flag of every field error, failing unless err is a *ValidationError
+func flagsOf(t *testing.T, err error) []string {
+	t.Helper()
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	flags := make([]string, len(validationErr.Errors))
+	for i, fieldErr := range validationErr.Errors {
+		flags[i] = fieldErr.Flag
+	}
+	return flags
+}
+
+func TestValidateCollectsEveryError(t *testing.T) {
+	if err := Validate(Default()); err != nil {
+		t.Fatalf("default configuration is invalid: %v", err)
+	}
+
+	cfg := Default()
+	cfg.Simulation.AdjusterType = "eip1559" // Sections of adjusters that do not run are still checked
+	cfg.BurstMultiplier = 0.5
+	cfg.Adjuster.AIMD.Gamma = 3
+	cfg.Adjuster.PID.Kd = -1
+	cfg.Simulation.Randomizer.GaussianNoise = 2
+	cfg.Simulation.Scenario = "bogus"
+
+	flags := flagsOf(t, Validate(cfg))
+	want := []string{"burst-multiplier", "aimd-gamma", "pid-kd", "rng-gaussian-noise", "scenario"}
+	if len(flags) != len(want) {
+		t.Fatalf("expected errors for %v, got %v", want, flags)
+	}
+	for i := range want {
+		if flags[i] != want[i] {
+			t.Errorf("error %d: expected %s, got %s", i, want[i], flags[i])
+		}
+	}
+
+	var validationErr *ValidationError
+	errors.As(Validate(cfg), &validationErr)
+	gamma := validationErr.Errors[1]
+	if gamma.Field != "Adjuster.AIMD.Gamma" || gamma.Value != "3" || gamma.Allowed != "[0, 2]" {
+		t.Errorf("unexpected gamma error: %+v", gamma)
+	}
+}
+
+func TestValidateCrossField(t *testing.T) {
+	tests := []struct {
+		name  string
+		apply func(cfg *Config)
+		flag  string
+	}{
+		{"integral bounds", func(cfg *Config) { cfg.Adjuster.PID.MinIntegral = cfg.Adjuster.PID.MaxIntegral }, "pid-max-integral"},
+		{"learning rate bounds", func(cfg *Config) { cfg.Adjuster.AIMD.MaxLearningRate = 0.0001 }, "aimd-max-learning-rate"},
+		{"initial below min", func(cfg *Config) { cfg.Adjuster.AIMD.InitialLearningRate = 0.0001 }, "aimd-initial-learning-rate"},
+		{"initial above max", func(cfg *Config) { cfg.Adjuster.AIMD.InitialLearningRate = 0.9 }, "aimd-initial-learning-rate"},
+		{"burst durations", func(cfg *Config) {
+			cfg.Simulation.Randomizer.BurstDurationMin = 5
+			cfg.Simulation.Randomizer.BurstDurationMax = 2
+		}, "rng-burst-duration-max"},
+		{"base fee bounds", func(cfg *Config) { cfg.MinBaseFee = 2 * cfg.InitialBaseFee }, "initial-base-fee"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg := Default()
+			test.apply(&cfg)
+			flags := flagsOf(t, Validate(cfg))
+			if len(flags) != 1 || flags[0] != test.flag {
+				t.Errorf("expected one error for %s, got %v", test.flag, flags)
+			}
+		})
+	}
+}
+
+func TestValidateVariantsReportOwnErrors(t *testing.T) {
+	cfg := Default()
+	cfg.Adjuster.AIMD.Beta = 2
+	cfg.Simulation.Compare.Variants = []string{"a:aimd", "b:pid:pid-kp=-1"}
+
+	// The shared beta error is reported once, not again for each variant
+	flags := flagsOf(t, Validate(cfg))
+	if len(flags) != 2 || flags[0] != "aimd-beta" || flags[1] != "pid-kp" {
+		t.Errorf("expected errors for aimd-beta and pid-kp, got %v", flags)
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	path := writeFile(t, "bad.toml", `aimd-gamma = 5
+bogus = 1
+
+[pid]
+kd = "x"
+
+[profiles.fixed]
+aimd-gamma = 0.5
+`)
+
+	flags := flagsOf(t, ValidateFile(path, ""))
+	want := []string{"bogus", "pid-kd", "aimd-gamma"}
+	if len(flags) != len(want) {
+		t.Fatalf("expected errors for %v, got %v", want, flags)
+	}
+	for i := range want {
+		if flags[i] != want[i] {
+			t.Errorf("error %d: expected %s, got %s", i, want[i], flags[i])
+		}
+	}
+
+	// The profile fixes gamma but not the file's other problems
+	flags = flagsOf(t, ValidateFile(path, "fixed"))
+	if len(flags) != 2 {
+		t.Errorf("expected 2 errors with the profile, got %v", flags)
+	}
+
+	if err := ValidateFile(path, "missing"); err == nil {
+		t.Error("expected an error for an unknown profile")
+	}
+	if err := ValidateFile(writeFile(t, "ok.json", `{"aimd": {"gamma": 0.5}}`), ""); err != nil {
+		t.Errorf("expected a valid file, got %v", err)
+	}
+}
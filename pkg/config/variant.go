@@ -0,0 +1,134 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Variant is a named adjuster configuration compared side by side with other variants
+type Variant struct {
+	Name         string
+	AdjusterType string
+	Overrides    []Override // Applied in order on top of the shared configuration
+}
+
+// Override sets one numeric parameter by its command-line flag name
+type Override struct {
+	Name  string
+	Value float64
+}
+
+// variantCoreParams are the core parameters a variant may override besides its adjuster's own
+var variantCoreParams = map[string]bool{
+	"target-block-size": true,
+	"burst-multiplier":  true,
+	"initial-base-fee":  true,
+	"min-base-fee":      true,
+	"window-size":       true,
+}
+
+// ParseVariant parses a variant of the form name:adjuster-type[:param=value,...], e.g.
+// aggressive:aimd:aimd-gamma=0.1,aimd-alpha=0.02. Parameters are core or adjuster flags.
+func ParseVariant(text string) (Variant, error) {
+	parts := strings.SplitN(text, ":", 3)
+	if len(parts) < 2 {
+		return Variant{}, fmt.Errorf("invalid variant %q, expected name:adjuster-type[:param=value,...]", text)
+	}
+
+	variant := Variant{
+		Name:         strings.TrimSpace(parts[0]),
+		AdjusterType: strings.TrimSpace(parts[1]),
+	}
+	if variant.Name == "" {
+		return Variant{}, fmt.Errorf("invalid variant %q, name is empty", text)
+	}
+	for _, r := range variant.Name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return Variant{}, fmt.Errorf("invalid variant name %q, use letters, digits, '-' and '_'", variant.Name)
+		}
+	}
+
+	if len(parts) < 3 {
+		return variant, nil
+	}
+	seen := make(map[string]bool)
+	for _, assignment := range strings.Split(parts[2], ",") {
+		if strings.TrimSpace(assignment) == "" {
+			continue
+		}
+		name, valueText, found := strings.Cut(assignment, "=")
+		name = strings.TrimPrefix(strings.TrimSpace(name), "-")
+		if !found || name == "" {
+			return Variant{}, fmt.Errorf("invalid override %q in variant %s, expected param=value", assignment, variant.Name)
+		}
+		if !isVariantParam(name) {
+			return Variant{}, fmt.Errorf("variant %s cannot override %s, only core and adjuster parameters", variant.Name, name)
+		}
+		if seen[name] {
+			return Variant{}, fmt.Errorf("variant %s overrides %s more than once", variant.Name, name)
+		}
+		seen[name] = true
+
+		value, err := ParseParamValue(name, valueText)
+		if err != nil {
+			return Variant{}, fmt.Errorf("invalid value for %s in variant %s: %w", name, variant.Name, err)
+		}
+		variant.Overrides = append(variant.Overrides, Override{Name: name, Value: value})
+	}
+	return variant, nil
+}
+
+// ParseVariants parses every variant and rejects duplicate names
+func ParseVariants(texts []string) ([]Variant, error) {
+	variants := make([]Variant, 0, len(texts))
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		variant, err := ParseVariant(text)
+		if err != nil {
+			return nil, err
+		}
+		if seen[variant.Name] {
+			return nil, fmt.Errorf("variant %s is defined more than once", variant.Name)
+		}
+		seen[variant.Name] = true
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// Apply returns a copy of cfg running only this variant: its adjuster type, its overrides and its label
+func (v Variant) Apply(cfg Config) (Config, error) {
+	cfg.Simulation.AdjusterType = v.AdjusterType
+	cfg.Simulation.AdjusterTypes = []string{v.AdjusterType}
+	cfg.Simulation.Compare.Variants = nil
+	cfg.Simulation.Label = v.Name
+
+	for _, override := range v.Overrides {
+		if err := SetParam(&cfg, override.Name, override.Value); err != nil {
+			return Config{}, fmt.Errorf("variant %s: %w", v.Name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Flags formats the overrides as command-line flags
+func (v Variant) Flags() string {
+	flags := make([]string, len(v.Overrides))
+	for i, override := range v.Overrides {
+		flags[i] = fmt.Sprintf("-%s=%g", override.Name, override.Value)
+	}
+	return strings.Join(flags, " ")
+}
+
+// isVariantParam reports whether a variant may override the named parameter
+func isVariantParam(name string) bool {
+	if variantCoreParams[name] {
+		return true
+	}
+	for _, prefix := range []string{"aimd-", "eip1559-", "pid-"} {
+		if strings.HasPrefix(name, prefix) {
+			return IsParam(name)
+		}
+	}
+	return false
+}
@@ -0,0 +1,170 @@
+package dynamics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Curve is a demand curve: the gas users want to include at a given base fee in wei.
+// Demand must not increase with the fee.
+type Curve interface {
+	GasAt(fee float64) float64
+}
+
+// Demand is a constant-elasticity demand curve: the gas users want to include at a given base fee.
+// At the reference fee demand is Scale times the target block size, and a 1% higher fee
+// lowers demand by Elasticity percent.
+type Demand struct {
+	TargetBlockSize uint64
+	ReferenceFee    float64 // Base fee in wei at which demand is Scale x target
+	Scale           float64 // Demand at the reference fee, in multiples of the target block size
+	Elasticity      float64 // Price elasticity of demand (positive)
+}
+
+// GasAt returns the gas demanded at the given base fee
+func (d Demand) GasAt(fee float64) float64 {
+	if fee <= 0 {
+		return math.Inf(1)
+	}
+	return d.Scale * float64(d.TargetBlockSize) * math.Pow(fee/d.ReferenceFee, -d.Elasticity)
+}
+
+// EquilibriumFee returns the base fee at which demand equals the target block size
+func (d Demand) EquilibriumFee() float64 {
+	return d.ReferenceFee * math.Pow(d.Scale, 1/d.Elasticity)
+}
+
+// LinearDemand falls linearly from MaxGas at a zero fee to nothing at the choke fee
+type LinearDemand struct {
+	MaxGas   float64 // Gas demanded at a zero fee
+	ChokeFee float64 // Base fee in wei at which demand reaches zero
+}
+
+// GasAt returns the gas demanded at the given base fee
+func (d LinearDemand) GasAt(fee float64) float64 {
+	return math.Max(d.MaxGas*(1-fee/d.ChokeFee), 0)
+}
+
+// TableDemand interpolates demand between points, linearly in the log of the fee.
+// Demand is constant beyond the first and last points.
+type TableDemand struct {
+	Fees []float64 // Base fees in wei, ascending
+	Gas  []float64 // Gas demanded at each fee
+}
+
+// GasAt returns the gas demanded at the given base fee
+func (d TableDemand) GasAt(fee float64) float64 {
+	n := len(d.Fees)
+	switch {
+	case fee <= d.Fees[0]:
+		return d.Gas[0]
+	case fee >= d.Fees[n-1]:
+		return d.Gas[n-1]
+	}
+
+	i := sort.SearchFloat64s(d.Fees, fee)
+	fraction := math.Log(fee/d.Fees[i-1]) / math.Log(d.Fees[i]/d.Fees[i-1])
+	return d.Gas[i-1] + fraction*(d.Gas[i]-d.Gas[i-1])
+}
+
+// ParseDemand parses a demand curve specification. Gas is in multiples of the target block
+// size and fees are in gwei:
+//
+//	elastic:scale:elasticity  constant elasticity, scale x target at the reference fee
+//	linear:gas:choke          gas x target at a zero fee, falling linearly to nothing at the choke fee
+//	points:fee=gas,...        interpolated between points, e.g. points:0.5=1.8,1=1.2,2=0.6
+func ParseDemand(spec string, targetBlockSize uint64, referenceFee float64) (Curve, error) {
+	kind, args, _ := strings.Cut(strings.ReplaceAll(spec, " ", ""), ":")
+	target := float64(targetBlockSize)
+
+	switch kind {
+	case "elastic":
+		numbers, err := parseDemandNumbers(args, 2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid elastic demand %q: %w", spec, err)
+		}
+		return Demand{
+			TargetBlockSize: targetBlockSize,
+			ReferenceFee:    referenceFee,
+			Scale:           numbers[0],
+			Elasticity:      numbers[1],
+		}, nil
+
+	case "linear":
+		numbers, err := parseDemandNumbers(args, 2)
+		if err != nil {
+			return nil, fmt.Errorf("invalid linear demand %q: %w", spec, err)
+		}
+		return LinearDemand{MaxGas: numbers[0] * target, ChokeFee: numbers[1] * 1e9}, nil
+
+	case "points":
+		var table TableDemand
+		for _, point := range strings.Split(args, ",") {
+			feeText, gasText, found := strings.Cut(point, "=")
+			fee, feeErr := strconv.ParseFloat(feeText, 64)
+			gas, gasErr := strconv.ParseFloat(gasText, 64)
+			if !found || feeErr != nil || gasErr != nil || fee <= 0 || gas < 0 {
+				return nil, fmt.Errorf("invalid demand point %q, expected fee=gas with a positive fee", point)
+			}
+			table.Fees = append(table.Fees, fee*1e9)
+			table.Gas = append(table.Gas, gas*target)
+		}
+		for i := 1; i < len(table.Fees); i++ {
+			if table.Fees[i] <= table.Fees[i-1] || table.Gas[i] > table.Gas[i-1] {
+				return nil, fmt.Errorf("demand points must have ascending fees and non-increasing gas")
+			}
+		}
+		if len(table.Fees) < 2 {
+			return nil, fmt.Errorf("demand table needs at least 2 points")
+		}
+		return table, nil
+
+	default:
+		return nil, fmt.Errorf("unknown demand curve %q, must be elastic, linear or points", kind)
+	}
+}
+
+// parseDemandNumbers parses count colon-separated positive numbers
+func parseDemandNumbers(args string, count int) ([]float64, error) {
+	parts := strings.Split(args, ":")
+	if len(parts) != count {
+		return nil, fmt.Errorf("expected %d values, got %d", count, len(parts))
+	}
+	numbers := make([]float64, count)
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(part, 64)
+		if err != nil || number <= 0 {
+			return nil, fmt.Errorf("value %q must be a positive number", part)
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// EquilibriumFee finds the base fee at which the curve demands exactly the target block size,
+// searching between minFee and 10^18 wei. It reports false if demand at the upper bound is
+// still above target. If demand at minFee is already below target, minFee is returned.
+func EquilibriumFee(curve Curve, targetBlockSize uint64, minFee float64) (float64, bool) {
+	target := float64(targetBlockSize)
+	low, high := math.Max(minFee, 1), 1e18
+	if curve.GasAt(low) <= target {
+		return low, true
+	}
+	if curve.GasAt(high) > target {
+		return 0, false
+	}
+
+	// Bisect on the log of the fee, which keeps relative precision across many orders of magnitude
+	for i := 0; i < 200 && high/low > 1+1e-12; i++ {
+		mid := math.Sqrt(low * high)
+		if curve.GasAt(mid) > target {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return math.Sqrt(low * high), true
+}
@@ -0,0 +1,226 @@
+package dynamics
+
+import (
+	"math"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+func TestDemandEquilibrium(t *testing.T) {
+	demand := Demand{
+		TargetBlockSize: 15_000_000,
+		ReferenceFee:    1_000_000_000,
+		Scale:           4,
+		Elasticity:      2,
+	}
+
+	fee := demand.EquilibriumFee()
+	if math.Abs(fee-2_000_000_000) > 1e-3 {
+		t.Errorf("Expected equilibrium fee 2 gwei, got %f", fee)
+	}
+	if gas := demand.GasAt(fee); math.Abs(gas-15_000_000) > 1e-3 {
+		t.Errorf("Expected target demand at equilibrium, got %f", gas)
+	}
+}
+
+func TestMeasureStep(t *testing.T) {
+	// A step from 1 to 2 that rises over 5 blocks, overshoots to 2.2, undershoots to 1.9
+	// and overshoots again to 2.1 before settling
+	blocks := Onset + 100
+	r := Response{
+		Input:       InputStep,
+		BaseFees:    make([]float64, blocks),
+		Equilibrium: make([]float64, blocks),
+	}
+	for t := range r.BaseFees {
+		r.Equilibrium[t] = 2
+		r.BaseFees[t] = 2
+		if t < Onset {
+			r.Equilibrium[t] = 1
+			r.BaseFees[t] = 1
+		}
+	}
+	for i, fee := range []float64{1, 1.05, 1.15, 1.5, 1.95, 2} {
+		r.BaseFees[Onset+i] = fee
+	}
+	r.BaseFees[Onset+6] = 2.2
+	r.BaseFees[Onset+9] = 1.9
+	r.BaseFees[Onset+12] = 2.1
+
+	m := Measure(r, 0.02)
+	if m.RiseTime != 2 {
+		t.Errorf("Expected rise time 2, got %f", m.RiseTime)
+	}
+	if math.Abs(m.Overshoot-20) > 1e-9 {
+		t.Errorf("Expected 20%% overshoot, got %f", m.Overshoot)
+	}
+	if m.SettlingTime != 13 {
+		t.Errorf("Expected settling time 13, got %f", m.SettlingTime)
+	}
+	if math.Abs(m.DecayRatio-0.5) > 1e-9 {
+		t.Errorf("Expected decay ratio 0.5, got %f", m.DecayRatio)
+	}
+	if m.SteadyStateError != 0 {
+		t.Errorf("Expected no steady-state error, got %f", m.SteadyStateError)
+	}
+}
+
+func TestSimulateEIP1559Step(t *testing.T) {
+	cfg := config.Default()
+
+	response, err := Simulate(simulator.AdjusterTypeEIP1559, cfg, InputStep)
+	if err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+	if len(response.BaseFees) != cfg.Simulation.Response.Blocks {
+		t.Fatalf("Expected %d blocks, got %d", cfg.Simulation.Response.Blocks, len(response.BaseFees))
+	}
+
+	// Before the step the fee stays at equilibrium
+	if response.BaseFees[Onset] != float64(cfg.InitialBaseFee) {
+		t.Errorf("Expected the fee at the onset to be the initial fee, got %f", response.BaseFees[Onset])
+	}
+
+	m := response.Metrics
+	if math.IsNaN(m.SettlingTime) || math.IsNaN(m.RiseTime) {
+		t.Errorf("Expected EIP-1559 to settle after a step, got %+v", m)
+	}
+	if math.Abs(m.SteadyStateError) > 1 {
+		t.Errorf("Expected steady-state error under 1%%, got %f", m.SteadyStateError)
+	}
+}
+
+func TestParsePeriods(t *testing.T) {
+	periods, err := ParsePeriods("64, 2,16")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(periods) != 3 || periods[0] != 2 || periods[2] != 64 {
+		t.Errorf("Expected sorted periods [2 16 64], got %v", periods)
+	}
+
+	for _, spec := range []string{"1", "abc", "", "8,0"} {
+		if _, err := ParsePeriods(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestEIP1559FrequencyResponse(t *testing.T) {
+	cfg := config.Default()
+
+	response, err := SimulateFrequencies(simulator.AdjusterTypeEIP1559, cfg, []int{4, 512})
+	if err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+
+	fast, slow := response.Points[0], response.Points[1]
+	if fast.Gain >= 0.5 {
+		t.Errorf("Expected fast demand cycles to be smoothed, got gain %f", fast.Gain)
+	}
+	if math.Abs(slow.Gain-1) > 0.05 {
+		t.Errorf("Expected slow demand cycles to be tracked, got gain %f", slow.Gain)
+	}
+	if slow.Phase >= 0 || slow.LagBlocks <= 0 {
+		t.Errorf("Expected the fee to lag demand, got phase %f", slow.Phase)
+	}
+	if response.Peak().Period != 512 {
+		t.Errorf("Expected the peak gain at the slow period, got %f", response.Peak().Period)
+	}
+}
+
+func TestParseDemand(t *testing.T) {
+	const target = 15_000_000
+	tests := []struct {
+		spec     string
+		fee      float64 // Fee in wei at which to check demand
+		expected float64 // Expected gas in multiples of target
+	}{
+		{"elastic:2:1", 2e9, 1},
+		{"linear:3:4", 2e9, 1.5},
+		{"points:1=2,4=1", 2e9, 1.5},
+		{"points:1=2,4=1", 10e9, 1},
+	}
+	for _, tt := range tests {
+		curve, err := ParseDemand(tt.spec, target, 1e9)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tt.spec, err)
+			continue
+		}
+		if gas := curve.GasAt(tt.fee) / target; math.Abs(gas-tt.expected) > 1e-9 {
+			t.Errorf("%q at %.0f wei: expected %f x target, got %f", tt.spec, tt.fee, tt.expected, gas)
+		}
+	}
+
+	for _, spec := range []string{"elastic:2", "linear:-1:2", "points:2=1,1=2", "points:1=1,2=2", "cubic:1:2"} {
+		if _, err := ParseDemand(spec, target, 1e9); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestEquilibriumFee(t *testing.T) {
+	curve := LinearDemand{MaxGas: 30_000_000, ChokeFee: 4e9}
+	fee, ok := EquilibriumFee(curve, 15_000_000, 0)
+	if !ok || math.Abs(fee-2e9) > 1 {
+		t.Errorf("Expected equilibrium at 2 gwei, got %f (%v)", fee, ok)
+	}
+
+	// Demand above target at every fee has no equilibrium
+	saturated := TableDemand{Fees: []float64{1e9, 2e9}, Gas: []float64{40_000_000, 20_000_000}}
+	if _, ok := EquilibriumFee(saturated, 15_000_000, 0); ok {
+		t.Error("Expected no equilibrium when demand always exceeds target")
+	}
+}
+
+func TestFindEquilibrium(t *testing.T) {
+	cfg := config.Default()
+	curve, err := ParseDemand("elastic:1.5:1", cfg.TargetBlockSize, float64(cfg.InitialBaseFee))
+	if err != nil {
+		t.Fatalf("Failed to parse demand: %v", err)
+	}
+
+	result, err := FindEquilibrium(simulator.AdjusterTypeEIP1559, cfg, curve, 1000)
+	if err != nil {
+		t.Fatalf("Simulation failed: %v", err)
+	}
+	if result.Status != StatusConverged {
+		t.Errorf("Expected EIP-1559 to converge, got %s", result.Status)
+	}
+	if math.Abs(result.Fee/result.TheoreticalFee-1) > 0.01 || math.Abs(result.Utilization-1) > 0.01 {
+		t.Errorf("Expected the fee to clear demand at target, got fee %f (theoretical %f), utilization %f",
+			result.Fee, result.TheoreticalFee, result.Utilization)
+	}
+}
+
+func TestClassify(t *testing.T) {
+	n := 400
+	build := func(fee func(t int) float64) Equilibrium {
+		e := Equilibrium{TheoreticalFee: 1e9, BaseFees: make([]float64, n), Utilizations: make([]float64, n)}
+		for t := range e.BaseFees {
+			e.BaseFees[t] = fee(t)
+			e.Utilizations[t] = 1
+		}
+		classify(&e)
+		return e
+	}
+
+	cycle := build(func(t int) float64 { return 1e9 * (1 + 0.1*math.Sin(2*math.Pi*float64(t)/10)) })
+	if cycle.Status != StatusLimitCycle || math.Abs(cycle.Period-10) > 0.5 {
+		t.Errorf("Expected a limit cycle with period 10, got %s with period %f", cycle.Status, cycle.Period)
+	}
+
+	damped := build(func(t int) float64 {
+		return 1e9 * (1 + 0.5*math.Exp(-float64(t)/150)*math.Sin(2*math.Pi*float64(t)/10))
+	})
+	if damped.Status != StatusConverging {
+		t.Errorf("Expected a damped oscillation to be converging, got %s", damped.Status)
+	}
+
+	drifting := build(func(t int) float64 { return 1e9 * math.Exp(float64(t)/100) })
+	if drifting.Status != StatusDiverges {
+		t.Errorf("Expected a fee drifting away from equilibrium to diverge, got %s", drifting.Status)
+	}
+}
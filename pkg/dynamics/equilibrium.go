@@ -0,0 +1,230 @@
+package dynamics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// settledBand is the peak-to-peak variation of the log fee, about 1%, below which the fee counts as constant
+const settledBand = 0.01
+
+// Status describes the long-run behavior of an adjuster under constant demand
+type Status string
+
+const (
+	// StatusConverged means the fee settled on a fixed point
+	StatusConverged Status = "converged"
+	// StatusConverging means the fee oscillates with shrinking amplitude but has not settled yet
+	StatusConverging Status = "converging"
+	// StatusLimitCycle means the fee keeps oscillating with constant amplitude
+	StatusLimitCycle Status = "limit cycle"
+	// StatusDiverges means the fee drifts away without settling
+	StatusDiverges Status = "diverges"
+)
+
+// Equilibrium is the long-run behavior of one adjuster under a constant demand curve
+type Equilibrium struct {
+	AdjusterType   string
+	Status         Status
+	TheoreticalFee float64 // Fee at which demand equals the target block size, 0 if there is none
+	Fee            float64 // Geometric mean fee over the final quarter of blocks, in wei
+	Utilization    float64 // Mean gas used over the final quarter, relative to the target block size
+	Amplitude      float64 // Half the peak-to-peak fee range over the final eighth, in % of the mean fee
+	Period         float64 // Dominant oscillation period of a limit cycle in blocks, 0 otherwise
+	SettledBlock   int     // First block after which the fee stays within 1% of its final value, -1 if never
+	BaseFees       []float64
+	Utilizations   []float64
+}
+
+// FindEquilibrium simulates a fresh adjuster for the given number of blocks with a fixed demand
+// curve in closed loop, starting from the configured initial base fee, and classifies where it goes
+func FindEquilibrium(adjusterType simulator.AdjusterType, cfg config.Config, curve Curve, blocks int) (Equilibrium, error) {
+	adjuster, err := simulator.NewAdjusterFactory().CreateAdjusterWithConfigs(adjusterType, &cfg)
+	if err != nil {
+		return Equilibrium{}, fmt.Errorf("failed to create adjuster: %w", err)
+	}
+
+	target := float64(cfg.TargetBlockSize)
+	maxGas := float64(adjuster.GetMaxBlockSize())
+	result := Equilibrium{
+		AdjusterType: string(adjusterType),
+		BaseFees:     make([]float64, blocks),
+		Utilizations: make([]float64, blocks),
+	}
+	if fee, ok := EquilibriumFee(curve, cfg.TargetBlockSize, float64(cfg.MinBaseFee)); ok {
+		result.TheoreticalFee = fee
+	}
+
+	for t := 0; t < blocks; t++ {
+		fee := float64(adjuster.GetCurrentState().BaseFee)
+		gas := math.Min(curve.GasAt(fee), maxGas)
+		result.BaseFees[t] = fee
+		result.Utilizations[t] = gas / target
+		adjuster.ProcessBlock(uint64(gas))
+	}
+
+	classify(&result)
+	return result, nil
+}
+
+// classify sets the status and summary statistics from the final blocks of the path.
+// The last quarter is split in half: a constant second half has converged, and a second half
+// that has moved by more than its own swing is still converging if it moved toward the
+// theoretical fee and diverging otherwise. Else the swings of the two halves tell a damped
+// oscillation from a sustained one.
+func classify(e *Equilibrium) {
+	n := len(e.BaseFees)
+	logFees := make([]float64, n)
+	for i, fee := range e.BaseFees {
+		logFees[i] = math.Log(math.Max(fee, 1))
+	}
+
+	tail := logFees[n-n/4:]
+	first, second := tail[:len(tail)/2], tail[len(tail)/2:]
+	swing1, mean1 := spread(first)
+	swing2, mean2 := spread(second)
+
+	var utilization float64
+	for _, u := range e.Utilizations[n-n/4:] {
+		utilization += u
+	}
+	e.Utilization = utilization / float64(n/4)
+	e.Fee = math.Exp((mean1 + mean2) / 2)
+	e.Amplitude = (math.Exp(swing2/2) - 1) * 100
+
+	switch {
+	case swing2 < settledBand:
+		e.Status = StatusConverged
+	case math.Abs(mean2-mean1) > swing2:
+		e.Status = StatusDiverges
+		if e.TheoreticalFee > 0 {
+			target := math.Log(e.TheoreticalFee)
+			if math.Abs(mean2-target) < math.Abs(mean1-target) {
+				e.Status = StatusConverging
+			}
+		}
+	case swing2 < 0.8*swing1:
+		e.Status = StatusConverging
+	default:
+		e.Status = StatusLimitCycle
+		if periods := analysis.DominantPeriods(tail, 1); len(periods) > 0 {
+			e.Period = periods[0].Blocks
+		}
+	}
+
+	// Without a market-clearing fee there is no fixed point, and fees that run to zero or
+	// explode (possibly wrapping around) diverge even if the log fee looks steady
+	final := e.BaseFees[n-1]
+	if e.TheoreticalFee == 0 || final <= 1 || final > 1000*e.TheoreticalFee {
+		e.Status = StatusDiverges
+	}
+
+	e.SettledBlock = -1
+	if e.Status == StatusConverged {
+		finalLog := logFees[n-1]
+		e.SettledBlock = 0
+		for i := n - 1; i >= 0; i-- {
+			if math.Abs(logFees[i]-finalLog) > settledBand {
+				e.SettledBlock = i + 1
+				break
+			}
+		}
+	}
+}
+
+// spread returns the peak-to-peak range and the mean of the values
+func spread(values []float64) (float64, float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	var sum float64
+	for _, v := range values {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+		sum += v
+	}
+	return high - low, sum / float64(len(values))
+}
+
+// RunEquilibria finds the equilibrium of every adjuster type under the configured demand curve
+func RunEquilibria(cfg config.Config, adjusterTypes []simulator.AdjusterType) (Curve, []Equilibrium, error) {
+	e := cfg.Simulation.Equilibrium
+	curve, err := ParseDemand(e.Demand, cfg.TargetBlockSize, float64(cfg.InitialBaseFee))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var results []Equilibrium
+	for _, adjusterType := range adjusterTypes {
+		result, err := FindEquilibrium(adjusterType, cfg, curve, e.Blocks)
+		if err != nil {
+			return nil, nil, err
+		}
+		results = append(results, result)
+	}
+	return curve, results, nil
+}
+
+// PrintEquilibria prints the long-run behavior of every adjuster
+func PrintEquilibria(w io.Writer, results []Equilibrium, cfg config.Config) {
+	e := cfg.Simulation.Equilibrium
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "EQUILIBRIUM ANALYSIS (demand %s, %d blocks)\n", e.Demand, e.Blocks)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	if len(results) > 0 {
+		if results[0].TheoreticalFee > 0 {
+			fmt.Fprintf(w, "Demand equals target at %s\n\n", config.FormatGwei(results[0].TheoreticalFee))
+		} else {
+			fmt.Fprintf(w, "Demand exceeds target at every fee: no fee clears the market\n\n")
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Algorithm\tStatus\tFee\tUtilization\tAmplitude %\tPeriod\tSettled At")
+	for _, result := range results {
+		period, settled := "-", "-"
+		if result.Period > 0 {
+			period = fmt.Sprintf("%.1f", result.Period)
+		}
+		if result.SettledBlock >= 0 {
+			settled = fmt.Sprintf("%d", result.SettledBlock)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.3f\t%.2f\t%s\t%s\n",
+			result.AdjusterType, result.Status, config.FormatGwei(result.Fee), result.Utilization,
+			result.Amplitude, period, settled)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nFee and utilization are averages over the final quarter of blocks;\n")
+	fmt.Fprintf(w, "utilization is gas used relative to the target block size.\n")
+}
+
+// EquilibriaTable returns the equilibrium of every adjuster. Fees are in wei. The theoretical fee is
+// empty when no fee clears the market, the period without a limit cycle and the settled block when
+// the fee never settles.
+func EquilibriaTable(results []Equilibrium) *output.Table {
+	table := output.NewTable("equilibria", "Equilibrium Analysis", "adjuster_type", "status", "theoretical_fee_wei",
+		"fee_wei", "utilization", "amplitude_percent", "period", "settled_block")
+	for _, result := range results {
+		var theoretical, period, settled interface{}
+		if result.TheoreticalFee > 0 {
+			theoretical = result.TheoreticalFee
+		}
+		if result.Period > 0 {
+			period = result.Period
+		}
+		if result.SettledBlock >= 0 {
+			settled = result.SettledBlock
+		}
+		table.Append(result.AdjusterType, string(result.Status), theoretical, result.Fee,
+			result.Utilization, result.Amplitude, period, settled)
+	}
+	return table
+}
@@ -0,0 +1,183 @@
+package dynamics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/cmplx"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// warmupCycles are the sinusoid periods simulated before measuring, so start-up transients decay
+const warmupCycles = 2
+
+// FrequencyPoint is the base fee's response to sinusoidal demand of one period.
+// Gain and phase compare the log base fee with the log equilibrium fee, so a gain of 1 with
+// no phase lag means the fee tracks equilibrium exactly.
+type FrequencyPoint struct {
+	Period    float64 // Demand period in blocks
+	Gain      float64 // Fee amplitude relative to the equilibrium fee amplitude
+	GainDB    float64 // Gain in decibels, 20*log10(Gain)
+	Phase     float64 // Phase of the fee relative to equilibrium in degrees; negative means lag
+	LagBlocks float64 // Phase lag converted to blocks
+}
+
+// FrequencyResponse is the gain and phase of one adjuster across demand periods
+type FrequencyResponse struct {
+	AdjusterType string
+	Points       []FrequencyPoint // Ordered by period, shortest first
+}
+
+// Peak returns the point with the largest gain
+func (f FrequencyResponse) Peak() FrequencyPoint {
+	var peak FrequencyPoint
+	for i, point := range f.Points {
+		if i == 0 || point.Gain > peak.Gain {
+			peak = point
+		}
+	}
+	return peak
+}
+
+// ParsePeriods parses a comma-separated list of demand periods in blocks, each at least 2
+func ParsePeriods(spec string) ([]int, error) {
+	var periods []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		period, err := strconv.Atoi(part)
+		if err != nil || period < 2 {
+			return nil, fmt.Errorf("invalid period %q, must be an integer of at least 2 blocks", part)
+		}
+		periods = append(periods, period)
+	}
+	if len(periods) == 0 {
+		return nil, fmt.Errorf("no periods given")
+	}
+	sort.Ints(periods)
+	return periods, nil
+}
+
+// SimulateSinusoid drives a fresh adjuster in closed loop with demand that oscillates by the
+// configured amplitude around the reference curve, and measures gain and phase at that period
+func SimulateSinusoid(adjusterType simulator.AdjusterType, cfg config.Config, period int) (FrequencyPoint, error) {
+	r := cfg.Simulation.Response
+	omega := 2 * math.Pi / float64(period)
+
+	// Cosine rather than sine so that the shortest period, 2 blocks, is not sampled at its zeros
+	scale := func(t int) float64 {
+		if t < Onset {
+			return 1
+		}
+		return 1 + r.Amplitude*math.Cos(omega*float64(t-Onset))
+	}
+
+	start := Onset + warmupCycles*period
+	blocks := start + r.Cycles*period
+	response, err := closedLoop(adjusterType, cfg, blocks, scale, nil)
+	if err != nil {
+		return FrequencyPoint{}, err
+	}
+
+	// Fourier coefficients at the driving frequency over whole cycles
+	reference := float64(cfg.InitialBaseFee)
+	var input, output complex128
+	for t := start; t < blocks; t++ {
+		rotation := cmplx.Exp(complex(0, -omega*float64(t-Onset)))
+		input += complex(math.Log(response.Equilibrium[t]/reference), 0) * rotation
+		output += complex(math.Log(math.Max(response.BaseFees[t], 1)/reference), 0) * rotation
+	}
+
+	transfer := output / input
+	point := FrequencyPoint{
+		Period: float64(period),
+		Gain:   cmplx.Abs(transfer),
+		Phase:  cmplx.Phase(transfer) * 180 / math.Pi,
+	}
+	point.GainDB = 20 * math.Log10(point.Gain)
+	if point.Phase < 0 {
+		point.LagBlocks = -point.Phase / 360 * float64(period)
+	}
+	return point, nil
+}
+
+// SimulateFrequencies measures one adjuster at every period
+func SimulateFrequencies(adjusterType simulator.AdjusterType, cfg config.Config, periods []int) (FrequencyResponse, error) {
+	response := FrequencyResponse{AdjusterType: string(adjusterType)}
+	for _, period := range periods {
+		point, err := SimulateSinusoid(adjusterType, cfg, period)
+		if err != nil {
+			return FrequencyResponse{}, err
+		}
+		response.Points = append(response.Points, point)
+	}
+	return response, nil
+}
+
+// RunFrequencies measures every adjuster type at the configured periods
+func RunFrequencies(cfg config.Config, adjusterTypes []simulator.AdjusterType) ([]FrequencyResponse, error) {
+	periods, err := ParsePeriods(cfg.Simulation.Response.Periods)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []FrequencyResponse
+	for _, adjusterType := range adjusterTypes {
+		response, err := SimulateFrequencies(adjusterType, cfg, periods)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// PrintFrequencyResponses prints gain and phase per period for every adjuster
+func PrintFrequencyResponses(w io.Writer, responses []FrequencyResponse, cfg config.Config) {
+	r := cfg.Simulation.Response
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "FREQUENCY RESPONSE (±%.0f%% sinusoidal demand, elasticity %.2g, %d measured cycles)\n",
+		r.Amplitude*100, r.Elasticity, r.Cycles)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	for _, response := range responses {
+		fmt.Fprintf(w, "\n%s\n", response.AdjusterType)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "  Period\tGain\tGain dB\tPhase°\tLag (blocks)")
+		for _, point := range response.Points {
+			fmt.Fprintf(tw, "  %.0f\t%.3f\t%+.1f\t%+.0f\t%.1f\n",
+				point.Period, point.Gain, point.GainDB, point.Phase, point.LagBlocks)
+		}
+		tw.Flush()
+
+		peak := response.Peak()
+		if peak.Gain > 1.05 {
+			fmt.Fprintf(w, "  Amplifies demand cycles: peak gain %.2f (%+.1f dB) at %.0f-block period\n",
+				peak.Gain, peak.GainDB, peak.Period)
+		}
+	}
+
+	fmt.Fprintf(w, "\nGain is fee amplitude over equilibrium fee amplitude: 1 tracks demand exactly,\n")
+	fmt.Fprintf(w, "above 1 amplifies demand cycles, below 1 smooths them.\n")
+}
+
+// FrequencyTable returns the gain and phase of every adjuster at every demand period
+func FrequencyTable(responses []FrequencyResponse) *output.Table {
+	table := output.NewTable("frequency", "Frequency Response", "adjuster_type", "period", "gain", "gain_db",
+		"phase_degrees", "lag_blocks")
+	for _, response := range responses {
+		for _, point := range response.Points {
+			table.Append(response.AdjusterType, point.Period, point.Gain, point.GainDB, point.Phase, point.LagBlocks)
+		}
+	}
+	return table
+}
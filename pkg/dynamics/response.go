@@ -0,0 +1,316 @@
+package dynamics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// Onset is the block at which every test input is applied. The blocks before it let
+// windowed adjusters fill their history at equilibrium.
+const Onset = 20
+
+// Input is a canonical test signal applied to the demand curve
+type Input string
+
+const (
+	// InputStep multiplies demand at every fee by the step size from the onset on
+	InputStep Input = "step"
+	// InputImpulse fills one block to burst capacity at the onset, then demand returns to normal
+	InputImpulse Input = "impulse"
+	// InputRamp raises the demand multiple linearly from 1 at the onset to the step size at the last block
+	InputRamp Input = "ramp"
+)
+
+// Inputs lists the test inputs in report order
+var Inputs = []Input{InputStep, InputImpulse, InputRamp}
+
+// Metrics are classical control metrics of the base fee's response to an input.
+// Errors are measured against the equilibrium fee of the current demand, and times are in
+// blocks after the onset. NaN means the response never met the condition within the run.
+type Metrics struct {
+	RiseTime         float64 // Step only: blocks to go from 10% to 90% of the way to the new equilibrium
+	Overshoot        float64 // Step only: peak excursion past the new equilibrium, in % of the step in fee
+	PeakDeviation    float64 // Largest deviation from equilibrium, in %
+	SettlingTime     float64 // Blocks until the fee stays within the tolerance band around equilibrium
+	SteadyStateError float64 // Mean signed deviation over the last 10% of blocks, in %
+	DecayRatio       float64 // Ratio of successive same-direction error peaks; 0 if the fee does not oscillate
+}
+
+// Response is the closed-loop trajectory of one adjuster driven by one input
+type Response struct {
+	AdjusterType string
+	Input        Input
+	BaseFees     []float64 // Base fee each block was priced at, in wei
+	Utilization  []float64 // Gas used relative to the target block size
+	Equilibrium  []float64 // Equilibrium fee for each block's demand, in wei
+	Metrics      Metrics
+}
+
+// Simulate drives a fresh adjuster with the input in closed loop: each block includes the gas
+// demanded at the current base fee, capped at the maximum block size. Demand starts at
+// equilibrium, with the configured initial base fee as the reference fee.
+func Simulate(adjusterType simulator.AdjusterType, cfg config.Config, input Input) (Response, error) {
+	r := cfg.Simulation.Response
+	scale := func(t int) float64 {
+		return demandScale(input, t, r.Blocks, r.StepSize)
+	}
+	full := func(t int) bool {
+		return input == InputImpulse && t == Onset
+	}
+
+	response, err := closedLoop(adjusterType, cfg, r.Blocks, scale, full)
+	if err != nil {
+		return Response{}, err
+	}
+	response.Input = input
+	response.Metrics = Measure(response, r.Tolerance)
+	return response, nil
+}
+
+// closedLoop runs a fresh adjuster for the given number of blocks. Demand at block t is
+// scale(t) times the reference demand curve, and blocks where full(t) is true are filled to
+// capacity regardless of the fee.
+func closedLoop(adjusterType simulator.AdjusterType, cfg config.Config, blocks int, scale func(int) float64, full func(int) bool) (Response, error) {
+	adjuster, err := simulator.NewAdjusterFactory().CreateAdjusterWithConfigs(adjusterType, &cfg)
+	if err != nil {
+		return Response{}, fmt.Errorf("failed to create adjuster: %w", err)
+	}
+
+	demand := Demand{
+		TargetBlockSize: cfg.TargetBlockSize,
+		ReferenceFee:    float64(cfg.InitialBaseFee),
+		Scale:           1,
+		Elasticity:      cfg.Simulation.Response.Elasticity,
+	}
+	maxGas := float64(adjuster.GetMaxBlockSize())
+
+	response := Response{
+		AdjusterType: string(adjusterType),
+		BaseFees:     make([]float64, blocks),
+		Utilization:  make([]float64, blocks),
+		Equilibrium:  make([]float64, blocks),
+	}
+	for t := 0; t < blocks; t++ {
+		demand.Scale = scale(t)
+		fee := float64(adjuster.GetCurrentState().BaseFee)
+
+		gas := math.Min(demand.GasAt(fee), maxGas)
+		if full != nil && full(t) {
+			gas = maxGas
+		}
+
+		response.BaseFees[t] = fee
+		response.Utilization[t] = gas / float64(cfg.TargetBlockSize)
+		response.Equilibrium[t] = demand.EquilibriumFee()
+		adjuster.ProcessBlock(uint64(gas))
+	}
+	return response, nil
+}
+
+// demandScale returns the demand multiple of the input at block t
+func demandScale(input Input, t, blocks int, stepSize float64) float64 {
+	if t < Onset {
+		return 1
+	}
+	switch input {
+	case InputStep:
+		return stepSize
+	case InputRamp:
+		return 1 + (stepSize-1)*float64(t-Onset)/float64(blocks-1-Onset)
+	default:
+		return 1
+	}
+}
+
+// Measure computes the control metrics of a response. Deviations within the tolerance,
+// a fraction of the equilibrium fee, count as settled and are ignored when finding peaks.
+func Measure(r Response, tolerance float64) Metrics {
+	blocks := len(r.BaseFees)
+	errors := make([]float64, blocks-Onset)
+	for i := range errors {
+		t := Onset + i
+		errors[i] = (r.BaseFees[t] - r.Equilibrium[t]) / r.Equilibrium[t]
+	}
+
+	m := Metrics{
+		RiseTime:  math.NaN(),
+		Overshoot: math.NaN(),
+	}
+
+	for _, e := range errors {
+		m.PeakDeviation = math.Max(m.PeakDeviation, math.Abs(e)*100)
+	}
+
+	m.SettlingTime = 0
+	for i := len(errors) - 1; i >= 0; i-- {
+		if math.Abs(errors[i]) > tolerance {
+			m.SettlingTime = float64(i + 1)
+			if i == len(errors)-1 {
+				m.SettlingTime = math.NaN()
+			}
+			break
+		}
+	}
+
+	tail := len(errors) / 10
+	if tail < 1 {
+		tail = 1
+	}
+	var sum float64
+	for _, e := range errors[len(errors)-tail:] {
+		sum += e
+	}
+	m.SteadyStateError = sum / float64(tail) * 100
+
+	// The first error segment of a step is the approach to the new level, not an oscillation
+	skip := 0
+	if r.Input == InputStep {
+		m.RiseTime, m.Overshoot = stepMetrics(r.BaseFees[Onset:], r.Equilibrium[blocks-1])
+		skip = 1
+	}
+	m.DecayRatio = decayRatio(errors, tolerance, skip)
+	return m
+}
+
+// stepMetrics returns the 10-90% rise time and the overshoot of a step response
+func stepMetrics(fees []float64, final float64) (float64, float64) {
+	initial := fees[0]
+	change := final - initial
+	if change == 0 {
+		return math.NaN(), math.NaN()
+	}
+
+	t10, t90 := -1, -1
+	var overshoot float64
+	for t, fee := range fees {
+		progress := (fee - initial) / change
+		if t10 < 0 && progress >= 0.1 {
+			t10 = t
+		}
+		if t90 < 0 && progress >= 0.9 {
+			t90 = t
+		}
+		overshoot = math.Max(overshoot, (progress-1)*100)
+	}
+
+	rise := math.NaN()
+	if t10 >= 0 && t90 >= 0 {
+		rise = float64(t90 - t10)
+	}
+	return rise, overshoot
+}
+
+// decayRatio splits the errors into runs of the same sign outside the tolerance band and
+// returns the ratio between the peaks of the first two same-direction runs after skipping some
+func decayRatio(errors []float64, tolerance float64, skip int) float64 {
+	var peaks []float64
+	sign := 0
+	for _, e := range errors {
+		if math.Abs(e) <= tolerance {
+			continue
+		}
+		s := 1
+		if e < 0 {
+			s = -1
+		}
+		if s != sign {
+			peaks = append(peaks, 0)
+			sign = s
+		}
+		peaks[len(peaks)-1] = math.Max(peaks[len(peaks)-1], math.Abs(e))
+	}
+
+	if len(peaks) < skip+3 {
+		return 0
+	}
+	return peaks[skip+2] / peaks[skip]
+}
+
+// Run simulates every input for every adjuster type, in Inputs order per adjuster
+func Run(cfg config.Config, adjusterTypes []simulator.AdjusterType) ([]Response, error) {
+	var responses []Response
+	for _, adjusterType := range adjusterTypes {
+		for _, input := range Inputs {
+			response, err := Simulate(adjusterType, cfg, input)
+			if err != nil {
+				return nil, err
+			}
+			responses = append(responses, response)
+		}
+	}
+	return responses, nil
+}
+
+// PrintResponses prints the control metrics of every response
+func PrintResponses(w io.Writer, responses []Response, cfg config.Config) {
+	r := cfg.Simulation.Response
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "RESPONSE ANALYSIS (%d blocks, input at block %d, %.2gx demand step, elasticity %.2g)\n",
+		r.Blocks, Onset, r.StepSize, r.Elasticity)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Algorithm\tInput\tRise\tOvershoot %\tPeak Dev %\tSettling\tSS Error %\tDecay Ratio")
+	for _, response := range responses {
+		m := response.Metrics
+		rise := "-"
+		if response.Input == InputStep {
+			rise = formatBlocks(m.RiseTime)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.1f\t%s\t%+.2f\t%.2f\n",
+			response.AdjusterType, response.Input, rise, formatPercent(m.Overshoot),
+			m.PeakDeviation, formatBlocks(m.SettlingTime), m.SteadyStateError, m.DecayRatio)
+	}
+	tw.Flush()
+
+	fmt.Fprintf(w, "\nTimes are blocks after the input; errors are relative to the equilibrium fee.\n")
+	fmt.Fprintf(w, "Settled means within %.1f%% of equilibrium for the rest of the run.\n", r.Tolerance*100)
+}
+
+// ResponsesTable returns the control metrics of every response. Times are in blocks after the
+// input and are empty when the fee never settles; rise time and overshoot are step-only.
+func ResponsesTable(responses []Response) *output.Table {
+	table := output.NewTable("responses", "Response Analysis", "adjuster_type", "input", "rise_time",
+		"overshoot_percent", "peak_deviation_percent", "settling_time", "steady_state_error_percent", "decay_ratio")
+	for _, response := range responses {
+		m := response.Metrics
+		var rise, overshoot interface{}
+		if response.Input == InputStep {
+			rise, overshoot = optional(m.RiseTime), optional(m.Overshoot)
+		}
+		table.Append(response.AdjusterType, string(response.Input), rise, overshoot,
+			m.PeakDeviation, optional(m.SettlingTime), m.SteadyStateError, m.DecayRatio)
+	}
+	return table
+}
+
+// optional returns the value, or nil for NaN so the table cell is left empty
+func optional(value float64) interface{} {
+	if math.IsNaN(value) {
+		return nil
+	}
+	return value
+}
+
+// formatBlocks formats a block count, or "never" for NaN
+func formatBlocks(value float64) string {
+	if math.IsNaN(value) {
+		return "never"
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+// formatPercent formats a percentage, or "-" for NaN
+func formatPercent(value float64) string {
+	if math.IsNaN(value) {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", value)
+}
@@ -0,0 +1,415 @@
+// Package experiment runs every combination of workloads, adjuster variants, parameter values
+// and seeds declared in an experiment file
+package experiment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/stats"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Experiment declares the cartesian product an experiment runs: every workload, with every
+// variant at every parameter point, for every seed. It is read from a JSON or TOML file.
+type Experiment struct {
+	Name      string    `json:"name"`              // Defaults to the file name
+	Scenarios Workloads `json:"scenarios"`         // Workloads every run is evaluated on
+	Variants  []string  `json:"variants"`          // name:adjuster-type[:param=value,...] (see config.ParseVariant) or a bare adjuster type
+	Params    []string  `json:"params,omitempty"`  // Parameter grid applied to every variant, e.g. aimd-gamma=0.05:0.2:0.05 (see sweep.ParseParam)
+	Seeds     []int64   `json:"seeds,omitempty"`   // Randomizer seeds (empty = the configured -rng-seed)
+	Metrics   []string  `json:"metrics,omitempty"` // Metrics collected (empty = every metric)
+}
+
+// Workloads lists the scenario sets of an experiment
+type Workloads struct {
+	BuiltIn  []string `json:"builtin,omitempty"`  // Built-in scenario names, or "all"
+	Files    []string `json:"files,omitempty"`    // Scenario files, e.g. written by the search command
+	DataSets []string `json:"datasets,omitempty"` // Blockchain dataset files
+}
+
+// LoadFromFile reads an experiment from a JSON or TOML file. Tables and keys are those of the
+// Experiment fields, e.g. [scenarios] builtin = ["all"].
+func LoadFromFile(filename string) (*Experiment, error) {
+	tree, err := config.DecodeFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode the tree like a JSON document so unknown keys are reported
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var e Experiment
+	if err := decoder.Decode(&e); err != nil {
+		return nil, fmt.Errorf("invalid experiment %s: %w", filename, err)
+	}
+
+	if e.Name == "" {
+		e.Name = strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	}
+	return &e, nil
+}
+
+// Runner executes an experiment on top of a base configuration, which sets every parameter the
+// experiment does not vary
+type Runner struct {
+	experiment Experiment
+	runs       []compare.Run // One per variant and parameter point
+	cases      []sweep.Case
+	seeds      []int64
+	metrics    []string
+	workers    int
+	logger     *slog.Logger
+}
+
+// job is one case run with one seed; every run of the experiment is evaluated on it
+type job struct {
+	c    sweep.Case
+	seed int64
+}
+
+// NewRunner checks the experiment and prepares its runs and cases. Workers is the number of jobs
+// run in parallel (0 = one per CPU).
+func NewRunner(cfg config.Config, e Experiment, workers int) (*Runner, error) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	r := &Runner{experiment: e, seeds: e.Seeds, metrics: e.Metrics, workers: workers}
+	if len(r.seeds) == 0 {
+		r.seeds = []int64{cfg.Simulation.Randomizer.Seed}
+	}
+
+	if len(r.metrics) == 0 {
+		r.metrics = sweep.MetricNames()
+	}
+	valid := make(map[string]bool)
+	for _, name := range sweep.MetricNames() {
+		valid[name] = true
+	}
+	for _, name := range r.metrics {
+		if !valid[name] {
+			return nil, fmt.Errorf("unknown metric: %s", name)
+		}
+	}
+
+	var err error
+	if r.runs, err = runs(cfg, e); err != nil {
+		return nil, err
+	}
+	if r.cases, err = cases(e.Scenarios); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetLogger sets the logger the simulations report their diagnostics to; nil discards
+func (r *Runner) SetLogger(logger *slog.Logger) {
+	r.logger = logger
+}
+
+// runs returns one run per variant and parameter point, labelled with the variant name and,
+// when parameters are varied, the point's flags
+func runs(cfg config.Config, e Experiment) ([]compare.Run, error) {
+	if len(e.Variants) == 0 {
+		return nil, fmt.Errorf("no variants selected")
+	}
+	texts := make([]string, len(e.Variants))
+	for i, text := range e.Variants {
+		// A bare adjuster type is a variant named after it
+		if !strings.Contains(text, ":") {
+			text = text + ":" + text
+		}
+		texts[i] = text
+	}
+	variants, err := config.ParseVariants(texts)
+	if err != nil {
+		return nil, err
+	}
+	params, err := sweep.ParseParams(e.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []compare.Run
+	for _, variant := range variants {
+		if _, err := simulator.ParseAdjusterType(variant.AdjusterType); err != nil {
+			return nil, fmt.Errorf("variant %s: %w", variant.Name, err)
+		}
+		for _, point := range sweep.Grid(params) {
+			runCfg, err := variant.Apply(cfg)
+			if err != nil {
+				return nil, err
+			}
+			label := variant.Name
+			if len(params) > 0 {
+				label += " " + sweep.Flags(params, point)
+			}
+			if runCfg, err = sweep.Apply(runCfg, params, point); err != nil {
+				return nil, fmt.Errorf("%s: %w", label, err)
+			}
+			runCfg.Simulation.Label = label
+			result = append(result, compare.Run{Label: label, Config: runCfg})
+		}
+	}
+	return result, nil
+}
+
+// cases returns the built-in scenarios, then the scenario files, then the datasets
+func cases(w Workloads) ([]sweep.Case, error) {
+	var result []sweep.Case
+	for _, name := range w.BuiltIn {
+		switch name {
+		case "all":
+			for _, builtIn := range []string{"full", "empty", "stable", "mixed"} {
+				result = append(result, sweep.Case{Name: builtIn, Scenario: builtIn})
+			}
+		case "full", "empty", "stable", "mixed":
+			result = append(result, sweep.Case{Name: name, Scenario: name})
+		default:
+			return nil, fmt.Errorf("unknown scenario: %s, must be one of: %v", name, scenarios.GetValidScenarioNames())
+		}
+	}
+
+	for _, filename := range w.Files {
+		// Scenario files are loaded for each seed; check them once up front
+		if _, err := scenarios.LoadFromFile(filename); err != nil {
+			return nil, err
+		}
+		result = append(result, sweep.Case{Name: filepath.Clean(filename), ScenarioFile: filename})
+	}
+
+	for _, filename := range w.DataSets {
+		dataset, err := blockchain.LoadDataSetFromFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dataset %s: %w", filename, err)
+		}
+		if err := blockchain.ValidateDataSet(dataset); err != nil {
+			return nil, fmt.Errorf("invalid dataset %s: %w", filename, err)
+		}
+		result = append(result, sweep.Case{Name: filepath.Clean(filename), DataSet: dataset})
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("no scenarios or datasets selected")
+	}
+	// A case selected twice would run twice and merge into one summary
+	seen := make(map[string]bool)
+	for _, c := range result {
+		if seen[c.Name] {
+			return nil, fmt.Errorf("duplicate scenario: %s", c.Name)
+		}
+		seen[c.Name] = true
+	}
+	return result, nil
+}
+
+// Labels returns the label of every run, one per variant and parameter point
+func (r *Runner) Labels() []string {
+	return compare.Labels(r.runs)
+}
+
+// jobs returns every case with every seed. Datasets replay recorded demand, which no seed
+// changes, so they run once with the first seed.
+func (r *Runner) jobs() []job {
+	var jobs []job
+	for _, c := range r.cases {
+		seeds := r.seeds
+		if c.IsDataSet() {
+			seeds = seeds[:1]
+		}
+		for _, seed := range seeds {
+			jobs = append(jobs, job{c: c, seed: seed})
+		}
+	}
+	return jobs
+}
+
+// Jobs returns the number of scenario and seed combinations, each simulated with every run
+func (r *Runner) Jobs() int {
+	return len(r.jobs())
+}
+
+// Results holds the outcome of every run of an experiment
+type Results struct {
+	Name    string
+	Labels  []string
+	Metrics []string     // Metrics collected, in output order
+	Cases   []CaseResult // In case order, then seed order
+}
+
+// CaseResult holds every run's results on one case with one seed
+type CaseResult struct {
+	Case     string
+	Seed     int64
+	Scenario *compare.ScenarioComparison // Set for built-in scenarios and scenario files
+	DataSet  *compare.DataSetComparison  // Set for datasets
+}
+
+// Run executes every job in parallel and calls progress, if not nil, after each one
+func (r *Runner) Run(ctx context.Context, progress func(completed, total int)) (*Results, error) {
+	jobs := r.jobs()
+	results := make([]CaseResult, len(jobs))
+	errs := make([]error, len(jobs))
+
+	queue := make(chan int, len(jobs))
+	for i := range jobs {
+		queue <- i
+	}
+	close(queue)
+
+	var mu sync.Mutex
+	completed := 0
+	var wg sync.WaitGroup
+	for w := 0; w < r.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if ctx.Err() != nil {
+					continue
+				}
+				results[i], errs[i] = r.runJob(jobs[i])
+
+				if progress != nil {
+					mu.Lock()
+					completed++
+					progress(completed, len(jobs))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("%s with seed %d: %w", jobs[i].c.Name, jobs[i].seed, err)
+		}
+	}
+	return &Results{Name: r.experiment.Name, Labels: r.Labels(), Metrics: r.metrics, Cases: results}, nil
+}
+
+// runJob evaluates every run on one case, with identical demand for all of them
+func (r *Runner) runJob(j job) (CaseResult, error) {
+	runs := make([]compare.Run, len(r.runs))
+	for i, run := range r.runs {
+		run.Config.Simulation.Randomizer.Seed = j.seed
+		runs[i] = run
+	}
+	result := CaseResult{Case: j.c.Name, Seed: j.seed}
+
+	if j.c.IsDataSet() {
+		comparison, err := compare.CompareDataSet(runs, j.c.DataSet, r.logger)
+		if err != nil {
+			return CaseResult{}, err
+		}
+		result.DataSet = &comparison
+		return result, nil
+	}
+
+	// The scenario is generated once from the first run's configuration, as in the compare command
+	cfg := runs[0].Config
+	cfg.Simulation.Scenario = j.c.Scenario
+	cfg.Simulation.ScenarioFile = j.c.ScenarioFile
+	selected, err := scenarios.NewGenerator(cfg.Simulation).Select(cfg)
+	if err != nil {
+		return CaseResult{}, err
+	}
+	comparison, err := compare.CompareScenario(runs, selected[0], r.logger)
+	if err != nil {
+		return CaseResult{}, err
+	}
+	result.Scenario = &comparison
+	return result, nil
+}
+
+// Rows returns the metrics of every run on the case, limited to the collected metrics
+func (c CaseResult) Rows(metrics []string) []compare.Row {
+	var rows []compare.Row
+	if c.DataSet != nil {
+		rows = compare.DataSetRows(*c.DataSet, c.Case)
+	} else {
+		rows = compare.ScenarioRows(*c.Scenario)
+		for i := range rows {
+			rows[i].Case = c.Case
+		}
+	}
+
+	for i, row := range rows {
+		collected := make(map[string]float64)
+		for _, name := range metrics {
+			if value, ok := row.Metrics[name]; ok {
+				collected[name] = value
+			}
+		}
+		rows[i].Metrics = collected
+	}
+	return rows
+}
+
+// Summary aggregates one run's metrics on one case across seeds
+type Summary struct {
+	Label        string
+	AdjusterType string
+	Case         string
+	Runs         int
+	Metrics      map[string]stats.Summary
+}
+
+// Summaries returns one summary per case and run, in case then run order
+func (r *Results) Summaries() []Summary {
+	var order []string
+	grouped := make(map[string][][]compare.Row) // The rows of every seed, by case
+	for _, c := range r.Cases {
+		if _, seen := grouped[c.Case]; !seen {
+			order = append(order, c.Case)
+		}
+		grouped[c.Case] = append(grouped[c.Case], c.Rows(r.Metrics))
+	}
+
+	var summaries []Summary
+	for _, name := range order {
+		seeds := grouped[name]
+		for i, row := range seeds[0] {
+			summary := Summary{
+				Label:        row.Label,
+				AdjusterType: row.AdjusterType,
+				Case:         name,
+				Runs:         len(seeds),
+				Metrics:      make(map[string]stats.Summary),
+			}
+			for _, metric := range r.Metrics {
+				var values []float64
+				for _, rows := range seeds {
+					if value, ok := rows[i].Metrics[metric]; ok {
+						values = append(values, value)
+					}
+				}
+				if len(values) > 0 {
+					summary.Metrics[metric] = stats.Summarize(values)
+				}
+			}
+			summaries = append(summaries, summary)
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,113 @@
+package experiment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+const testExperiment = `name = "nightly"
+variants = ["eip1559", "fast:aimd:aimd-gamma=0.1"]
+params = ["window-size=5,10"]
+seeds = [1, 2]
+metrics = ["base_fee_volatility", "mean_abs_fee_change"]
+
+[scenarios]
+builtin = ["stable", "full"]
+`
+
+func loadTestExperiment(t *testing.T, text string) *Experiment {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "nightly.toml")
+	if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	return e
+}
+
+func TestRunExperiment(t *testing.T) {
+	e := loadTestExperiment(t, testExperiment)
+	cfg := config.Default()
+	cfg.Simulation.Randomizer.GaussianNoise = 0.1
+
+	runner, err := NewRunner(cfg, *e, 2)
+	if err != nil {
+		t.Fatalf("NewRunner failed: %v", err)
+	}
+	wantLabels := []string{"eip1559 -window-size=5", "eip1559 -window-size=10", "fast -window-size=5", "fast -window-size=10"}
+	if got := runner.Labels(); strings.Join(got, "|") != strings.Join(wantLabels, "|") {
+		t.Errorf("expected labels %v, got %v", wantLabels, got)
+	}
+	if runner.Jobs() != 4 {
+		t.Errorf("expected 2 scenarios x 2 seeds, got %d jobs", runner.Jobs())
+	}
+
+	calls := 0
+	results, err := runner.Run(context.Background(), func(completed, total int) { calls++ })
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if calls != 4 || len(results.Cases) != 4 || results.Cases[1].Case != "stable" || results.Cases[1].Seed != 2 {
+		t.Fatalf("unexpected results: %d progress calls, %d cases", calls, len(results.Cases))
+	}
+
+	// Different seeds draw different noise, and every run sees the same demand
+	first, second := results.Cases[0].Scenario, results.Cases[1].Scenario
+	if first.Results[0].AvgGasUsed == second.Results[0].AvgGasUsed || first.Results[0].AvgGasUsed != first.Results[2].AvgGasUsed {
+		t.Error("expected per-seed demand shared by every run")
+	}
+
+	summaries := results.Summaries()
+	if len(summaries) != 8 || summaries[0].Runs != 2 || len(summaries[0].Metrics) != 2 {
+		t.Fatalf("unexpected summaries: %+v", summaries)
+	}
+
+	dir := t.TempDir()
+	files, err := SaveDir(dir, results, false, nil)
+	if err != nil {
+		t.Fatalf("SaveDir failed: %v", err)
+	}
+	// runs.csv, summary.csv, summary.md and 16 traces
+	if len(files) != 19 {
+		t.Errorf("expected 19 data files, got %d", len(files))
+	}
+	for _, name := range []string{"runs.csv", "summary.md", "charts/01_stable.html", "traces/02_full_04_fast_-window-size_10_seed2.csv"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+}
+
+func TestInvalidExperiments(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":      "variants = [\"aimd\"]\nseed = 1\n[scenarios]\nbuiltin = [\"full\"]\n",
+		"no variants":      "[scenarios]\nbuiltin = [\"full\"]\n",
+		"unknown scenario": "variants = [\"aimd\"]\n[scenarios]\nbuiltin = [\"bogus\"]\n",
+		"duplicate case":   "variants = [\"aimd\"]\n[scenarios]\nbuiltin = [\"all\", \"full\"]\n",
+		"unknown metric":   "variants = [\"aimd\"]\nmetrics = [\"bogus\"]\n[scenarios]\nbuiltin = [\"full\"]\n",
+		"invalid point":    "variants = [\"aimd\"]\nparams = [\"aimd-beta=0.5,2\"]\n[scenarios]\nbuiltin = [\"full\"]\n",
+	}
+	for name, text := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "bad.toml")
+			if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+				t.Fatal(err)
+			}
+			e, err := LoadFromFile(path)
+			if err == nil {
+				_, err = NewRunner(config.Default(), *e, 1)
+			}
+			if err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
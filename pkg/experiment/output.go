@@ -0,0 +1,168 @@
+package experiment
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/visualization"
+)
+
+// RunsTable returns one row per run, case and seed: label, adjuster type, case, seed, then every
+// collected metric. Metrics a case does not report are empty.
+func RunsTable(results *Results) *output.Table {
+	table := output.NewTable("runs", "Runs", append([]string{"label", "adjuster_type", "case", "seed"}, results.Metrics...)...)
+	for _, c := range results.Cases {
+		for _, row := range c.Rows(results.Metrics) {
+			values := []interface{}{row.Label, row.AdjusterType, row.Case, c.Seed}
+			for _, name := range results.Metrics {
+				if value, ok := row.Metrics[name]; ok {
+					values = append(values, value)
+				} else {
+					values = append(values, nil)
+				}
+			}
+			table.Append(values...)
+		}
+	}
+	return table
+}
+
+// SummaryTable returns one row per run and case with the mean and standard deviation of every
+// collected metric across seeds
+func SummaryTable(results *Results) *output.Table {
+	columns := []string{"label", "adjuster_type", "case", "runs"}
+	for _, name := range results.Metrics {
+		columns = append(columns, name+"_mean", name+"_std")
+	}
+	table := output.NewTable("summary", "Summary: "+results.Name, columns...)
+	for _, summary := range results.Summaries() {
+		values := []interface{}{summary.Label, summary.AdjusterType, summary.Case, summary.Runs}
+		for _, name := range results.Metrics {
+			if metric, ok := summary.Metrics[name]; ok {
+				values = append(values, metric.Mean, metric.StdDev)
+			} else {
+				values = append(values, nil, nil)
+			}
+		}
+		table.Append(values...)
+	}
+	return table
+}
+
+// SaveDir writes the results directory:
+//
+//	runs.csv                      every run on every case and seed (RunsTable)
+//	summary.csv, summary.md       metrics aggregated across seeds (SummaryTable)
+//	traces/<i>_<case>_<j>_<label>_seed<n>.csv  base fee after each block of every run
+//	charts/<i>_<case>.html        base fee of every run with the first seed
+//
+// File names start with the case's and run's position, numbered from 01, so cases and labels that
+// differ only in their directory or in characters a file name cannot hold never collide. It
+// returns the data files written, without the charts, whose chart IDs change on every render.
+// Saved charts are reported to logger; nil discards.
+func SaveDir(dir string, results *Results, useLogScale bool, logger *slog.Logger) ([]string, error) {
+	for _, sub := range []string{"traces", "charts"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create results directory: %w", err)
+		}
+	}
+
+	var files []string
+	save := func(name string, format output.Format, tables ...*output.Table) error {
+		filename := filepath.Join(dir, name)
+		file, err := os.Create(filename)
+		if err != nil {
+			return fmt.Errorf("failed to create file: %w", err)
+		}
+		defer file.Close()
+		if err := output.Write(file, format, tables...); err != nil {
+			return fmt.Errorf("failed to write %s: %w", filename, err)
+		}
+		files = append(files, filename)
+		return nil
+	}
+
+	if err := save("runs.csv", output.CSV, RunsTable(results)); err != nil {
+		return nil, err
+	}
+	summary := SummaryTable(results)
+	if err := save("summary.csv", output.CSV, summary); err != nil {
+		return nil, err
+	}
+	if err := save("summary.md", output.Markdown, summary); err != nil {
+		return nil, err
+	}
+
+	// Cases come once per seed; number them in the order they first appear
+	indexes := make(map[string]string)
+	charted := make(map[string]bool)
+	generator := visualization.NewGenerator(logger)
+	for _, c := range results.Cases {
+		index, ok := indexes[c.Case]
+		if !ok {
+			index = fmt.Sprintf("%02d", len(indexes)+1)
+			indexes[c.Case] = index
+		}
+		for i, label := range results.Labels {
+			run := fmt.Sprintf("%02d", i+1)
+			trace := fileName(index, caseName(c.Case), run, label)
+			var table *output.Table
+			if c.DataSet != nil {
+				table = blockchain.BlocksTable(c.DataSet.SimResults[i].ComparisonData)
+			} else {
+				trace = fileName(index, caseName(c.Case), run, label, fmt.Sprintf("seed%d", c.Seed))
+				table = traceTable(c.Scenario.Scenario.Blocks, c.Scenario.BaseFees[i])
+			}
+			if err := save(filepath.Join("traces", trace+".csv"), output.CSV, table); err != nil {
+				return nil, err
+			}
+		}
+
+		if charted[c.Case] {
+			continue
+		}
+		charted[c.Case] = true
+		chart := filepath.Join(dir, "charts", fileName(index, caseName(c.Case))+".html")
+		var err error
+		if c.DataSet != nil {
+			err = generator.GenerateDataSetComparisonChart(*c.DataSet, useLogScale, chart)
+		} else {
+			err = generator.GenerateComparisonChart(*c.Scenario, useLogScale, chart)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to chart %s: %w", c.Case, err)
+		}
+	}
+	return files, nil
+}
+
+// traceTable returns the gas used and base fee in wei after each block of a scenario run
+func traceTable(blocks, baseFees []uint64) *output.Table {
+	table := output.NewTable("trace", "Trace", "block", "gas_used", "base_fee")
+	for i, gasUsed := range blocks {
+		table.Append(i+1, gasUsed, baseFees[i])
+	}
+	return table
+}
+
+// caseName returns the name of a case without the directory and extension of its file
+func caseName(name string) string {
+	return strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+}
+
+// fileName joins the parts into a file name, replacing characters other than letters, digits,
+// '.', '-' and '_'
+func fileName(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "_"))
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+}
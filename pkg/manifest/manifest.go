@@ -0,0 +1,195 @@
+// Package manifest records what is needed to reproduce a run and checks that a rerun reproduces it
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/version"
+)
+
+// Results is the path recorded for results printed to stdout rather than saved to a file
+const Results = "(stdout)"
+
+// Manifest describes one run of a command: everything needed to run it again, and hashes of
+// what it read and wrote
+type Manifest struct {
+	Command   string                 `json:"command"`
+	Flags     map[string]string      `json:"flags,omitempty"` // The command's own flags, e.g. report-output
+	Args      []string               `json:"args,omitempty"`  // Positional arguments
+	Config    map[string]interface{} `json:"config"`          // Every shared setting by flag name, as dumped by config.DumpConfig
+	Seed      int64                  `json:"seed"`            // Randomizer seed; Monte Carlo runs use the seeds that follow it
+	Version   string                 `json:"version"`         // Build version, see version.Version
+	GoVersion string                 `json:"go_version"`
+	CreatedAt time.Time              `json:"created_at"`
+	Inputs    []File                 `json:"inputs,omitempty"` // Scenario and dataset files
+	Outputs   []File                 `json:"outputs"`          // Results and data files written by the command
+}
+
+// File is the SHA-256 hash of a file read or written by a run
+type File struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// New returns the manifest of a command about to run with the resolved configuration, hashing its
+// input files. The manifest setting itself is left out so a rerun does not overwrite the manifest.
+func New(command string, flags map[string]string, args []string, cfg config.Config, inputs []string) (*Manifest, error) {
+	var dumped bytes.Buffer
+	if err := config.DumpConfig(&dumped, cfg, "json"); err != nil {
+		return nil, err
+	}
+	settings, err := decode(&dumped)
+	if err != nil {
+		return nil, err
+	}
+	delete(settings, "manifest")
+
+	m := &Manifest{
+		Command:   command,
+		Flags:     flags,
+		Args:      args,
+		Config:    settings,
+		Seed:      cfg.Simulation.Randomizer.Seed,
+		Version:   version.Version(),
+		GoVersion: runtime.Version(),
+		CreatedAt: time.Now().UTC(),
+	}
+	for _, path := range inputs {
+		file, err := HashFile(path)
+		if err != nil {
+			return nil, err
+		}
+		m.Inputs = append(m.Inputs, file)
+	}
+	return m, nil
+}
+
+// HashFile returns the hash of a file
+func HashFile(path string) (File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return File{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return File{}, fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	return File{Path: path, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// Writer passes writes through and hashes them, to record results printed to stdout
+type Writer struct {
+	w    io.Writer
+	hash hash.Hash
+}
+
+// NewWriter returns a writer hashing everything written to w
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w, hash: sha256.New()}
+}
+
+// Write writes p to the underlying writer and adds it to the hash
+func (w *Writer) Write(p []byte) (int, error) {
+	w.hash.Write(p)
+	return w.w.Write(p)
+}
+
+// File returns the hash of everything written so far, recorded under the given path
+func (w *Writer) File(path string) File {
+	return File{Path: path, SHA256: hex.EncodeToString(w.hash.Sum(nil))}
+}
+
+// Verify hashes the files again and describes every one that changed or can no longer be read
+func Verify(files []File) []string {
+	var problems []string
+	for _, file := range files {
+		current, err := HashFile(file.Path)
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if current.SHA256 != file.SHA256 {
+			problems = append(problems, fmt.Sprintf("%s has changed", file.Path))
+		}
+	}
+	return problems
+}
+
+// Diff describes how the files of a rerun differ from the recorded ones
+func Diff(recorded, rerun []File) []string {
+	hashes := make(map[string]string, len(rerun))
+	for _, file := range rerun {
+		hashes[file.Path] = file.SHA256
+	}
+
+	var differences []string
+	seen := make(map[string]bool, len(recorded))
+	for _, file := range recorded {
+		seen[file.Path] = true
+		hash, ok := hashes[file.Path]
+		switch {
+		case !ok:
+			differences = append(differences, fmt.Sprintf("%s was not written", file.Path))
+		case hash != file.SHA256:
+			differences = append(differences, fmt.Sprintf("%s differs", file.Path))
+		}
+	}
+	for _, file := range rerun {
+		if !seen[file.Path] {
+			differences = append(differences, fmt.Sprintf("%s was not in the manifest", file.Path))
+		}
+	}
+	return differences
+}
+
+// SaveToFile writes a manifest as JSON
+func SaveToFile(m *Manifest, filename string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// LoadFromFile reads a manifest written by SaveToFile
+func LoadFromFile(filename string) (*Manifest, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber() // Keeps large seeds and gas values exact for config.FlagArgs
+	if err := decoder.Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", filename, err)
+	}
+	if m.Command == "" || m.Config == nil {
+		return nil, fmt.Errorf("manifest %s has no command or configuration", filename)
+	}
+	return &m, nil
+}
+
+// decode decodes a JSON object, keeping numbers as written
+func decode(r io.Reader) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	var settings map[string]interface{}
+	if err := decoder.Decode(&settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
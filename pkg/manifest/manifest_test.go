@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "scenario.json")
+	if err := os.WriteFile(input, []byte(`{"name": "test"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Simulation.Randomizer.Seed = 1_700_000_000_123_456_789
+	cfg.Simulation.Manifest = filepath.Join(dir, "manifest.json")
+	m, err := New("run", map[string]string{"report-output": "r.html"}, []string{input}, cfg, []string{input})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.Config["manifest"]; ok {
+		t.Error("the manifest setting should not be recorded")
+	}
+
+	var out bytes.Buffer
+	results := NewWriter(&out)
+	results.Write([]byte("results\n"))
+	m.Outputs = append(m.Outputs, results.File(Results))
+
+	if err := SaveToFile(m, cfg.Simulation.Manifest); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadFromFile(cfg.Simulation.Manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Seed != cfg.Simulation.Randomizer.Seed || !reflect.DeepEqual(loaded.Outputs, m.Outputs) ||
+		!reflect.DeepEqual(loaded.Inputs, m.Inputs) || loaded.Flags["report-output"] != "r.html" {
+		t.Errorf("manifest did not round trip: %+v", loaded)
+	}
+	if out.String() != "results\n" {
+		t.Errorf("writer did not pass results through, got %q", out.String())
+	}
+
+	if problems := Verify(loaded.Inputs); len(problems) != 0 {
+		t.Errorf("unchanged inputs reported: %v", problems)
+	}
+	if err := os.WriteFile(input, []byte(`{"name": "changed"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if problems := Verify(loaded.Inputs); len(problems) != 1 {
+		t.Errorf("expected the changed input to be reported, got %v", problems)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	recorded := []File{{Path: Results, SHA256: "a"}, {Path: "sweep.csv", SHA256: "b"}, {Path: "old.csv", SHA256: "c"}}
+	rerun := []File{{Path: Results, SHA256: "a"}, {Path: "sweep.csv", SHA256: "x"}, {Path: "new.csv", SHA256: "d"}}
+	want := []string{"sweep.csv differs", "old.csv was not written", "new.csv was not in the manifest"}
+	if got := Diff(recorded, rerun); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := Diff(recorded, recorded); len(got) != 0 {
+		t.Errorf("expected no differences, got %v", got)
+	}
+}
@@ -0,0 +1,255 @@
+package montecarlo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/stats"
+)
+
+// RunResult holds the analysis results of a single seed
+type RunResult struct {
+	Seed     int64
+	Results  []analysis.Result // One per scenario, in scenario order
+	BaseFees [][]uint64        // Base fee after each block, one series per scenario
+}
+
+// FeeBands holds per-block base fee percentiles across runs, in Gwei
+type FeeBands struct {
+	P5  []float64 `json:"p5"`
+	P25 []float64 `json:"p25"`
+	P50 []float64 `json:"p50"`
+	P75 []float64 `json:"p75"`
+	P95 []float64 `json:"p95"`
+}
+
+// ScenarioSummary aggregates every analysis metric of one scenario across runs
+type ScenarioSummary struct {
+	ScenarioName string                   `json:"scenarioName"`
+	Runs         int                      `json:"runs"`
+	Metrics      map[string]stats.Summary `json:"metrics"`
+	FeeBands     FeeBands                 `json:"feeBands"`
+}
+
+// Report contains the aggregated outcome of a Monte Carlo batch
+type Report struct {
+	AdjusterType string            `json:"adjusterType"`
+	BaseSeed     int64             `json:"baseSeed"`
+	Runs         int               `json:"runs"`
+	Scenarios    []ScenarioSummary `json:"scenarios"`
+	RunResults   []RunResult       `json:"-"`
+}
+
+// Runner executes the same configuration across many randomizer seeds
+type Runner struct {
+	config  config.Config
+	runs    int
+	workers int
+}
+
+// NewRunner creates a new Monte Carlo runner
+func NewRunner(cfg config.Config) *Runner {
+	workers := cfg.Simulation.MonteCarlo.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &Runner{
+		config:  cfg,
+		runs:    cfg.Simulation.MonteCarlo.Runs,
+		workers: workers,
+	}
+}
+
+// Seeds returns the seeds the runner will use
+func (r *Runner) Seeds() []int64 {
+	seeds := make([]int64, r.runs)
+	for i := range seeds {
+		seeds[i] = r.config.Simulation.Randomizer.Seed + int64(i)
+	}
+	return seeds
+}
+
+// Run executes every seed and aggregates the results
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	runResults, err := r.RunSeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Report{
+		AdjusterType: r.config.Simulation.AdjusterType,
+		BaseSeed:     r.config.Simulation.Randomizer.Seed,
+		Runs:         len(runResults),
+		Scenarios:    Aggregate(runResults),
+		RunResults:   runResults,
+	}, nil
+}
+
+// RunSeeds executes every seed in parallel and returns the per-seed results in seed order
+func (r *Runner) RunSeeds(ctx context.Context) ([]RunResult, error) {
+	seeds := r.Seeds()
+	results := make([]RunResult, len(seeds))
+	errs := make([]error, len(seeds))
+
+	jobs := make(chan int, len(seeds))
+	for i := range seeds {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < r.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				select {
+				case <-ctx.Done():
+					errs[i] = ctx.Err()
+					continue
+				default:
+				}
+				results[i], errs[i] = r.runSeed(seeds[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("run with seed %d failed: %w", seeds[i], err)
+		}
+	}
+	return results, nil
+}
+
+// runSeed runs every selected scenario with the given randomizer seed
+func (r *Runner) runSeed(seed int64) (RunResult, error) {
+	cfg := r.config
+	cfg.Simulation.Randomizer.Seed = seed
+
+	// The analyzer panics on an invalid adjuster type; report it as an error instead
+	if _, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType); err != nil {
+		return RunResult{}, err
+	}
+
+	scenarioList, err := scenarios.NewGenerator(cfg.Simulation).Select(cfg)
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	analyzer := analysis.NewAnalyzer(cfg)
+	run := RunResult{Seed: seed}
+	for _, scenario := range scenarioList {
+		result, baseFees := analyzer.RunDetailedAnalysisWithBaseFees(scenario)
+		run.Results = append(run.Results, result)
+		run.BaseFees = append(run.BaseFees, baseFees)
+	}
+	return run, nil
+}
+
+// Aggregate summarizes per-seed results into per-scenario metric distributions
+func Aggregate(runResults []RunResult) []ScenarioSummary {
+	if len(runResults) == 0 {
+		return nil
+	}
+
+	var summaries []ScenarioSummary
+	for s := range runResults[0].Results {
+		summary := ScenarioSummary{
+			ScenarioName: runResults[0].Results[s].ScenarioName,
+			Runs:         len(runResults),
+			Metrics:      make(map[string]stats.Summary),
+		}
+
+		for _, name := range analysis.MetricNames {
+			values := make([]float64, len(runResults))
+			for i, run := range runResults {
+				values[i], _ = run.Results[s].Metric(name)
+			}
+			summary.Metrics[name] = stats.Summarize(values)
+		}
+
+		summary.FeeBands = feeBands(runResults, s)
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+// feeBands computes per-block base fee percentiles for a scenario
+func feeBands(runResults []RunResult, scenario int) FeeBands {
+	blocks := len(runResults[0].BaseFees[scenario])
+	for _, run := range runResults[1:] {
+		if len(run.BaseFees[scenario]) < blocks {
+			blocks = len(run.BaseFees[scenario])
+		}
+	}
+
+	bands := FeeBands{
+		P5:  make([]float64, blocks),
+		P25: make([]float64, blocks),
+		P50: make([]float64, blocks),
+		P75: make([]float64, blocks),
+		P95: make([]float64, blocks),
+	}
+
+	fees := make([]float64, len(runResults))
+	for b := 0; b < blocks; b++ {
+		for i, run := range runResults {
+			fees[i] = float64(run.BaseFees[scenario][b]) / 1e9
+		}
+		summary := stats.Summarize(fees)
+		bands.P5[b] = summary.P5
+		bands.P25[b] = summary.P25
+		bands.P50[b] = summary.P50
+		bands.P75[b] = summary.P75
+		bands.P95[b] = summary.P95
+	}
+	return bands
+}
+
+// PrintReport prints the metric distributions of every scenario
+func PrintReport(w io.Writer, report *Report) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "MONTE CARLO SUMMARY: %s (%d runs, seeds %d-%d)\n",
+		report.AdjusterType, report.Runs, report.BaseSeed, report.BaseSeed+int64(report.Runs)-1)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	for _, summary := range report.Scenarios {
+		fmt.Fprintf(w, "\n%s\n", summary.ScenarioName)
+		fmt.Fprintf(w, strings.Repeat("-", 60)+"\n")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Metric\tMean\tStd Dev\tP5\tP25\tP50\tP75\tP95")
+		for _, name := range analysis.MetricNames {
+			m := summary.Metrics[name]
+			fmt.Fprintf(tw, "%s\t%.6g\t%.6g\t%.6g\t%.6g\t%.6g\t%.6g\t%.6g\n",
+				name, m.Mean, m.StdDev, m.P5, m.P25, m.P50, m.P75, m.P95)
+		}
+		tw.Flush()
+	}
+}
+
+// ReportTable returns the distribution of every metric per scenario, one row per scenario and metric
+func ReportTable(report *Report) *output.Table {
+	table := output.NewTable("montecarlo", "Monte Carlo Summary", "scenario", "metric", "runs",
+		"mean", "std_dev", "min", "p5", "p25", "p50", "p75", "p95", "max")
+	for _, summary := range report.Scenarios {
+		for _, name := range analysis.MetricNames {
+			m := summary.Metrics[name]
+			table.Append(summary.ScenarioName, name, m.Count, m.Mean, m.StdDev, m.Min, m.P5, m.P25, m.P50, m.P75, m.P95, m.Max)
+		}
+	}
+	return table
+}
@@ -0,0 +1,72 @@
+package montecarlo
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func testConfig(workers int) config.Config {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "eip1559"
+	cfg.Simulation.Scenario = "stable"
+	cfg.Simulation.Randomizer.Seed = 11
+	cfg.Simulation.Randomizer.GaussianNoise = 0.1
+	cfg.Simulation.MonteCarlo.Runs = 8
+	cfg.Simulation.MonteCarlo.Workers = workers
+	return cfg
+}
+
+func TestRunIsDeterministicAcrossWorkerCounts(t *testing.T) {
+	serial, err := NewRunner(testConfig(1)).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Serial run failed: %v", err)
+	}
+	parallel, err := NewRunner(testConfig(4)).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Parallel run failed: %v", err)
+	}
+
+	for i, run := range parallel.RunResults {
+		if run.Seed != 11+int64(i) {
+			t.Errorf("Expected run %d to use seed %d, got %d", i, 11+i, run.Seed)
+		}
+	}
+	if !reflect.DeepEqual(serial.Scenarios, parallel.Scenarios) {
+		t.Error("Expected identical summaries regardless of worker count")
+	}
+}
+
+func TestAggregateBands(t *testing.T) {
+	report, err := NewRunner(testConfig(0)).Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if len(report.Scenarios) != 1 {
+		t.Fatalf("Expected 1 scenario summary, got %d", len(report.Scenarios))
+	}
+
+	summary := report.Scenarios[0]
+	if summary.Runs != 8 {
+		t.Errorf("Expected 8 runs, got %d", summary.Runs)
+	}
+	if summary.Metrics["final_base_fee"].StdDev == 0 {
+		t.Error("Expected noisy runs to produce different final base fees")
+	}
+	for b := range summary.FeeBands.P50 {
+		if summary.FeeBands.P5[b] > summary.FeeBands.P50[b] || summary.FeeBands.P50[b] > summary.FeeBands.P95[b] {
+			t.Fatalf("Block %d: bands out of order: %f, %f, %f",
+				b, summary.FeeBands.P5[b], summary.FeeBands.P50[b], summary.FeeBands.P95[b])
+		}
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRunner(testConfig(2)).Run(ctx); err == nil {
+		t.Error("Expected an error from a cancelled context")
+	}
+}
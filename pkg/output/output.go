@@ -0,0 +1,242 @@
+// Package output writes result tables in the formats scripts and notebooks read: JSON, CSV and
+// Markdown, next to the aligned text the commands print by default.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Format is the format results are written in
+type Format string
+
+// Supported formats
+const (
+	Text     Format = "text"
+	JSON     Format = "json"
+	CSV      Format = "csv"
+	Markdown Format = "markdown"
+)
+
+// Table is one named table of results, e.g. the per-block state of every scenario
+type Table struct {
+	Name    string // Key of the table in JSON output, e.g. blocks
+	Title   string // Heading in Markdown and text output
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// NewTable returns an empty table with the given columns
+func NewTable(name, title string, columns ...string) *Table {
+	return &Table{Name: name, Title: title, Columns: columns}
+}
+
+// Append adds a row; values are in column order
+func (t *Table) Append(values ...interface{}) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Write writes the tables in the given format. JSON is one object with an array of records per
+// table name; Markdown writes the tables one after the other. CSV stays one rectangular table:
+// several tables are combined under the union of their columns, each row tagged with its table.
+func Write(w io.Writer, format Format, tables ...*Table) error {
+	switch format {
+	case JSON:
+		return writeJSON(w, tables)
+	case CSV:
+		return writeCSV(w, tables)
+	case Markdown:
+		return writeMarkdown(w, tables)
+	case Text:
+		return writeText(w, tables)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writeJSON writes {"name": [{"column": value, ...}, ...], ...}, keeping tables and columns in order
+func writeJSON(w io.Writer, tables []*Table) error {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n  ")
+		writeJSONValue(&b, table.Name)
+		b.WriteString(": [")
+		for j, row := range table.Rows {
+			if j > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString("\n    {")
+			for k, column := range table.Columns {
+				if k > 0 {
+					b.WriteString(", ")
+				}
+				writeJSONValue(&b, column)
+				b.WriteString(": ")
+				writeJSONValue(&b, cell(row, k))
+			}
+			b.WriteString("}")
+		}
+		if len(table.Rows) > 0 {
+			b.WriteString("\n  ")
+		}
+		b.WriteString("]")
+	}
+	b.WriteString("\n}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeJSONValue writes one value; NaN and infinities, which JSON cannot hold, become null
+func writeJSONValue(b *strings.Builder, value interface{}) {
+	if f, ok := value.(float64); ok && (math.IsNaN(f) || math.IsInf(f, 0)) {
+		value = nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		data, _ = json.Marshal(fmt.Sprint(value))
+	}
+	b.Write(data)
+}
+
+// writeCSV writes one table with its header. Several tables are written as one: a leading table
+// column holds each row's table name, then come the columns of every table in order of first
+// appearance, left empty in rows of tables without them.
+func writeCSV(w io.Writer, tables []*Table) error {
+	writer := csv.NewWriter(w)
+	if len(tables) == 1 {
+		table := tables[0]
+		if err := writer.Write(table.Columns); err != nil {
+			return err
+		}
+		for _, row := range table.Rows {
+			record := make([]string, len(table.Columns))
+			for k := range table.Columns {
+				record[k] = formatValue(cell(row, k))
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	} else {
+		header := []string{"table"}
+		index := make(map[string]int)
+		for _, table := range tables {
+			for _, column := range table.Columns {
+				if _, seen := index[column]; !seen {
+					index[column] = len(header)
+					header = append(header, column)
+				}
+			}
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, table := range tables {
+			for _, row := range table.Rows {
+				record := make([]string, len(header))
+				record[0] = table.Name
+				for k, column := range table.Columns {
+					record[index[column]] = formatValue(cell(row, k))
+				}
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeMarkdown writes each table as a section with a pipe table; numeric columns are right-aligned
+func writeMarkdown(w io.Writer, tables []*Table) error {
+	var b strings.Builder
+	for i, table := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "## %s\n\n", table.Title)
+		fmt.Fprintf(&b, "| %s |\n|", strings.Join(table.Columns, " | "))
+		for k := range table.Columns {
+			if len(table.Rows) > 0 && isNumber(cell(table.Rows[0], k)) {
+				b.WriteString("---:|")
+			} else {
+				b.WriteString("---|")
+			}
+		}
+		b.WriteString("\n")
+		for _, row := range table.Rows {
+			values := make([]string, len(table.Columns))
+			for k := range table.Columns {
+				values[k] = strings.ReplaceAll(formatValue(cell(row, k)), "|", `\|`)
+			}
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(values, " | "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeText writes each table under its title with aligned columns
+func writeText(w io.Writer, tables []*Table) error {
+	for i, table := range tables {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "%s\n", table.Title)
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(table.Columns, "\t"))
+		for _, row := range table.Rows {
+			values := make([]string, len(table.Columns))
+			for k := range table.Columns {
+				values[k] = formatValue(cell(row, k))
+			}
+			fmt.Fprintln(tw, strings.Join(values, "\t"))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cell returns the k-th value of a row, or nil when the row is short
+func cell(row []interface{}, k int) interface{} {
+	if k < len(row) {
+		return row[k]
+	}
+	return nil
+}
+
+// formatValue formats a value for CSV, Markdown and text; nil is empty and floats keep full precision
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// isNumber reports whether a value is numeric
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int64, uint64, float64:
+		return true
+	}
+	return false
+}
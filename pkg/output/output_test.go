@@ -0,0 +1,87 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func testTables() []*Table {
+	blocks := NewTable("blocks", "Blocks", "scenario", "block", "base_fee")
+	blocks.Append("full", 1, uint64(1_000_000_000))
+	blocks.Append("a|b", 2, math.NaN())
+	summary := NewTable("summary", "Summary", "metric", "value")
+	summary.Append("volatility", 0.25)
+	return []*Table{blocks, summary}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, JSON, testTables()...); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string][]map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(decoded["blocks"]) != 2 || len(decoded["summary"]) != 1 {
+		t.Fatalf("unexpected tables: %v", decoded)
+	}
+	if decoded["blocks"][0]["base_fee"] != 1e9 || decoded["blocks"][1]["base_fee"] != nil {
+		t.Errorf("unexpected base fees: %v", decoded["blocks"])
+	}
+	// Columns keep their order
+	if strings.Index(buf.String(), `"scenario"`) > strings.Index(buf.String(), `"block"`) {
+		t.Errorf("columns out of order:\n%s", buf.String())
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, CSV, testTables()...); err != nil {
+		t.Fatal(err)
+	}
+	want := "table,scenario,block,base_fee,metric,value\n" +
+		"blocks,full,1,1000000000,,\n" +
+		"blocks,a|b,2,NaN,,\n" +
+		"summary,,,,volatility,0.25\n"
+	if buf.String() != want {
+		t.Errorf("expected\n%s\ngot\n%s", want, buf.String())
+	}
+
+	// Every record has the header's fields, so CSV readers accept the stream
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil || len(records) != 4 {
+		t.Errorf("expected 4 readable records, got %d: %v", len(records), err)
+	}
+
+	buf.Reset()
+	if err := Write(&buf, CSV, testTables()[1]); err != nil {
+		t.Fatal(err)
+	}
+	if want := "metric,value\nvolatility,0.25\n"; buf.String() != want {
+		t.Errorf("expected a single table without a table column\n%s\ngot\n%s", want, buf.String())
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Markdown, testTables()...); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"## Blocks\n", "| scenario | block | base_fee |\n|---|---:|---:|\n", `| a\|b | 2 | NaN |`, "## Summary\n"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown does not contain %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, Format("xml"), testTables()...); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
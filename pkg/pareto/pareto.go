@@ -0,0 +1,246 @@
+package pareto
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Objective is a metric together with the direction that counts as better
+type Objective struct {
+	Metric   string
+	Maximize bool
+}
+
+// String formats the objective in the form accepted by ParseObjectives
+func (o Objective) String() string {
+	if o.Maximize {
+		return o.Metric + ":max"
+	}
+	return o.Metric + ":min"
+}
+
+// ParseObjectives parses two or three comma-separated metric:min or metric:max terms.
+// A metric without a direction is minimized.
+func ParseObjectives(spec string) ([]Objective, error) {
+	known := make(map[string]bool)
+	for _, name := range sweep.MetricNames() {
+		known[name] = true
+	}
+
+	var objectives []Objective
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		metric, direction, _ := strings.Cut(part, ":")
+		metric = strings.TrimSpace(metric)
+		if !known[metric] {
+			return nil, fmt.Errorf("unknown pareto metric: %s", metric)
+		}
+
+		objective := Objective{Metric: metric}
+		switch strings.TrimSpace(direction) {
+		case "", "min":
+		case "max":
+			objective.Maximize = true
+		default:
+			return nil, fmt.Errorf("invalid direction %q for %s, must be min or max", direction, metric)
+		}
+		objectives = append(objectives, objective)
+	}
+
+	if len(objectives) < 2 || len(objectives) > 3 {
+		return nil, fmt.Errorf("pareto analysis needs 2 or 3 metrics, got %d", len(objectives))
+	}
+	return objectives, nil
+}
+
+// Point is one evaluated configuration with its objective values averaged over the cases
+type Point struct {
+	Index      int
+	Params     []float64 // Parameter values, in sweep parameter order
+	Flags      string    // Parameter values as command-line flags
+	Values     []float64 // Objective metric values, in objective order
+	OnFrontier bool
+}
+
+// Result contains every evaluated point and the non-dominated set
+type Result struct {
+	AdjusterType string
+	Objectives   []Objective
+	Params       []sweep.Param
+	Points       []Point
+	Frontier     []Point // Non-dominated points, ordered by the first objective
+	Skipped      int     // Points that failed validation or lack an objective metric
+}
+
+// Analyze averages each point's objective metrics over its cases and finds the non-dominated set
+func Analyze(adjusterType string, params []sweep.Param, objectives []Objective, rows []sweep.Row) Result {
+	result := Result{
+		AdjusterType: adjusterType,
+		Objectives:   objectives,
+		Params:       params,
+	}
+
+	// Rows arrive grouped by point, one per case
+	var order []int
+	byPoint := make(map[int][]sweep.Row)
+	for _, row := range rows {
+		if _, seen := byPoint[row.Point]; !seen {
+			order = append(order, row.Point)
+		}
+		byPoint[row.Point] = append(byPoint[row.Point], row)
+	}
+
+	for _, index := range order {
+		point, ok := aggregate(index, params, objectives, byPoint[index])
+		if !ok {
+			result.Skipped++
+			continue
+		}
+		result.Points = append(result.Points, point)
+	}
+
+	for i := range result.Points {
+		result.Points[i].OnFrontier = true
+		for j := range result.Points {
+			if i != j && Dominates(result.Points[j].Values, result.Points[i].Values, objectives) {
+				result.Points[i].OnFrontier = false
+				break
+			}
+		}
+		if result.Points[i].OnFrontier {
+			result.Frontier = append(result.Frontier, result.Points[i])
+		}
+	}
+
+	sort.SliceStable(result.Frontier, func(i, j int) bool {
+		return result.Frontier[i].Values[0] < result.Frontier[j].Values[0]
+	})
+	return result
+}
+
+// aggregate builds a point from its rows, or reports false if any case failed or lacks a metric
+func aggregate(index int, params []sweep.Param, objectives []Objective, rows []sweep.Row) (Point, bool) {
+	point := Point{
+		Index:  index,
+		Params: make([]float64, len(params)),
+		Values: make([]float64, len(objectives)),
+	}
+	for i, param := range params {
+		point.Params[i] = rows[0].Params[param.Name]
+	}
+	point.Flags = sweep.Flags(params, point.Params)
+
+	for i, objective := range objectives {
+		var sum float64
+		var count int
+		for _, row := range rows {
+			if row.Error != "" {
+				return Point{}, false
+			}
+			if value, ok := row.Metrics[objective.Metric]; ok {
+				sum += value
+				count++
+			}
+		}
+		if count == 0 || math.IsNaN(sum) {
+			return Point{}, false
+		}
+		point.Values[i] = sum / float64(count)
+	}
+	return point, true
+}
+
+// Dominates reports whether a is at least as good as b on every objective and strictly better on one
+func Dominates(a, b []float64, objectives []Objective) bool {
+	strictlyBetter := false
+	for i, objective := range objectives {
+		better, worse := a[i] < b[i], a[i] > b[i]
+		if objective.Maximize {
+			better, worse = worse, better
+		}
+		if worse {
+			return false
+		}
+		if better {
+			strictlyBetter = true
+		}
+	}
+	return strictlyBetter
+}
+
+// PrintResult prints the non-dominated configurations
+func PrintResult(w io.Writer, result Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "PARETO FRONTIER: %s (%d of %d points non-dominated)\n",
+		result.AdjusterType, len(result.Frontier), len(result.Points))
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	names := make([]string, len(result.Objectives))
+	for i, objective := range result.Objectives {
+		names[i] = objective.String()
+	}
+	fmt.Fprintf(w, "  Objectives: %s\n", strings.Join(names, ", "))
+	if result.Skipped > 0 {
+		fmt.Fprintf(w, "  Points skipped (invalid or missing metrics): %d\n", result.Skipped)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	header := []string{"Point"}
+	for _, param := range result.Params {
+		header = append(header, param.Name)
+	}
+	for _, objective := range result.Objectives {
+		header = append(header, objective.Metric)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, point := range result.Frontier {
+		fields := []string{fmt.Sprintf("%d", point.Index)}
+		for _, value := range point.Params {
+			fields = append(fields, fmt.Sprintf("%.6g", value))
+		}
+		for _, value := range point.Values {
+			fields = append(fields, fmt.Sprintf("%.6g", value))
+		}
+		fmt.Fprintln(tw, strings.Join(fields, "\t"))
+	}
+	tw.Flush()
+}
+
+// Table returns every evaluated point: its index, parameters, objective metrics and whether it is
+// on the frontier
+func Table(result Result) *output.Table {
+	columns := []string{"point"}
+	for _, param := range result.Params {
+		columns = append(columns, param.Name)
+	}
+	for _, objective := range result.Objectives {
+		columns = append(columns, objective.Metric)
+	}
+	columns = append(columns, "on_frontier")
+
+	table := output.NewTable("pareto", "Pareto Frontier", columns...)
+	for _, point := range result.Points {
+		values := []interface{}{point.Index}
+		for _, value := range point.Params {
+			values = append(values, value)
+		}
+		for _, value := range point.Values {
+			values = append(values, value)
+		}
+		table.Append(append(values, point.OnFrontier)...)
+	}
+	return table
+}
@@ -0,0 +1,80 @@
+package pareto
+
+import (
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+func TestParseObjectives(t *testing.T) {
+	objectives, err := ParseObjectives("base_fee_volatility, responsiveness_score:max")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if objectives[0].Maximize || !objectives[1].Maximize {
+		t.Errorf("Unexpected directions: %+v", objectives)
+	}
+
+	for _, spec := range []string{"base_fee_volatility", "a:min,b:max", "base_fee_volatility:up,target_deviation", "base_fee_volatility,target_deviation,avg_learning_rate,final_base_fee"} {
+		if _, err := ParseObjectives(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestDominates(t *testing.T) {
+	objectives := []Objective{{Metric: "x"}, {Metric: "y", Maximize: true}}
+
+	tests := []struct {
+		name     string
+		a, b     []float64
+		expected bool
+	}{
+		{"better on both", []float64{1, 5}, []float64{2, 4}, true},
+		{"better on one, equal on other", []float64{1, 4}, []float64{2, 4}, true},
+		{"equal", []float64{1, 4}, []float64{1, 4}, false},
+		{"trade-off", []float64{1, 3}, []float64{2, 4}, false},
+		{"worse", []float64{2, 4}, []float64{1, 5}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Dominates(tt.a, tt.b, objectives); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	params := []sweep.Param{{Name: "aimd-gamma"}}
+	objectives := []Objective{{Metric: "base_fee_volatility"}, {Metric: "responsiveness_score", Maximize: true}}
+
+	row := func(point int, gamma, volatility, responsiveness float64, caseName string) sweep.Row {
+		return sweep.Row{
+			Point:   point,
+			Params:  map[string]float64{"aimd-gamma": gamma},
+			Case:    caseName,
+			Metrics: map[string]float64{"base_fee_volatility": volatility, "responsiveness_score": responsiveness},
+		}
+	}
+	rows := []sweep.Row{
+		// Point 0 averages to (2, 0.5) and point 1 to (3, 0.6): a trade-off
+		row(0, 0.1, 1, 0.4, "full"), row(0, 0.1, 3, 0.6, "empty"),
+		row(1, 0.2, 3, 0.6, "full"), row(1, 0.2, 3, 0.6, "empty"),
+		// Point 2 is dominated by point 1
+		row(2, 0.3, 4, 0.5, "full"), row(2, 0.3, 4, 0.5, "empty"),
+		// Point 3 failed on one case and is skipped
+		row(3, 2.5, 0, 1, "full"), {Point: 3, Params: map[string]float64{"aimd-gamma": 2.5}, Case: "empty", Error: "invalid"},
+	}
+
+	result := Analyze("aimd", params, objectives, rows)
+	if result.Skipped != 1 || len(result.Points) != 3 {
+		t.Fatalf("Expected 3 points and 1 skipped, got %d and %d", len(result.Points), result.Skipped)
+	}
+	if len(result.Frontier) != 2 || result.Frontier[0].Index != 0 || result.Frontier[1].Index != 1 {
+		t.Fatalf("Expected frontier [0 1], got %+v", result.Frontier)
+	}
+	if result.Frontier[0].Values[0] != 2 || result.Frontier[0].Flags != "-aimd-gamma=0.1" {
+		t.Errorf("Unexpected frontier point: %+v", result.Frontier[0])
+	}
+}
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/go-echarts/go-echarts/v2/components"
+)
+
+// htmlTemplate lays out the sections of an HTML report. Charts are go-echarts snippets; their
+// scripts come from the page's assets, inlined when the report has an assets directory.
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Title }}</title>
+{{- range .Scripts }}
+{{- if .Inline }}
+<script>{{ .Inline }}</script>
+{{- else }}
+<script src="{{ .Src }}"></script>
+{{- end }}
+{{- end }}
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em auto; max-width: 1240px; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; font-size: 0.9em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: right; }
+th.text, td.text { text-align: left; }
+th { background: #f4f4f4; }
+pre { background: #f7f7f7; padding: 1em; max-height: 24em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>{{ .Title }}</h1>
+{{- range .Sections }}
+{{- if .Title }}
+<h2>{{ .Title }}</h2>
+{{- end }}
+{{- range .Paragraphs }}
+<p>{{ . }}</p>
+{{- end }}
+{{- if .List }}
+<ul>
+{{- range .List }}
+<li>{{ . }}</li>
+{{- end }}
+</ul>
+{{- end }}
+{{- if .Code }}
+<pre><code>{{ .Code }}</code></pre>
+{{- end }}
+{{- range $table := .Tables }}
+<table>
+<tr>{{ range $i, $column := .Columns }}<th{{ if $table.IsText $i }} class="text"{{ end }}>{{ $column }}</th>{{ end }}</tr>
+{{- range .Rows }}
+<tr>{{ range $i, $cell := . }}<td{{ if $table.IsText $i }} class="text"{{ end }}>{{ $cell }}</td>{{ end }}</tr>
+{{- end }}
+</table>
+{{- end }}
+{{- if .Chart }}
+{{ .Chart }}
+{{- end }}
+{{- end }}
+</body>
+</html>
+`))
+
+// htmlScript is a chart script, loaded from Src or inlined
+type htmlScript struct {
+	Src    string
+	Inline template.JS
+}
+
+// htmlSection is a section with its chart rendered to HTML
+type htmlSection struct {
+	section
+	Chart template.HTML
+}
+
+// WriteHTML writes the report as one HTML page, with each scenario's and dataset's chart
+// below its tables
+func WriteHTML(w io.Writer, report Report) error {
+	all, err := sections(report)
+	if err != nil {
+		return err
+	}
+
+	// The page merges the assets of every chart
+	page := components.NewPage()
+	for _, s := range all {
+		if s.Chart != nil {
+			page.AddCharts(s.Chart)
+		}
+	}
+	page.Validate()
+
+	scripts, err := htmlScripts(page.JSAssets.Values, report.AssetsDir)
+	if err != nil {
+		return err
+	}
+	data := struct {
+		Title    string
+		Scripts  []htmlScript
+		Sections []htmlSection
+	}{Title: report.Title, Scripts: scripts}
+	for _, s := range all {
+		rendered := htmlSection{section: s}
+		if s.Chart != nil {
+			snippet := s.Chart.RenderSnippet()
+			rendered.Chart = template.HTML(snippet.Element + snippet.Script)
+		}
+		data.Sections = append(data.Sections, rendered)
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+// saveCharts renders every chart of the report to one page and reports whether there were any
+func saveCharts(filename string, report Report) (bool, error) {
+	all, err := sections(report)
+	if err != nil {
+		return false, err
+	}
+
+	page := components.NewPage()
+	page.SetPageTitle(report.Title)
+	if report.AssetsDir != "" {
+		// Scripts are loaded relative to the charts page
+		dir, err := filepath.Rel(filepath.Dir(filename), report.AssetsDir)
+		if err != nil {
+			dir = report.AssetsDir
+		}
+		page.SetAssetsHost(filepath.ToSlash(dir) + "/")
+	}
+	for _, s := range all {
+		if s.Chart != nil {
+			page.AddCharts(s.Chart)
+		}
+	}
+	if len(page.Charts) == 0 {
+		return false, nil
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return false, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return false, fmt.Errorf("failed to render charts: %w", err)
+	}
+	return true, nil
+}
+
+// htmlScripts returns the page's scripts, inlining the file of the same name from assetsDir when
+// it is set, so the report renders offline
+func htmlScripts(urls []string, assetsDir string) ([]htmlScript, error) {
+	scripts := make([]htmlScript, len(urls))
+	for i, url := range urls {
+		if assetsDir == "" {
+			scripts[i] = htmlScript{Src: url}
+			continue
+		}
+		filename := filepath.Join(assetsDir, path.Base(url))
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chart script: %w", err)
+		}
+		scripts[i] = htmlScript{Inline: template.JS(data)}
+	}
+	return scripts, nil
+}
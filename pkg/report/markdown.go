@@ -0,0 +1,73 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteMarkdown writes the report as a Markdown document: run details, the summary table, the
+// resolved configuration, and one section per scenario and dataset. Markdown cannot hold the
+// charts; ChartsFile, when set, is linked instead.
+func WriteMarkdown(w io.Writer, report Report) error {
+	all, err := sections(report)
+	if err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", report.Title)
+	for _, s := range all {
+		b.WriteString("\n")
+		if s.Title != "" {
+			fmt.Fprintf(&b, "## %s\n\n", s.Title)
+		}
+		for _, paragraph := range s.Paragraphs {
+			fmt.Fprintf(&b, "%s\n\n", paragraph)
+		}
+		for _, item := range s.List {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		if s.Title == "" && report.ChartsFile != "" {
+			fmt.Fprintf(&b, "- Charts: [%s](%s)\n", report.ChartsFile, report.ChartsFile)
+		}
+		if s.Code != "" {
+			fmt.Fprintf(&b, "```toml\n%s```\n", s.Code)
+		}
+		for i, t := range s.Tables {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			writeMarkdownTable(&b, t)
+		}
+	}
+
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownTable writes a pipe table
+func writeMarkdownTable(b *strings.Builder, t table) {
+	writeMarkdownRow(b, t.Columns)
+	b.WriteString("|")
+	for i := range t.Columns {
+		if t.IsText(i) {
+			b.WriteString("---|")
+		} else {
+			b.WriteString("---:|")
+		}
+	}
+	b.WriteString("\n")
+	for _, row := range t.Rows {
+		writeMarkdownRow(b, row)
+	}
+}
+
+// writeMarkdownRow writes one table row, escaping the pipes in cells
+func writeMarkdownRow(b *strings.Builder, cells []string) {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+	}
+	fmt.Fprintf(b, "| %s |\n", strings.Join(escaped, " | "))
+}
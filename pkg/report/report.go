@@ -0,0 +1,294 @@
+package report
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/version"
+	"github.com/brianbland/feemarketsim/pkg/visualization"
+	"github.com/go-echarts/go-echarts/v2/charts"
+)
+
+// Report bundles the configuration and results of one run
+type Report struct {
+	Title       string
+	GeneratedAt time.Time
+	Version     string // Build version of the simulator, see version.Version
+	Config      config.Config
+	Scenarios   []compare.ScenarioComparison // One per scenario; a single-algorithm run has one label
+	DataSets    []DataSetReplay              // One per -dataset file
+	ChartsFile  string                       // Page holding the charts of a Markdown report, linked from it
+
+	// AssetsDir holds local copies of the chart scripts, e.g. echarts.min.js. HTML reports inline
+	// them and the charts page of a Markdown report loads them from there; when empty, both load
+	// the scripts from the go-echarts CDN and need network access to render their charts.
+	AssetsDir string
+}
+
+// DataSetReplay holds every run's replay of one dataset file
+type DataSetReplay struct {
+	File       string
+	Comparison compare.DataSetComparison
+}
+
+// Build runs every configured algorithm or variant on the selected scenarios and replays
+// every -dataset file, reporting the simulations' diagnostics to logger; nil discards them
+func Build(cfg config.Config, logger *slog.Logger) (Report, error) {
+	runs, err := compare.Runs(cfg)
+	if err != nil {
+		return Report{}, err
+	}
+
+	// Generate (and randomize) each scenario once so every run sees identical demand
+	scenariosToRun, err := scenarios.NewGenerator(cfg.Simulation).Select(cfg)
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := Report{
+		Title:       "Fee Market Simulation Report",
+		GeneratedAt: time.Now().UTC(),
+		Version:     version.Version(),
+		Config:      cfg,
+	}
+	for _, scenario := range scenariosToRun {
+		comparison, err := compare.CompareScenario(runs, scenario, logger)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", scenario.Name, err)
+		}
+		report.Scenarios = append(report.Scenarios, comparison)
+	}
+
+	for _, filename := range cfg.Simulation.DataSets {
+		dataset, err := blockchain.LoadDataSetFromFile(filename)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", filename, err)
+		}
+		comparison, err := compare.CompareDataSet(runs, dataset, logger)
+		if err != nil {
+			return Report{}, fmt.Errorf("%s: %w", filename, err)
+		}
+		report.DataSets = append(report.DataSets, DataSetReplay{File: filename, Comparison: comparison})
+	}
+	return report, nil
+}
+
+// SaveToFile writes the report as HTML when the file name ends in .html or .htm, and as
+// Markdown otherwise. A Markdown report links to its charts, saved next to it as <name>_charts.html.
+func SaveToFile(filename string, report Report) error {
+	ext := strings.ToLower(filepath.Ext(filename))
+	isHTML := ext == ".html" || ext == ".htm"
+
+	if !isHTML {
+		chartsFile := strings.TrimSuffix(filename, filepath.Ext(filename)) + "_charts.html"
+		saved, err := saveCharts(chartsFile, report)
+		if err != nil {
+			return err
+		}
+		if saved {
+			report.ChartsFile = filepath.Base(chartsFile)
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if isHTML {
+		err = WriteHTML(file, report)
+	} else {
+		err = WriteMarkdown(file, report)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// section is one part of the report, rendered as Markdown or HTML
+type section struct {
+	Title      string
+	Paragraphs []string
+	List       []string
+	Code       string // Preformatted TOML
+	Tables     []table
+	Chart      *charts.Line
+}
+
+// table is a table of formatted cells. The leading text columns are left-aligned and the
+// others, which hold numbers, right-aligned.
+type table struct {
+	Columns     []string
+	Rows        [][]string
+	TextColumns int
+}
+
+// sections lays out the report: run details, the summary across scenarios, the configuration,
+// then one section per scenario and dataset
+func sections(report Report) ([]section, error) {
+	cfg := report.Config
+	generator := visualization.NewGenerator(nil)
+	logScale := cfg.Simulation.LogScale
+
+	details := section{List: []string{
+		"Generated: " + report.GeneratedAt.Format(time.RFC3339),
+		fmt.Sprintf("Version: %s (%s)", report.Version, runtime.Version()),
+		"Algorithms: " + strings.Join(runLabels(report), ", "),
+		fmt.Sprintf("Randomizer seed: %d", cfg.Simulation.Randomizer.Seed),
+		fmt.Sprintf("Scenarios: %d", len(report.Scenarios)),
+	}}
+	if len(report.DataSets) > 0 {
+		details.List = append(details.List, fmt.Sprintf("Datasets: %d", len(report.DataSets)))
+	}
+	result := []section{details}
+
+	if len(report.Scenarios) > 0 {
+		result = append(result, section{Title: "Summary", Tables: []table{summaryTable(report.Scenarios)}})
+	}
+
+	var dump strings.Builder
+	if err := config.DumpConfig(&dump, cfg, "toml"); err != nil {
+		return nil, err
+	}
+	result = append(result, section{Title: "Configuration", Code: dump.String()})
+
+	for _, comparison := range report.Scenarios {
+		s := section{Title: comparison.Scenario.Name}
+		if comparison.Scenario.Description != "" {
+			s.Paragraphs = append(s.Paragraphs, comparison.Scenario.Description)
+		}
+		s.Paragraphs = append(s.Paragraphs, fmt.Sprintf("%d blocks.", len(comparison.Scenario.Blocks)))
+		s.Tables = []table{metricTable(comparison.Labels, comparison.Results)}
+		chart, err := generator.ComparisonChart(comparison, logScale)
+		if err != nil {
+			return nil, err
+		}
+		s.Chart = chart
+		result = append(result, s)
+	}
+
+	for _, replay := range report.DataSets {
+		comparison := replay.Comparison
+		dataset := comparison.DataSet
+		s := section{Title: "Replay: " + filepath.Base(replay.File)}
+		s.Paragraphs = append(s.Paragraphs, fmt.Sprintf("Base blocks %d to %d (%d blocks), simulated against the actual base fees.",
+			dataset.StartBlock, dataset.EndBlock, len(dataset.Blocks)))
+
+		results := make([]analysis.Result, len(comparison.Results))
+		for i, result := range comparison.Results {
+			results[i] = *result
+		}
+		s.Tables = []table{accuracyTable(comparison), metricTable(comparison.Labels, results)}
+		chart, err := generator.DataSetComparisonChart(comparison, logScale)
+		if err != nil {
+			return nil, err
+		}
+		s.Chart = chart
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// runLabels returns the labels of the report's runs
+func runLabels(report Report) []string {
+	if len(report.Scenarios) > 0 {
+		return report.Scenarios[0].Labels
+	}
+	if len(report.DataSets) > 0 {
+		return report.DataSets[0].Comparison.Labels
+	}
+	return report.Config.Simulation.AdjusterTypeList()
+}
+
+// summaryTable returns the headline metrics of every run on every scenario
+func summaryTable(comparisons []compare.ScenarioComparison) table {
+	t := table{
+		Columns:     []string{"Scenario", "Run", "Avg Gas %", "Final Fee", "Fee Range", "P99/P50", "Avg |Change|", "Fee Volatility", "Responsiveness"},
+		TextColumns: 2,
+	}
+	for _, comparison := range comparisons {
+		for i, result := range comparison.Results {
+			tail := "-"
+			if result.FeeP50 > 0 {
+				tail = fmt.Sprintf("%.2fx", result.FeeP99/result.FeeP50)
+			}
+			t.Rows = append(t.Rows, []string{
+				comparison.Scenario.Name,
+				comparison.Labels[i],
+				fmt.Sprintf("%.1f%%", result.AvgGasUsedPercent),
+				config.FormatGwei(float64(result.FinalBaseFee)),
+				fmt.Sprintf("%.2fx", float64(result.MaxBaseFee)/float64(result.MinBaseFee)),
+				tail,
+				fmt.Sprintf("%.2f%%", result.MeanAbsFeeChange),
+				config.FormatGwei(result.BaseFeeVolatility),
+				fmt.Sprintf("%.3f", result.ResponsivenessScore),
+			})
+		}
+	}
+	return t
+}
+
+// metricTable returns one row per metric and one column per run
+func metricTable(labels []string, results []analysis.Result) table {
+	t := table{Columns: append([]string{"Metric"}, labels...), TextColumns: 1}
+	metrics := make([]map[string]float64, len(results))
+	for i, result := range results {
+		metrics[i] = result.Metrics()
+	}
+	for _, name := range analysis.MetricNames {
+		row := []string{name}
+		for i := range metrics {
+			row = append(row, strconv.FormatFloat(metrics[i][name], 'g', 6, 64))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+	return t
+}
+
+// IsText reports whether column i holds text rather than numbers
+func (t table) IsText(i int) bool {
+	return i < t.TextColumns
+}
+
+// accuracyTable returns how closely every run reproduces the actual base fees of a dataset
+func accuracyTable(comparison compare.DataSetComparison) table {
+	t := table{Columns: append([]string{"Accuracy"}, comparison.Labels...), TextColumns: 1}
+	rows := [][]string{
+		{"RMSE"}, {"MAPE"}, {"Bias"}, {"Correlation"}, {"Best lag"},
+		{"Directional agreement"}, {"Max drawdown (actual / simulated)"}, {"Dropped transactions"},
+	}
+	for _, simResult := range comparison.SimResults {
+		accuracy := blockchain.Accuracy{}
+		if simResult.Accuracy != nil {
+			accuracy = *simResult.Accuracy
+		}
+		values := []string{
+			config.FormatGwei(accuracy.RMSE),
+			fmt.Sprintf("%.2f%%", accuracy.MAPE),
+			fmt.Sprintf("%+.2f%%", accuracy.Bias),
+			fmt.Sprintf("%.3f", accuracy.Correlation),
+			fmt.Sprintf("%+d blocks (%.3f)", accuracy.Lag, accuracy.LagCorrelation),
+			fmt.Sprintf("%.1f%%", accuracy.DirectionalAgreement),
+			fmt.Sprintf("%.1f%% / %.1f%%", accuracy.ActualMaxDrawdown, accuracy.SimulatedMaxDrawdown),
+			fmt.Sprintf("%.2f%%", simResult.DroppedPercentage),
+		}
+		for i, value := range values {
+			rows[i] = append(rows[i], value)
+		}
+	}
+	t.Rows = rows
+	return t
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func testReport(t *testing.T) Report {
+	t.Helper()
+	dataset := &blockchain.DataSet{StartBlock: 100, EndBlock: 119, InitialBaseFee: 1_000_000_000, InitialGasLimit: 30_000_000}
+	for i := uint64(0); i < 20; i++ {
+		dataset.Blocks = append(dataset.Blocks, blockchain.BlockData{
+			Number:        100 + i,
+			GasLimit:      30_000_000,
+			GasUsed:       10_000_000 + i*1_000_000,
+			BaseFeePerGas: 1_000_000_000 + i*10_000_000,
+		})
+	}
+	path := filepath.Join(t.TempDir(), "data.json")
+	if err := blockchain.SaveDataSetToFile(dataset, path); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Simulation.Scenario = "stable"
+	cfg.Simulation.AdjusterTypes = []string{"aimd", "eip1559"}
+	cfg.Simulation.Randomizer.Seed = 7
+	cfg.Simulation.DataSets = []string{path}
+	report, err := Build(cfg, nil)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return report
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	report := testReport(t)
+	if len(report.Scenarios) != 1 || len(report.DataSets) != 1 {
+		t.Fatalf("expected one scenario and one dataset, got %d and %d", len(report.Scenarios), len(report.DataSets))
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"- Randomizer seed: 7",
+		"- Version: ",
+		"## Summary",
+		`Avg \|Change\|`,
+		"| Metric | aimd | eip1559 |",
+		"## Replay: data.json",
+		"| Accuracy | aimd | eip1559 |",
+		"| MAPE |",
+		"rng-seed = 7",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("markdown does not contain %q", want)
+		}
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	report := testReport(t)
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	if got := strings.Count(html, "echarts.init("); got != 2 {
+		t.Errorf("expected a chart per scenario and dataset, got %d", got)
+	}
+	for _, want := range []string{"echarts.min.js", "<h2>Replay: data.json</h2>", "<th class=\"text\">Metric</th>"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+}
+
+func TestWriteHTMLInlinesAssets(t *testing.T) {
+	report := testReport(t)
+	report.AssetsDir = t.TempDir()
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, report); err == nil {
+		t.Error("expected an error for a missing chart script")
+	}
+
+	if err := os.WriteFile(filepath.Join(report.AssetsDir, "echarts.min.js"), []byte("var echarts = {};"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	buf.Reset()
+	if err := WriteHTML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	if !strings.Contains(html, "<script>var echarts = {};</script>") {
+		t.Error("HTML does not inline the chart script")
+	}
+	if strings.Contains(html, "<script src=") {
+		t.Error("HTML still loads a chart script from the network")
+	}
+}
+
+func TestSaveToFileLinksCharts(t *testing.T) {
+	report := testReport(t)
+	filename := filepath.Join(t.TempDir(), "report.md")
+	if err := SaveToFile(filename, report); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "[report_charts.html](report_charts.html)") {
+		t.Error("Markdown report does not link its charts")
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(filename), "report_charts.html")); err != nil {
+		t.Errorf("charts page not saved: %v", err)
+	}
+}
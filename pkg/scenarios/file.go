@@ -0,0 +1,43 @@
+package scenarios
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SaveToFile saves a scenario to a JSON file
+func SaveToFile(scenario Scenario, filename string) error {
+	jsonData, err := json.MarshalIndent(scenario, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal scenario: %w", err)
+	}
+
+	if err := os.WriteFile(filename, jsonData, 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadFromFile loads a scenario from a JSON file
+func LoadFromFile(filename string) (Scenario, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var scenario Scenario
+	if err := json.Unmarshal(data, &scenario); err != nil {
+		return Scenario{}, fmt.Errorf("failed to unmarshal scenario: %w", err)
+	}
+
+	if len(scenario.Blocks) == 0 {
+		return Scenario{}, fmt.Errorf("scenario %s contains no blocks", filename)
+	}
+	if scenario.Name == "" {
+		scenario.Name = filename
+	}
+
+	return scenario, nil
+}
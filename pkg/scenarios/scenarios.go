@@ -1,6 +1,8 @@
 package scenarios
 
 import (
+	"fmt"
+
 	"github.com/brianbland/feemarketsim/pkg/config"
 	"github.com/brianbland/feemarketsim/pkg/randomizer"
 	"github.com/brianbland/feemarketsim/pkg/simulator"
@@ -8,9 +10,9 @@ import (
 
 // Scenario represents a simulation scenario
 type Scenario struct {
-	Name        string
-	Description string
-	Blocks      []uint64 // Gas used per block
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Blocks      []uint64 `json:"blocks"` // Gas used per block
 }
 
 // Generator handles scenario generation
@@ -38,8 +40,9 @@ func (g *Generator) GenerateAll(cfg config.Config) map[string]Scenario {
 		"mixed":  g.generateMixedTraffic(cfg),
 	}
 
-	for key, scenario := range scenarios {
-		scenarios[key] = g.applyRandomness(scenario)
+	// Randomize in a fixed order so each scenario gets the same noise for a given seed
+	for _, key := range []string{"full", "empty", "stable", "mixed"} {
+		scenarios[key] = g.applyRandomness(scenarios[key])
 	}
 
 	return scenarios
@@ -52,6 +55,46 @@ func (g *Generator) GetByName(name string, cfg config.Config) (Scenario, bool) {
 	return scenario, exists
 }
 
+// Select returns the scenarios chosen by the simulation config: a scenario file,
+// a single named scenario, every built-in scenario for "all", or none for "none"
+func (g *Generator) Select(cfg config.Config) ([]Scenario, error) {
+	if cfg.Simulation.ScenarioFile != "" {
+		scenario, err := LoadFromFile(cfg.Simulation.ScenarioFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load scenario file: %w", err)
+		}
+		if randomized(cfg.Simulation.Randomizer) {
+			scenario = g.applyRandomness(scenario)
+		}
+		return []Scenario{scenario}, nil
+	}
+
+	if cfg.Simulation.Scenario == "none" {
+		return nil, nil
+	}
+
+	if cfg.Simulation.Scenario == "all" {
+		allScenarios := g.GenerateAll(cfg)
+		return []Scenario{
+			allScenarios["full"],
+			allScenarios["empty"],
+			allScenarios["stable"],
+			allScenarios["mixed"],
+		}, nil
+	}
+
+	scenario, exists := g.GetByName(cfg.Simulation.Scenario, cfg)
+	if !exists {
+		return nil, fmt.Errorf("unknown scenario: %s", cfg.Simulation.Scenario)
+	}
+	return []Scenario{scenario}, nil
+}
+
+// randomized reports whether the randomizer config adds any noise
+func randomized(cfg config.RandomizerConfig) bool {
+	return cfg.GaussianNoise > 0 || cfg.BurstProbability > 0
+}
+
 // generateFullBlocks creates a scenario with full or nearly-full blocks
 func (g *Generator) generateFullBlocks(cfg config.Config) Scenario {
 	return Scenario{
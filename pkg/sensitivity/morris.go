@@ -0,0 +1,102 @@
+package sensitivity
+
+import (
+	"math"
+	"math/rand"
+)
+
+// MorrisDesign returns r trajectories through the unit cube, each of k+1 points.
+// Every point lies on a grid of the given even number of levels, and each step moves one
+// parameter, in random order, by delta = levels/(2(levels-1)).
+func MorrisDesign(rng *rand.Rand, k, r, levels int) [][]float64 {
+	half := levels / 2
+	step := 1 / float64(levels-1)
+
+	design := make([][]float64, 0, r*(k+1))
+	for t := 0; t < r; t++ {
+		position := make([]int, k)
+		for j := range position {
+			position[j] = rng.Intn(levels)
+		}
+		design = append(design, toGrid(position, step))
+
+		// Move up by half the levels where possible, otherwise down, so every move stays on the grid
+		for _, j := range rng.Perm(k) {
+			if position[j]+half < levels {
+				position[j] += half
+			} else {
+				position[j] -= half
+			}
+			design = append(design, toGrid(position, step))
+		}
+	}
+	return design
+}
+
+// toGrid converts level positions to unit coordinates
+func toGrid(position []int, step float64) []float64 {
+	point := make([]float64, len(position))
+	for j, level := range position {
+		point[j] = float64(level) * step
+	}
+	return point
+}
+
+// MorrisIndices computes mu, mu* and sigma of the elementary effects from a MorrisDesign
+// and its outputs. Effects are per unit of the parameter's range. Steps with a NaN output
+// are skipped and counted.
+func MorrisIndices(design [][]float64, outputs []float64, k int) ([]Index, int) {
+	effects := make([][]float64, k)
+	skipped := 0
+
+	for start := 0; start+k < len(design); start += k + 1 {
+		for s := start; s < start+k; s++ {
+			before, after := design[s], design[s+1]
+			j := changed(before, after)
+			if math.IsNaN(outputs[s]) || math.IsNaN(outputs[s+1]) {
+				skipped++
+				continue
+			}
+			effects[j] = append(effects[j], (outputs[s+1]-outputs[s])/(after[j]-before[j]))
+		}
+	}
+
+	indices := make([]Index, k)
+	for j, ee := range effects {
+		if len(ee) == 0 {
+			indices[j] = Index{Mu: math.NaN(), MuStar: math.NaN(), Sigma: math.NaN()}
+			continue
+		}
+		var sum, absSum float64
+		for _, e := range ee {
+			sum += e
+			absSum += math.Abs(e)
+		}
+		mu := sum / float64(len(ee))
+
+		var variance float64
+		for _, e := range ee {
+			variance += (e - mu) * (e - mu)
+		}
+		if len(ee) > 1 {
+			variance /= float64(len(ee) - 1)
+		}
+
+		indices[j] = Index{
+			Mu:     mu,
+			MuStar: absSum / float64(len(ee)),
+			Sigma:  math.Sqrt(variance),
+		}
+	}
+	return indices, skipped
+}
+
+// changed returns the coordinate that differs between two consecutive trajectory points
+func changed(before, after []float64) int {
+	for j := range before {
+		if before[j] != after[j] {
+			return j
+		}
+	}
+	return 0
+}
@@ -0,0 +1,293 @@
+package sensitivity
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Default sample sizes when the configuration leaves them at zero
+const (
+	DefaultMorrisTrajectories = 20
+	DefaultSobolSamples       = 256
+)
+
+// defaultParams are the ranges analyzed for each adjuster when no parameters are given.
+// They span the values that pass validation and are plausible in practice. Parameters are sampled
+// independently, so ranges of ordered parameters must not overlap: the AIMD learning rates satisfy
+// min <= 0.01 <= initial <= 0.1 <= max at every point.
+var defaultParams = map[string][]string{
+	"aimd": {
+		"aimd-gamma=0.05:1",
+		"aimd-alpha=log(0.001,0.1,2)",
+		"aimd-beta=0.5:0.99",
+		"aimd-delta=0:0.1",
+		"aimd-min-learning-rate=log(0.0001,0.01,2)",
+		"aimd-max-learning-rate=0.1:1",
+		"aimd-initial-learning-rate=0.01:0.1",
+		"window-size=2:30",
+	},
+	"pid": {
+		"pid-kp=log(0.001,1,2)",
+		"pid-ki=log(0.0000001,0.001,2)",
+		"pid-kd=log(0.0001,0.1,2)",
+		"pid-max-fee-change=0.05:0.5",
+		"window-size=2:30",
+	},
+	"eip1559": {
+		"eip1559-max-fee-change=0.01:0.5",
+	},
+}
+
+// DefaultParams returns the parameter ranges analyzed for an adjuster when none are given
+func DefaultParams(adjusterType string) ([]sweep.Param, error) {
+	if adjusterType == "eip-1559" {
+		adjusterType = "eip1559"
+	}
+	specs, ok := defaultParams[adjusterType]
+	if !ok {
+		return nil, fmt.Errorf("no default parameters for adjuster type: %s", adjusterType)
+	}
+	return sweep.ParseParams(specs)
+}
+
+// ParseMetrics parses a comma-separated list of output metrics
+func ParseMetrics(spec string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, name := range sweep.MetricNames() {
+		known[name] = true
+	}
+
+	var metrics []string
+	for _, part := range strings.Split(spec, ",") {
+		metric := strings.TrimSpace(part)
+		if metric == "" {
+			continue
+		}
+		if !known[metric] {
+			return nil, fmt.Errorf("unknown sensitivity metric: %s", metric)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("no sensitivity metrics given")
+	}
+	return metrics, nil
+}
+
+// Index is the sensitivity of one metric to one parameter.
+// Morris fills Mu, MuStar and Sigma; Sobol fills First and Total.
+type Index struct {
+	Param  string
+	Mu     float64 // Mean elementary effect; its sign is the direction of the effect
+	MuStar float64 // Mean absolute elementary effect, the overall importance
+	Sigma  float64 // Standard deviation of the elementary effects, from nonlinearity or interactions
+	First  float64 // Share of output variance explained by the parameter alone
+	Total  float64 // Share of output variance involving the parameter, including interactions
+}
+
+// MetricResult holds the indices of every parameter for one metric, most influential first
+type MetricResult struct {
+	Metric  string
+	Indices []Index
+	Skipped int // Samples dropped because a point failed or produced NaN
+}
+
+// Result contains the sensitivity of every metric
+type Result struct {
+	Method       string
+	AdjusterType string
+	Params       []sweep.Param
+	Evaluations  int // Parameter points evaluated, each on every case
+	Metrics      []MetricResult
+}
+
+// Analyzer runs a sensitivity analysis over a set of parameters
+type Analyzer struct {
+	config  config.Config
+	params  []sweep.Param
+	metrics []string
+	sweep   *sweep.Sweep
+}
+
+// NewAnalyzer creates an analyzer of the given metrics over the given parameters
+func NewAnalyzer(cfg config.Config, params []sweep.Param, metrics []string) (*Analyzer, error) {
+	sw, err := sweep.New(cfg, params)
+	if err != nil {
+		return nil, err
+	}
+	return &Analyzer{
+		config:  cfg,
+		params:  params,
+		metrics: metrics,
+		sweep:   sw,
+	}, nil
+}
+
+// Samples returns the Morris trajectories or Sobol base samples, applying the method default
+func (a *Analyzer) Samples() int {
+	s := a.config.Simulation.Sensitivity
+	switch {
+	case s.Samples > 0:
+		return s.Samples
+	case s.Method == "sobol":
+		return DefaultSobolSamples
+	default:
+		return DefaultMorrisTrajectories
+	}
+}
+
+// Evaluations returns the number of parameter points the analysis evaluates
+func (a *Analyzer) Evaluations() int {
+	if a.config.Simulation.Sensitivity.Method == "sobol" {
+		return a.Samples() * (len(a.params) + 2)
+	}
+	return a.Samples() * (len(a.params) + 1)
+}
+
+// Cases returns the cases every point is evaluated on
+func (a *Analyzer) Cases() []sweep.Case {
+	return a.sweep.Cases()
+}
+
+// Run draws the design from the randomizer seed, evaluates it and computes the indices
+func (a *Analyzer) Run(ctx context.Context) (Result, error) {
+	s := a.config.Simulation.Sensitivity
+	rng := rand.New(rand.NewSource(a.config.Simulation.Randomizer.Seed))
+	k := len(a.params)
+
+	var design [][]float64
+	if s.Method == "sobol" {
+		design = SobolDesign(rng, k, a.Samples())
+	} else {
+		design = MorrisDesign(rng, k, a.Samples(), s.Levels)
+	}
+
+	points := make([][]float64, len(design))
+	for i, unit := range design {
+		points[i] = make([]float64, k)
+		for j, param := range a.params {
+			points[i][j] = param.FromUnit(unit[j])
+		}
+	}
+
+	rows, err := a.sweep.RunPoints(ctx, points)
+	if err != nil {
+		return Result{}, err
+	}
+	means := sweep.MeanMetrics(rows, len(points))
+
+	result := Result{
+		Method:       s.Method,
+		AdjusterType: a.config.Simulation.AdjusterType,
+		Params:       a.params,
+		Evaluations:  len(points),
+	}
+	for _, metric := range a.metrics {
+		outputs := make([]float64, len(points))
+		for i, m := range means {
+			value, ok := m[metric]
+			if !ok {
+				value = math.NaN()
+			}
+			outputs[i] = value
+		}
+
+		var indices []Index
+		var skipped int
+		if s.Method == "sobol" {
+			indices, skipped = SobolIndices(outputs, k)
+		} else {
+			indices, skipped = MorrisIndices(design, outputs, k)
+		}
+		for j := range indices {
+			indices[j].Param = a.params[j].Name
+		}
+		rank(indices, s.Method)
+
+		result.Metrics = append(result.Metrics, MetricResult{
+			Metric:  metric,
+			Indices: indices,
+			Skipped: skipped,
+		})
+	}
+	return result, nil
+}
+
+// rank orders indices by Morris mu* or Sobol total effect, largest first
+func rank(indices []Index, method string) {
+	key := func(index Index) float64 {
+		if method == "sobol" {
+			return index.Total
+		}
+		return index.MuStar
+	}
+	sort.SliceStable(indices, func(i, j int) bool {
+		return key(indices[i]) > key(indices[j])
+	})
+}
+
+// PrintResult prints the ranked parameters for every metric
+func PrintResult(w io.Writer, result Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "SENSITIVITY ANALYSIS: %s (%s, %d evaluations)\n",
+		result.AdjusterType, result.Method, result.Evaluations)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	for _, metric := range result.Metrics {
+		fmt.Fprintf(w, "\n%s\n", metric.Metric)
+		if metric.Skipped > 0 {
+			fmt.Fprintf(w, "  Samples skipped (invalid points or NaN metrics): %d\n", metric.Skipped)
+		}
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		if result.Method == "sobol" {
+			fmt.Fprintln(tw, "  Rank\tParameter\tFirst-order\tTotal-effect\tInteraction")
+			for i, index := range metric.Indices {
+				fmt.Fprintf(tw, "  %d\t%s\t%.4f\t%.4f\t%.4f\n",
+					i+1, index.Param, index.First, index.Total, index.Total-index.First)
+			}
+		} else {
+			fmt.Fprintln(tw, "  Rank\tParameter\tmu*\tmu\tsigma")
+			for i, index := range metric.Indices {
+				fmt.Fprintf(tw, "  %d\t%s\t%.6g\t%.6g\t%.6g\n",
+					i+1, index.Param, index.MuStar, index.Mu, index.Sigma)
+			}
+		}
+		tw.Flush()
+	}
+
+	if result.Method == "sobol" {
+		fmt.Fprintln(w, "\n  Interaction = total-effect - first-order: variance from combinations with other parameters.")
+	} else {
+		fmt.Fprintln(w, "\n  Effects are per full parameter range. A large sigma relative to mu* means the")
+		fmt.Fprintln(w, "  effect depends on the other parameters (nonlinearity or interactions).")
+	}
+}
+
+// Table returns the indices of every parameter per metric, most influential first. Morris fills
+// mu_star, mu and sigma; Sobol fills first and total.
+func Table(result Result) *output.Table {
+	table := output.NewTable("sensitivity", "Sensitivity Analysis", "metric", "rank", "param",
+		"mu_star", "mu", "sigma", "first", "total")
+	for _, metric := range result.Metrics {
+		for i, index := range metric.Indices {
+			if result.Method == "sobol" {
+				table.Append(metric.Metric, i+1, index.Param, nil, nil, nil, index.First, index.Total)
+			} else {
+				table.Append(metric.Metric, i+1, index.Param, index.MuStar, index.Mu, index.Sigma, nil, nil)
+			}
+		}
+	}
+	return table
+}
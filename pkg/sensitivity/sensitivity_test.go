@@ -0,0 +1,194 @@
+package sensitivity
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func TestMorrisLinearFunction(t *testing.T) {
+	// f = 10*x0 - x1 has constant elementary effects and ignores x2
+	rng := rand.New(rand.NewSource(1))
+	design := MorrisDesign(rng, 3, 10, 4)
+	if len(design) != 10*4 {
+		t.Fatalf("Expected 40 design points, got %d", len(design))
+	}
+
+	outputs := make([]float64, len(design))
+	for i, x := range design {
+		outputs[i] = 10*x[0] - x[1]
+	}
+
+	indices, skipped := MorrisIndices(design, outputs, 3)
+	if skipped != 0 {
+		t.Errorf("Expected no skipped steps, got %d", skipped)
+	}
+
+	expected := []Index{{Mu: 10, MuStar: 10}, {Mu: -1, MuStar: 1}, {}}
+	for j, want := range expected {
+		got := indices[j]
+		if math.Abs(got.Mu-want.Mu) > 1e-9 || math.Abs(got.MuStar-want.MuStar) > 1e-9 || got.Sigma > 1e-9 {
+			t.Errorf("Parameter %d: expected mu=%g mu*=%g sigma=0, got %+v", j, want.Mu, want.MuStar, got)
+		}
+	}
+}
+
+func TestMorrisSkipsNaN(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	design := MorrisDesign(rng, 2, 5, 4)
+	outputs := make([]float64, len(design))
+	for i, x := range design {
+		outputs[i] = x[0] + x[1]
+	}
+	outputs[0] = math.NaN()
+
+	_, skipped := MorrisIndices(design, outputs, 2)
+	if skipped != 1 {
+		t.Errorf("Expected 1 skipped step, got %d", skipped)
+	}
+}
+
+func TestSobolIshigami(t *testing.T) {
+	// The Ishigami function has known indices:
+	// S = (0.314, 0.442, 0), ST = (0.558, 0.442, 0.244)
+	rng := rand.New(rand.NewSource(42))
+	n := 8192
+	design := SobolDesign(rng, 3, n)
+	if len(design) != n*5 {
+		t.Fatalf("Expected %d design points, got %d", n*5, len(design))
+	}
+
+	outputs := make([]float64, len(design))
+	for i, u := range design {
+		x := make([]float64, 3)
+		for j := range x {
+			x[j] = -math.Pi + 2*math.Pi*u[j]
+		}
+		outputs[i] = math.Sin(x[0]) + 7*math.Pow(math.Sin(x[1]), 2) + 0.1*math.Pow(x[2], 4)*math.Sin(x[0])
+	}
+
+	indices, skipped := SobolIndices(outputs, 3)
+	if skipped != 0 {
+		t.Errorf("Expected no skipped samples, got %d", skipped)
+	}
+
+	first := []float64{0.314, 0.442, 0}
+	total := []float64{0.558, 0.442, 0.244}
+	for j := range indices {
+		if math.Abs(indices[j].First-first[j]) > 0.05 {
+			t.Errorf("Parameter %d: expected first-order ~%.3f, got %.3f", j, first[j], indices[j].First)
+		}
+		if math.Abs(indices[j].Total-total[j]) > 0.05 {
+			t.Errorf("Parameter %d: expected total-effect ~%.3f, got %.3f", j, total[j], indices[j].Total)
+		}
+	}
+}
+
+func TestDefaultParams(t *testing.T) {
+	for _, adjusterType := range []string{"aimd", "pid", "eip1559", "eip-1559"} {
+		params, err := DefaultParams(adjusterType)
+		if err != nil {
+			t.Errorf("Unexpected error for %s: %v", adjusterType, err)
+			continue
+		}
+		for _, param := range params {
+			if !param.Continuous || param.Min >= param.Max {
+				t.Errorf("%s: expected a continuous range for %s, got %+v", adjusterType, param.Name, param)
+			}
+		}
+	}
+	if _, err := DefaultParams("unknown"); err == nil {
+		t.Error("Expected an error for an unknown adjuster type")
+	}
+}
+
+func TestDefaultParamsSkipNoPoints(t *testing.T) {
+	for _, adjusterType := range []string{"aimd", "pid", "eip1559"} {
+		for _, method := range []string{"morris", "sobol"} {
+			cfg := config.Default()
+			cfg.Simulation.AdjusterType = adjusterType
+			cfg.Simulation.Scenario = "stable"
+			cfg.Simulation.Randomizer.Seed = 1
+			cfg.Simulation.Sensitivity.Method = method
+			if method == "sobol" {
+				cfg.Simulation.Sensitivity.Samples = 32
+			}
+
+			params, err := DefaultParams(adjusterType)
+			if err != nil {
+				t.Fatalf("Failed to get default params: %v", err)
+			}
+			metrics, err := ParseMetrics("base_fee_volatility")
+			if err != nil {
+				t.Fatalf("Failed to parse metrics: %v", err)
+			}
+			analyzer, err := NewAnalyzer(cfg, params, metrics)
+			if err != nil {
+				t.Fatalf("Failed to create analyzer: %v", err)
+			}
+			result, err := analyzer.Run(context.Background())
+			if err != nil {
+				t.Fatalf("%s %s: analysis failed: %v", adjusterType, method, err)
+			}
+			if skipped := result.Metrics[0].Skipped; skipped > 0 {
+				t.Errorf("%s %s: %d of %d samples skipped", adjusterType, method, skipped, result.Evaluations)
+			}
+		}
+	}
+}
+
+func TestRunRanksParameters(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "aimd"
+	cfg.Simulation.Scenario = "stable"
+	cfg.Simulation.Randomizer.Seed = 3
+	cfg.Simulation.Sensitivity.Samples = 3
+
+	params, err := DefaultParams("aimd")
+	if err != nil {
+		t.Fatalf("Failed to get default params: %v", err)
+	}
+	metrics, err := ParseMetrics("base_fee_volatility,responsiveness_score")
+	if err != nil {
+		t.Fatalf("Failed to parse metrics: %v", err)
+	}
+
+	analyzer, err := NewAnalyzer(cfg, params, metrics)
+	if err != nil {
+		t.Fatalf("Failed to create analyzer: %v", err)
+	}
+	result, err := analyzer.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Analysis failed: %v", err)
+	}
+
+	if result.Evaluations != analyzer.Evaluations() {
+		t.Errorf("Expected %d evaluations, got %d", analyzer.Evaluations(), result.Evaluations)
+	}
+	if len(result.Metrics) != 2 {
+		t.Fatalf("Expected 2 metric results, got %d", len(result.Metrics))
+	}
+	for _, metric := range result.Metrics {
+		if len(metric.Indices) != len(params) {
+			t.Errorf("%s: expected %d indices, got %d", metric.Metric, len(params), len(metric.Indices))
+		}
+		for i := 1; i < len(metric.Indices); i++ {
+			if metric.Indices[i].MuStar > metric.Indices[i-1].MuStar {
+				t.Errorf("%s: indices not ranked by mu*: %+v", metric.Metric, metric.Indices)
+				break
+			}
+		}
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	if _, err := ParseMetrics("base_fee_volatility,nope"); err == nil {
+		t.Error("Expected an error for an unknown metric")
+	}
+	if _, err := ParseMetrics(" , "); err == nil {
+		t.Error("Expected an error for an empty metric list")
+	}
+}
@@ -0,0 +1,94 @@
+package sensitivity
+
+import (
+	"math"
+	"math/rand"
+)
+
+// SobolDesign returns the Saltelli sample for n base samples of k parameters in the unit cube:
+// n rows of matrix A, n rows of matrix B, then for each parameter i the n rows of A with
+// column i taken from B.
+func SobolDesign(rng *rand.Rand, k, n int) [][]float64 {
+	a := make([][]float64, n)
+	b := make([][]float64, n)
+	for s := 0; s < n; s++ {
+		a[s] = make([]float64, k)
+		b[s] = make([]float64, k)
+		for j := 0; j < k; j++ {
+			a[s][j] = rng.Float64()
+			b[s][j] = rng.Float64()
+		}
+	}
+
+	design := make([][]float64, 0, n*(k+2))
+	design = append(design, a...)
+	design = append(design, b...)
+	for i := 0; i < k; i++ {
+		for s := 0; s < n; s++ {
+			ab := append([]float64{}, a[s]...)
+			ab[i] = b[s][i]
+			design = append(design, ab)
+		}
+	}
+	return design
+}
+
+// SobolIndices computes first-order (Saltelli 2010) and total-effect (Jansen) indices from
+// the outputs of a SobolDesign. Base samples with a NaN output are skipped and counted.
+func SobolIndices(outputs []float64, k int) ([]Index, int) {
+	n := len(outputs) / (k + 2)
+	fA, fB := outputs[:n], outputs[n:2*n]
+
+	// A base sample is usable only if every one of its k+2 evaluations succeeded
+	var usable []int
+	for s := 0; s < n; s++ {
+		ok := !math.IsNaN(fA[s]) && !math.IsNaN(fB[s])
+		for i := 0; i < k && ok; i++ {
+			ok = !math.IsNaN(outputs[(2+i)*n+s])
+		}
+		if ok {
+			usable = append(usable, s)
+		}
+	}
+	skipped := n - len(usable)
+
+	indices := make([]Index, k)
+	variance := pooledVariance(fA, fB, usable)
+	if len(usable) == 0 || variance == 0 {
+		// Without variance every parameter is equally (un)important
+		return indices, skipped
+	}
+
+	count := float64(len(usable))
+	for i := 0; i < k; i++ {
+		fAB := outputs[(2+i)*n : (3+i)*n]
+		var first, total float64
+		for _, s := range usable {
+			first += fB[s] * (fAB[s] - fA[s])
+			total += (fA[s] - fAB[s]) * (fA[s] - fAB[s])
+		}
+		indices[i] = Index{
+			First: first / count / variance,
+			Total: total / (2 * count) / variance,
+		}
+	}
+	return indices, skipped
+}
+
+// pooledVariance returns the variance of the usable outputs of A and B together
+func pooledVariance(fA, fB []float64, usable []int) float64 {
+	if len(usable) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, s := range usable {
+		sum += fA[s] + fB[s]
+	}
+	mean := sum / float64(2*len(usable))
+
+	var variance float64
+	for _, s := range usable {
+		variance += (fA[s]-mean)*(fA[s]-mean) + (fB[s]-mean)*(fB[s]-mean)
+	}
+	return variance / float64(2*len(usable))
+}
@@ -0,0 +1,326 @@
+package significance
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/montecarlo"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+	"github.com/brianbland/feemarketsim/pkg/stats"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Verdicts of a paired test
+const (
+	VerdictLower        = "lower"
+	VerdictHigher       = "higher"
+	VerdictNoDifference = "no significant difference"
+	VerdictInconclusive = "inconclusive"
+)
+
+// Test is the paired comparison of one metric between the two adjusters
+type Test struct {
+	Metric     string  `json:"metric"`
+	Pairs      int     `json:"pairs"`
+	MeanA      float64 `json:"meanA"`
+	MeanB      float64 `json:"meanB"`
+	Difference float64 `json:"difference"` // Mean paired difference A - B
+	Relative   float64 `json:"relative"`   // Difference as a percentage of |MeanB|, 0 if MeanB is 0
+	CILow      float64 `json:"ciLow"`      // Bootstrap confidence interval of the mean difference
+	CIHigh     float64 `json:"ciHigh"`
+	PValue     float64 `json:"pValue"` // Two-sided Wilcoxon signed-rank p-value
+	Verdict    string  `json:"verdict"`
+}
+
+// Group holds the tests of one workload: a scenario paired over seeds or a dataset paired over segments
+type Group struct {
+	Name  string `json:"name"`
+	Tests []Test `json:"tests"`
+}
+
+// Result contains every paired test of a comparison
+type Result struct {
+	AdjusterA  string  `json:"adjusterA"`
+	AdjusterB  string  `json:"adjusterB"`
+	Confidence float64 `json:"confidence"`
+	Resamples  int     `json:"resamples"`
+	Groups     []Group `json:"groups"`
+}
+
+// ParseMetrics parses a comma-separated list of metrics to test
+func ParseMetrics(spec string) ([]string, error) {
+	known := make(map[string]bool)
+	for _, name := range sweep.MetricNames() {
+		known[name] = true
+	}
+
+	var metrics []string
+	for _, part := range strings.Split(spec, ",") {
+		metric := strings.TrimSpace(part)
+		if metric == "" {
+			continue
+		}
+		if !known[metric] {
+			return nil, fmt.Errorf("unknown significance metric: %s", metric)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("no significance metrics given")
+	}
+	return metrics, nil
+}
+
+// Comparer runs two adjusters on identical workloads and tests their paired metrics
+type Comparer struct {
+	config  config.Config
+	metrics []string
+}
+
+// NewComparer creates a comparer of -adjuster-type against -sig-against
+func NewComparer(cfg config.Config) (*Comparer, error) {
+	a, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
+	if err != nil {
+		return nil, err
+	}
+	b, err := simulator.ParseAdjusterType(cfg.Simulation.Significance.Against)
+	if err != nil {
+		return nil, err
+	}
+	if a == b {
+		return nil, fmt.Errorf("cannot compare %s with itself", a)
+	}
+
+	metrics, err := ParseMetrics(cfg.Simulation.Significance.Metrics)
+	if err != nil {
+		return nil, err
+	}
+	return &Comparer{config: cfg, metrics: metrics}, nil
+}
+
+// Run evaluates both adjusters and tests every metric of every workload.
+// Synthetic scenarios are paired by randomizer seed, so both adjusters see the same noise;
+// datasets are paired by block range.
+func (c *Comparer) Run(ctx context.Context) (Result, error) {
+	sig := c.config.Simulation.Significance
+	result := Result{
+		AdjusterA:  c.config.Simulation.AdjusterType,
+		AdjusterB:  sig.Against,
+		Confidence: sig.Confidence,
+		Resamples:  sig.Resamples,
+	}
+	rng := rand.New(rand.NewSource(c.config.Simulation.Randomizer.Seed))
+
+	scenarioGroups, err := c.scenarioGroups(ctx)
+	if err != nil {
+		return Result{}, err
+	}
+	for _, g := range scenarioGroups {
+		result.Groups = append(result.Groups, c.test(g, rng))
+	}
+
+	for _, filename := range c.config.Simulation.DataSets {
+		g, err := c.datasetGroup(ctx, filename)
+		if err != nil {
+			return Result{}, err
+		}
+		result.Groups = append(result.Groups, c.test(g, rng))
+	}
+
+	if len(result.Groups) == 0 {
+		return Result{}, fmt.Errorf("no scenarios or datasets selected")
+	}
+	return result, nil
+}
+
+// observations holds paired metric values of one workload, in pair order
+type observations struct {
+	name string
+	a, b []map[string]float64
+}
+
+// scenarioGroups runs the Monte Carlo seeds with both adjusters and pairs them per scenario
+func (c *Comparer) scenarioGroups(ctx context.Context) ([]observations, error) {
+	cfgB := c.config
+	cfgB.Simulation.AdjusterType = c.config.Simulation.Significance.Against
+
+	runsA, err := montecarlo.NewRunner(c.config).RunSeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	runsB, err := montecarlo.NewRunner(cfgB).RunSeeds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(runsA) == 0 {
+		return nil, nil
+	}
+
+	groups := make([]observations, len(runsA[0].Results))
+	for s := range groups {
+		groups[s].name = runsA[0].Results[s].ScenarioName
+		for i := range runsA {
+			groups[s].a = append(groups[s].a, runsA[i].Results[s].Metrics())
+			groups[s].b = append(groups[s].b, runsB[i].Results[s].Metrics())
+		}
+	}
+	return groups, nil
+}
+
+// datasetGroup splits a dataset into block ranges and runs both adjusters on each
+func (c *Comparer) datasetGroup(ctx context.Context, filename string) (observations, error) {
+	dataset, err := blockchain.LoadDataSetFromFile(filename)
+	if err != nil {
+		return observations{}, fmt.Errorf("failed to load dataset %s: %w", filename, err)
+	}
+	if err := blockchain.ValidateDataSet(dataset); err != nil {
+		return observations{}, fmt.Errorf("invalid dataset %s: %w", filename, err)
+	}
+	parts, err := blockchain.SplitDataSet(dataset, c.config.Simulation.Significance.Segments)
+	if err != nil {
+		return observations{}, fmt.Errorf("failed to split %s: %w", filename, err)
+	}
+
+	cfgB := c.config
+	cfgB.Simulation.AdjusterType = c.config.Simulation.Significance.Against
+
+	group := observations{name: filename}
+	for _, part := range parts {
+		if err := ctx.Err(); err != nil {
+			return observations{}, err
+		}
+		metricsA, err := sweep.EvaluateCase(c.config, sweep.Case{DataSet: part})
+		if err != nil {
+			return observations{}, err
+		}
+		metricsB, err := sweep.EvaluateCase(cfgB, sweep.Case{DataSet: part})
+		if err != nil {
+			return observations{}, err
+		}
+		group.a = append(group.a, metricsA)
+		group.b = append(group.b, metricsB)
+	}
+	return group, nil
+}
+
+// test runs the paired tests of every metric the workload reports
+func (c *Comparer) test(g observations, rng *rand.Rand) Group {
+	sig := c.config.Simulation.Significance
+	group := Group{Name: g.name}
+
+	for _, metric := range c.metrics {
+		var a, b []float64
+		for i := range g.a {
+			valueA, okA := g.a[i][metric]
+			valueB, okB := g.b[i][metric]
+			if okA && okB {
+				a = append(a, valueA)
+				b = append(b, valueB)
+			}
+		}
+		if len(a) == 0 {
+			continue
+		}
+		group.Tests = append(group.Tests, PairedTest(metric, a, b, sig.Resamples, sig.Confidence, rng))
+	}
+	return group
+}
+
+// PairedTest compares paired samples a and b with a bootstrap confidence interval of the mean
+// difference and a Wilcoxon signed-rank test. The difference is significant when the p-value is
+// below 1 - confidence and the interval excludes zero; when only one of them says so the
+// verdict is inconclusive.
+func PairedTest(metric string, a, b []float64, resamples int, confidence float64, rng *rand.Rand) Test {
+	differences := make([]float64, len(a))
+	for i := range a {
+		differences[i] = a[i] - b[i]
+	}
+
+	test := Test{
+		Metric:     metric,
+		Pairs:      len(a),
+		MeanA:      stats.Mean(a),
+		MeanB:      stats.Mean(b),
+		Difference: stats.Mean(differences),
+	}
+	if test.MeanB != 0 {
+		test.Relative = test.Difference / math.Abs(test.MeanB) * 100
+	}
+	test.CILow, test.CIHigh = stats.BootstrapMeanCI(differences, resamples, confidence, rng)
+	test.PValue = stats.WilcoxonSignedRank(differences).PValue
+
+	significant := test.PValue < 1-confidence
+	excludesZero := test.CILow > 0 || test.CIHigh < 0
+	switch {
+	case significant && excludesZero && test.Difference < 0:
+		test.Verdict = VerdictLower
+	case significant && excludesZero:
+		test.Verdict = VerdictHigher
+	case !significant && !excludesZero:
+		test.Verdict = VerdictNoDifference
+	default:
+		test.Verdict = VerdictInconclusive
+	}
+	return test
+}
+
+// PrintResult prints the verdict table of every workload
+func PrintResult(w io.Writer, result Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "SIGNIFICANCE: %s (A) vs %s (B), %.0f%% confidence\n",
+		result.AdjusterA, result.AdjusterB, result.Confidence*100)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	for _, group := range result.Groups {
+		pairs := 0
+		if len(group.Tests) > 0 {
+			pairs = group.Tests[0].Pairs
+		}
+		fmt.Fprintf(w, "\n%s (%d pairs)\n", group.Name, pairs)
+		fmt.Fprintf(w, strings.Repeat("-", 60)+"\n")
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "Metric\tMean A\tMean B\tA - B\tRelative\tCI\tp-value\tVerdict")
+		for _, test := range group.Tests {
+			fmt.Fprintf(tw, "%s\t%.6g\t%.6g\t%.4g\t%+.1f%%\t[%.4g, %.4g]\t%.4f\t%s\n",
+				test.Metric, test.MeanA, test.MeanB, test.Difference, test.Relative,
+				test.CILow, test.CIHigh, test.PValue, verdictText(test.Verdict, result.AdjusterA))
+		}
+		tw.Flush()
+	}
+
+	fmt.Fprintf(w, "\nCI is the %.0f%% bootstrap interval of the mean paired difference (%d resamples);\n",
+		result.Confidence*100, result.Resamples)
+	fmt.Fprintf(w, "p-values are from the two-sided Wilcoxon signed-rank test.\n")
+}
+
+// ResultTable returns one row per workload and metric. The verdict is the direction of adjuster A
+// relative to adjuster B.
+func ResultTable(result Result) *output.Table {
+	table := output.NewTable("significance", "Significance", "adjuster_a", "adjuster_b", "group", "metric", "pairs",
+		"mean_a", "mean_b", "difference", "relative_percent", "ci_low", "ci_high", "p_value", "verdict")
+	for _, group := range result.Groups {
+		for _, test := range group.Tests {
+			table.Append(result.AdjusterA, result.AdjusterB, group.Name, test.Metric, test.Pairs,
+				test.MeanA, test.MeanB, test.Difference, test.Relative, test.CILow, test.CIHigh, test.PValue, test.Verdict)
+		}
+	}
+	return table
+}
+
+// verdictText names the direction of a significant difference after adjuster A
+func verdictText(verdict, adjusterA string) string {
+	if verdict == VerdictLower || verdict == VerdictHigher {
+		return adjusterA + " " + verdict
+	}
+	return verdict
+}
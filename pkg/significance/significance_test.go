@@ -0,0 +1,81 @@
+package significance
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func TestPairedTestVerdicts(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	noise := make([]float64, 40)
+	for i := range noise {
+		noise[i] = rng.NormFloat64()
+	}
+
+	shifted := make([]float64, len(noise))
+	for i := range noise {
+		shifted[i] = noise[i] + 2 + 0.1*rng.NormFloat64()
+	}
+	if test := PairedTest("m", noise, shifted, 1000, 0.95, rng); test.Verdict != VerdictLower {
+		t.Errorf("Expected %q for a consistent shift, got %q (p=%f)", VerdictLower, test.Verdict, test.PValue)
+	}
+	if test := PairedTest("m", shifted, noise, 1000, 0.95, rng); test.Verdict != VerdictHigher {
+		t.Errorf("Expected %q for a consistent shift, got %q", VerdictHigher, test.Verdict)
+	}
+
+	jittered := make([]float64, len(noise))
+	for i := range noise {
+		jittered[i] = noise[i] + rng.NormFloat64()
+	}
+	test := PairedTest("m", noise, jittered, 1000, 0.95, rng)
+	if test.Verdict == VerdictLower || test.Verdict == VerdictHigher {
+		t.Errorf("Expected no significant verdict for pure noise, got %q (p=%f)", test.Verdict, test.PValue)
+	}
+	if test.Pairs != len(noise) || test.CILow > test.CIHigh {
+		t.Errorf("Unexpected test summary %+v", test)
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	metrics, err := ParseMetrics("fee_p99, base_fee_volatility")
+	if err != nil || len(metrics) != 2 || metrics[0] != "fee_p99" {
+		t.Fatalf("Unexpected metrics %v, %v", metrics, err)
+	}
+	if _, err := ParseMetrics("not_a_metric"); err == nil {
+		t.Error("Expected an error for an unknown metric")
+	}
+}
+
+func TestComparerPairsSeeds(t *testing.T) {
+	cfg := config.Default()
+	cfg.Simulation.Scenario = "stable"
+	cfg.Simulation.AdjusterType = "aimd"
+	cfg.Simulation.Significance.Against = "eip1559"
+	cfg.Simulation.Significance.Metrics = "mean_abs_fee_change"
+	cfg.Simulation.Significance.Resamples = 200
+	cfg.Simulation.MonteCarlo.Runs = 8
+	cfg.Simulation.Randomizer.GaussianNoise = 0.1
+
+	comparer, err := NewComparer(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create comparer: %v", err)
+	}
+	result, err := comparer.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Comparison failed: %v", err)
+	}
+	if len(result.Groups) != 1 || len(result.Groups[0].Tests) != 1 {
+		t.Fatalf("Expected one group with one test, got %+v", result.Groups)
+	}
+	if pairs := result.Groups[0].Tests[0].Pairs; pairs != 8 {
+		t.Errorf("Expected 8 pairs, got %d", pairs)
+	}
+
+	cfg.Simulation.Significance.Against = "aimd"
+	if _, err := NewComparer(cfg); err == nil {
+		t.Error("Expected an error comparing an adjuster with itself")
+	}
+}
@@ -2,6 +2,7 @@ package simulator
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/brianbland/feemarketsim/pkg/config"
@@ -17,13 +18,20 @@ const (
 )
 
 // AdjusterFactory creates fee adjusters based on configuration
-type AdjusterFactory struct{}
+type AdjusterFactory struct {
+	logger *slog.Logger
+}
 
 // NewAdjusterFactory creates a new adjuster factory
 func NewAdjusterFactory() *AdjusterFactory {
 	return &AdjusterFactory{}
 }
 
+// SetLogger sets the logger the adjusters created afterwards report their internals to; nil discards
+func (f *AdjusterFactory) SetLogger(logger *slog.Logger) {
+	f.logger = logger
+}
+
 // CreateAdjuster creates a fee adjuster based on the specified type and config
 func (f *AdjusterFactory) CreateAdjuster(adjusterType AdjusterType, cfg config.Config) (FeeAdjuster, error) {
 	switch adjusterType {
@@ -67,6 +75,7 @@ func (f *AdjusterFactory) CreateAdjuster(adjusterType AdjusterType, cfg config.C
 			MinIntegral:     -1000.0,
 			MaxFeeChange:    0.25,
 			WindowSize:      3,
+			Logger:          f.logger,
 		}
 		return NewPIDFeeAdjuster(pidConfig), nil
 
@@ -88,6 +97,7 @@ func (f *AdjusterFactory) CreateAdjusterWithConfigs(adjusterType AdjusterType, c
 
 	case AdjusterTypePID:
 		pidConfig := ConvertToPIDConfig(cfg)
+		pidConfig.Logger = f.logger
 		return NewPIDFeeAdjuster(pidConfig), nil
 
 	default:
@@ -1,8 +1,10 @@
 package simulator
 
 import (
-	"fmt"
+	"log/slog"
 	"math"
+
+	"github.com/brianbland/feemarketsim/pkg/logging"
 )
 
 // PIDConfig holds configuration specific to PID controller
@@ -24,6 +26,8 @@ type PIDConfig struct {
 	// Output limits
 	MaxFeeChange float64 // Maximum fee change per block (as ratio)
 	WindowSize   int     // Window for derivative calculation
+
+	Logger *slog.Logger // Receives the effective learning rate at debug level; nil discards
 }
 
 // DefaultPIDConfig returns the default PID configuration
@@ -205,17 +209,16 @@ func (fa *PIDFeeAdjuster) GetCurrentState() State {
 		} else {
 			baseFeeChange = float64(prevBlock.BaseFee-lastBlock.BaseFee) / float64(prevBlock.BaseFee)
 		}
-		fmt.Printf("Base fee change: %f\n", baseFeeChange)
 
 		// Calculate excess utilization
 		excessUtilization := (float64(lastBlock.GasUsed) - float64(fa.config.TargetBlockSize)) / float64(fa.config.TargetBlockSize)
-		fmt.Printf("Excess utilization: %f\n", excessUtilization)
 
 		// Effective learning rate is the ratio of base fee change to utilization change
 		if math.Abs(excessUtilization) > 1e-10 {
 			effectiveLearningRate = math.Abs(baseFeeChange / excessUtilization)
-			fmt.Printf("Effective learning rate: %f\n", effectiveLearningRate)
 		}
+		logging.OrDiscard(fa.config.Logger).Debug("PID effective learning rate", "base_fee_change", baseFeeChange,
+			"excess_utilization", excessUtilization, "learning_rate", effectiveLearningRate)
 	}
 
 	return State{
@@ -0,0 +1,22 @@
+package simulator
+
+import (
+	"fmt"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+// SimulateBaseFees runs a gas usage sequence through a fresh adjuster and returns the base fee after each block
+func SimulateBaseFees(adjusterType AdjusterType, cfg config.Config, blocks []uint64) ([]uint64, error) {
+	adjuster, err := NewAdjusterFactory().CreateAdjusterWithConfigs(adjusterType, &cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create adjuster: %w", err)
+	}
+
+	baseFees := make([]uint64, len(blocks))
+	for i, gasUsed := range blocks {
+		adjuster.ProcessBlock(gasUsed)
+		baseFees[i] = adjuster.GetCurrentState().BaseFee
+	}
+	return baseFees, nil
+}
@@ -0,0 +1,131 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// maxExactWilcoxon is the largest number of non-zero differences for which the exact
+// signed-rank distribution is computed; larger samples use the normal approximation
+const maxExactWilcoxon = 50
+
+// Wilcoxon is the outcome of a two-sided Wilcoxon signed-rank test
+type Wilcoxon struct {
+	N      int     `json:"n"`      // Number of non-zero differences
+	WPlus  float64 `json:"wPlus"`  // Sum of the ranks of the positive differences
+	PValue float64 `json:"pValue"` // Two-sided p-value
+	Exact  bool    `json:"exact"`  // Whether the p-value comes from the exact distribution
+}
+
+// WilcoxonSignedRank tests whether paired differences are symmetric around zero.
+// Zero differences are dropped and tied magnitudes share their average rank.
+func WilcoxonSignedRank(differences []float64) Wilcoxon {
+	var nonZero []float64
+	for _, d := range differences {
+		if d != 0 {
+			nonZero = append(nonZero, d)
+		}
+	}
+	n := len(nonZero)
+	if n == 0 {
+		return Wilcoxon{PValue: 1, Exact: true}
+	}
+
+	sort.Slice(nonZero, func(i, j int) bool { return math.Abs(nonZero[i]) < math.Abs(nonZero[j]) })
+
+	// Average ranks over runs of equal magnitude, remembering tie sizes for the variance
+	ranks := make([]float64, n)
+	var tieCorrection float64
+	for i := 0; i < n; {
+		j := i
+		for j < n && math.Abs(nonZero[j]) == math.Abs(nonZero[i]) {
+			j++
+		}
+		for k := i; k < j; k++ {
+			ranks[k] = float64(i+j+1) / 2
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	result := Wilcoxon{N: n}
+	for i, d := range nonZero {
+		if d > 0 {
+			result.WPlus += ranks[i]
+		}
+	}
+
+	if n <= maxExactWilcoxon {
+		result.PValue = exactWilcoxonP(ranks, result.WPlus)
+		result.Exact = true
+		return result
+	}
+
+	nf := float64(n)
+	mean := nf * (nf + 1) / 4
+	variance := nf*(nf+1)*(2*nf+1)/24 - tieCorrection/48
+	if variance <= 0 {
+		result.PValue = 1
+		return result
+	}
+	z := math.Max(math.Abs(result.WPlus-mean)-0.5, 0) / math.Sqrt(variance)
+	result.PValue = math.Min(math.Erfc(z/math.Sqrt2), 1)
+	return result
+}
+
+// exactWilcoxonP returns the two-sided p-value of wPlus under the null distribution of the
+// signed-rank statistic for the given ranks. Ranks are doubled so that tied half ranks stay integers.
+func exactWilcoxonP(ranks []float64, wPlus float64) float64 {
+	total := 0
+	doubled := make([]int, len(ranks))
+	for i, r := range ranks {
+		doubled[i] = int(math.Round(2 * r))
+		total += doubled[i]
+	}
+
+	// counts[s] is the number of sign assignments whose positive doubled ranks sum to s
+	counts := make([]float64, total+1)
+	counts[0] = 1
+	for _, r := range doubled {
+		for s := total; s >= r; s-- {
+			counts[s] += counts[s-r]
+		}
+	}
+
+	observed := int(math.Round(2 * wPlus))
+	var lower, upper, all float64
+	for s, count := range counts {
+		all += count
+		if s <= observed {
+			lower += count
+		}
+		if s >= observed {
+			upper += count
+		}
+	}
+	return math.Min(2*math.Min(lower, upper)/all, 1)
+}
+
+// BootstrapMeanCI returns a percentile bootstrap confidence interval for the mean of values,
+// drawing resamples from rng
+func BootstrapMeanCI(values []float64, resamples int, confidence float64, rng *rand.Rand) (float64, float64) {
+	if len(values) < 2 || resamples <= 0 {
+		mean := Mean(values)
+		return mean, mean
+	}
+
+	means := make([]float64, resamples)
+	for r := range means {
+		var sum float64
+		for range values {
+			sum += values[rng.Intn(len(values))]
+		}
+		means[r] = sum / float64(len(values))
+	}
+	sort.Float64s(means)
+
+	tail := (1 - confidence) / 2 * 100
+	return percentileSorted(means, tail), percentileSorted(means, 100-tail)
+}
@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestWilcoxonSignedRankExact(t *testing.T) {
+	// Paired depression scores before and after treatment (Hollander and Wolfe)
+	before := []float64{1.83, 0.50, 1.62, 2.48, 1.68, 1.88, 1.55, 3.06, 1.30}
+	after := []float64{0.878, 0.647, 0.598, 2.05, 1.06, 1.29, 1.06, 3.14, 1.29}
+	differences := make([]float64, len(before))
+	for i := range before {
+		differences[i] = before[i] - after[i]
+	}
+
+	result := WilcoxonSignedRank(differences)
+	if !result.Exact || result.N != 9 || result.WPlus != 40 {
+		t.Errorf("Expected exact test with n=9 and W+=40, got %+v", result)
+	}
+	if math.Abs(result.PValue-0.0390625) > 1e-9 {
+		t.Errorf("Expected p=0.0390625, got %f", result.PValue)
+	}
+}
+
+func TestWilcoxonSignedRankEdgeCases(t *testing.T) {
+	if result := WilcoxonSignedRank([]float64{0, 0, 0}); result.PValue != 1 || result.N != 0 {
+		t.Errorf("All-zero differences should give p=1, got %+v", result)
+	}
+
+	// Ten positive differences: only one of 1024 sign assignments is as extreme in each direction
+	positive := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	if result := WilcoxonSignedRank(positive); math.Abs(result.PValue-2.0/1024) > 1e-12 {
+		t.Errorf("Expected p=%f, got %f", 2.0/1024, result.PValue)
+	}
+
+	// Ties share ranks and the p-value stays a probability
+	if result := WilcoxonSignedRank([]float64{1, -1, 1, -1, 2}); result.PValue <= 0 || result.PValue > 1 {
+		t.Errorf("Expected a p-value in (0, 1], got %f", result.PValue)
+	}
+}
+
+func TestWilcoxonSignedRankNormalApproximation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	shifted := make([]float64, 200)
+	symmetric := make([]float64, 200)
+	for i := range shifted {
+		noise := rng.NormFloat64()
+		shifted[i] = noise + 1
+		symmetric[i] = noise
+	}
+
+	if result := WilcoxonSignedRank(shifted); result.Exact || result.PValue > 1e-6 {
+		t.Errorf("Expected a tiny approximate p-value for shifted differences, got %+v", result)
+	}
+	if result := WilcoxonSignedRank(symmetric); result.PValue < 0.01 {
+		t.Errorf("Expected no significance for symmetric noise, got p=%f", result.PValue)
+	}
+}
+
+func TestBootstrapMeanCI(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	values := make([]float64, 100)
+	for i := range values {
+		values[i] = 5 + rng.NormFloat64()
+	}
+
+	low, high := BootstrapMeanCI(values, 2000, 0.95, rand.New(rand.NewSource(1)))
+	mean := Mean(values)
+	if !(low < mean && mean < high) {
+		t.Errorf("Interval [%f, %f] should contain the mean %f", low, high, mean)
+	}
+	// The standard error is about 0.1, so a 95% interval spans roughly 0.4
+	if width := high - low; width < 0.25 || width > 0.6 {
+		t.Errorf("Unexpected interval width %f", width)
+	}
+
+	if low, high := BootstrapMeanCI([]float64{3}, 100, 0.95, rng); low != 3 || high != 3 {
+		t.Errorf("A single value should give a degenerate interval, got [%f, %f]", low, high)
+	}
+}
@@ -0,0 +1,145 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes the distribution of a metric across runs
+type Summary struct {
+	Count  int     `json:"count"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stdDev"`
+	Min    float64 `json:"min"`
+	P5     float64 `json:"p5"`
+	P25    float64 `json:"p25"`
+	P50    float64 `json:"p50"`
+	P75    float64 `json:"p75"`
+	P95    float64 `json:"p95"`
+	Max    float64 `json:"max"`
+}
+
+// Summarize computes the mean, standard deviation and percentile bands of values
+func Summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	return Summary{
+		Count:  len(values),
+		Mean:   Mean(values),
+		StdDev: StdDev(values),
+		Min:    sorted[0],
+		P5:     percentileSorted(sorted, 5),
+		P25:    percentileSorted(sorted, 25),
+		P50:    percentileSorted(sorted, 50),
+		P75:    percentileSorted(sorted, 75),
+		P95:    percentileSorted(sorted, 95),
+		Max:    sorted[len(sorted)-1],
+	}
+}
+
+// Mean returns the arithmetic mean of values
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev returns the sample standard deviation of values
+func StdDev(values []float64) float64 {
+	if len(values) <= 1 {
+		return 0
+	}
+
+	mean := Mean(values)
+	var sumSquares float64
+	for _, v := range values {
+		diff := v - mean
+		sumSquares += diff * diff
+	}
+	return math.Sqrt(sumSquares / float64(len(values)-1))
+}
+
+// Percentile returns the p-th percentile (0-100) of values using linear interpolation
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return percentileSorted(sorted, p)
+}
+
+// percentileSorted returns the p-th percentile of already sorted values
+func percentileSorted(sorted []float64, p float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	fraction := rank - float64(lower)
+	return sorted[lower] + fraction*(sorted[upper]-sorted[lower])
+}
+
+// Correlation returns the Pearson correlation of two equal-length series, or 0 if either is constant
+func Correlation(x, y []float64) float64 {
+	if len(x) != len(y) || len(x) < 2 {
+		return 0
+	}
+
+	meanX, meanY := Mean(x), Mean(y)
+	var cov, varX, varY float64
+	for i := range x {
+		dx, dy := x[i]-meanX, y[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// Gini returns the Gini coefficient of non-negative values: 0 when all are equal,
+// approaching 1 when a single value holds the whole total
+func Gini(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	var total, weighted float64
+	for i, v := range sorted {
+		total += v
+		weighted += float64(i+1) * v
+	}
+	if total == 0 {
+		return 0
+	}
+
+	n := float64(len(sorted))
+	return 2*weighted/(n*total) - (n+1)/n
+}
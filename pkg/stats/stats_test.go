@@ -0,0 +1,70 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	values := []float64{5, 1, 4, 2, 3}
+	summary := Summarize(values)
+
+	if summary.Count != 5 {
+		t.Errorf("Expected count 5, got %d", summary.Count)
+	}
+	if summary.Mean != 3 {
+		t.Errorf("Expected mean 3, got %f", summary.Mean)
+	}
+	if math.Abs(summary.StdDev-math.Sqrt(2.5)) > 1e-12 {
+		t.Errorf("Expected std dev %f, got %f", math.Sqrt(2.5), summary.StdDev)
+	}
+	if summary.Min != 1 || summary.Max != 5 || summary.P50 != 3 {
+		t.Errorf("Unexpected min/median/max: %f/%f/%f", summary.Min, summary.P50, summary.Max)
+	}
+	if summary.P25 != 2 || summary.P75 != 4 {
+		t.Errorf("Unexpected quartiles: %f/%f", summary.P25, summary.P75)
+	}
+
+	// Input must not be reordered
+	if values[0] != 5 {
+		t.Error("Summarize modified its input")
+	}
+}
+
+func TestPercentileInterpolation(t *testing.T) {
+	values := []float64{0, 10}
+	if got := Percentile(values, 25); got != 2.5 {
+		t.Errorf("Expected 2.5, got %f", got)
+	}
+	if got := Percentile(nil, 50); got != 0 {
+		t.Errorf("Expected 0 for empty input, got %f", got)
+	}
+	if got := Percentile([]float64{7}, 95); got != 7 {
+		t.Errorf("Expected 7 for single value, got %f", got)
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	x := []float64{1, 2, 3, 4, 5}
+	if r := Correlation(x, []float64{2, 4, 6, 8, 10}); math.Abs(r-1) > 1e-12 {
+		t.Errorf("Expected perfect correlation, got %f", r)
+	}
+	if r := Correlation(x, []float64{5, 4, 3, 2, 1}); math.Abs(r+1) > 1e-12 {
+		t.Errorf("Expected perfect anti-correlation, got %f", r)
+	}
+	if r := Correlation(x, []float64{3, 3, 3, 3, 3}); r != 0 {
+		t.Errorf("Expected 0 for a constant series, got %f", r)
+	}
+}
+
+func TestGini(t *testing.T) {
+	if g := Gini([]float64{4, 4, 4, 4}); g != 0 {
+		t.Errorf("Expected 0 for equal values, got %f", g)
+	}
+	if g := Gini([]float64{0, 0, 0, 8}); math.Abs(g-0.75) > 1e-12 {
+		t.Errorf("Expected 0.75 when one of four values holds everything, got %f", g)
+	}
+	if g := Gini(nil); g != 0 {
+		t.Errorf("Expected 0 for empty input, got %f", g)
+	}
+}
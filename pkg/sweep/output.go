@@ -0,0 +1,36 @@
+package sweep
+
+import (
+	"github.com/brianbland/feemarketsim/pkg/output"
+)
+
+// Table returns one row per evaluation: point, parameters, case, error, then every metric.
+// Metrics a case does not report are left empty.
+func Table(params []Param, rows []Row) *output.Table {
+	metricNames := MetricNames()
+
+	columns := []string{"point"}
+	for _, param := range params {
+		columns = append(columns, param.Name)
+	}
+	columns = append(columns, "case", "error")
+	columns = append(columns, metricNames...)
+
+	table := output.NewTable("sweep", "Sweep", columns...)
+	for _, row := range rows {
+		values := []interface{}{row.Point}
+		for _, param := range params {
+			values = append(values, row.Params[param.Name])
+		}
+		values = append(values, row.Case, row.Error)
+		for _, name := range metricNames {
+			if value, ok := row.Metrics[name]; ok {
+				values = append(values, value)
+			} else {
+				values = append(values, nil)
+			}
+		}
+		table.Append(values...)
+	}
+	return table
+}
@@ -0,0 +1,232 @@
+package sweep
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+// Param is a range of values for one parameter, identified by its command-line flag name
+type Param struct {
+	Name       string
+	Values     []float64 // Grid values in ascending order
+	Min        float64   // Lower bound for random sampling
+	Max        float64   // Upper bound for random sampling
+	Log        bool      // Sample log-uniformly between Min and Max
+	Continuous bool      // Random sampling draws from [Min, Max] rather than picking from Values
+}
+
+// ParseParam parses a parameter range of the form name=spec, where spec is one of
+//
+//	start:stop:step   inclusive linear steps, e.g. aimd-gamma=0.05:0.5:0.05
+//	min:max           bounds only, sampled or tuned continuously, e.g. aimd-gamma=0.05:0.5
+//	log(min,max,n)    n log-spaced values, e.g. pid-kp=log(0.001,1,20)
+//	lin(min,max,n)    n evenly spaced values, e.g. aimd-beta=lin(0.5,0.95,10)
+//	v1,v2,...         an explicit list, e.g. window-size=5,10,20
+//
+// Gas and fee parameters accept units, e.g. target-block-size=10M:30M:5M or
+// initial-base-fee=log(0.01gwei,10gwei,10).
+func ParseParam(text string) (Param, error) {
+	name, spec, found := strings.Cut(text, "=")
+	name = strings.TrimPrefix(strings.TrimSpace(name), "-")
+	spec = strings.ReplaceAll(spec, " ", "")
+	if !found || name == "" || spec == "" {
+		return Param{}, fmt.Errorf("invalid parameter range %q, expected name=range", text)
+	}
+
+	if !config.IsParam(name) {
+		return Param{}, fmt.Errorf("unknown numeric parameter: %s", name)
+	}
+
+	param := Param{Name: name}
+	var err error
+	switch {
+	case strings.HasPrefix(spec, "log(") && strings.HasSuffix(spec, ")"):
+		param.Log = true
+		param.Continuous = true
+		param.Values, err = spaced(name, spec[4:len(spec)-1], true)
+	case strings.HasPrefix(spec, "lin(") && strings.HasSuffix(spec, ")"):
+		param.Continuous = true
+		param.Values, err = spaced(name, spec[4:len(spec)-1], false)
+	case strings.Count(spec, ":") == 2:
+		param.Continuous = true
+		param.Values, err = stepped(name, spec)
+	case strings.Count(spec, ":") == 1:
+		param.Continuous = true
+		param.Values, err = bounds(name, spec)
+	default:
+		param.Values, err = list(name, spec)
+	}
+	if err != nil {
+		return Param{}, fmt.Errorf("invalid range for %s: %w", name, err)
+	}
+
+	param.Min = param.Values[0]
+	param.Max = param.Values[len(param.Values)-1]
+	return param, nil
+}
+
+// ParseParams parses every parameter range and rejects duplicate names
+func ParseParams(texts []string) ([]Param, error) {
+	params := make([]Param, 0, len(texts))
+	seen := make(map[string]bool)
+	for _, text := range texts {
+		param, err := ParseParam(text)
+		if err != nil {
+			return nil, err
+		}
+		if seen[param.Name] {
+			return nil, fmt.Errorf("parameter %s is swept more than once", param.Name)
+		}
+		seen[param.Name] = true
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// Sample draws a random value from the parameter's range
+func (p Param) Sample(rng *rand.Rand) float64 {
+	if !p.Continuous {
+		return p.Values[rng.Intn(len(p.Values))]
+	}
+	return p.FromUnit(rng.Float64())
+}
+
+// FromUnit maps u in [0, 1] to a value between Min and Max, on a log scale for log ranges
+func (p Param) FromUnit(u float64) float64 {
+	if p.Log {
+		return math.Exp(math.Log(p.Min) + u*(math.Log(p.Max)-math.Log(p.Min)))
+	}
+	return p.Min + u*(p.Max-p.Min)
+}
+
+// ToUnit maps a value between Min and Max to [0, 1], the inverse of FromUnit
+func (p Param) ToUnit(value float64) float64 {
+	var u float64
+	switch {
+	case p.Max == p.Min:
+		return 0
+	case p.Log:
+		u = (math.Log(value) - math.Log(p.Min)) / (math.Log(p.Max) - math.Log(p.Min))
+	default:
+		u = (value - p.Min) / (p.Max - p.Min)
+	}
+	return math.Min(math.Max(u, 0), 1)
+}
+
+// Flags formats parameter values as command-line flags
+func Flags(params []Param, values []float64) string {
+	flags := make([]string, len(params))
+	for i, param := range params {
+		flags[i] = fmt.Sprintf("-%s=%.6g", param.Name, values[i])
+	}
+	return strings.Join(flags, " ")
+}
+
+// stepped expands start:stop:step into an inclusive sequence
+func stepped(name, spec string) ([]float64, error) {
+	parts := strings.Split(spec, ":")
+	numbers, err := parseNumbers(name, parts)
+	if err != nil {
+		return nil, err
+	}
+	start, stop, step := numbers[0], numbers[1], numbers[2]
+	if step <= 0 {
+		return nil, fmt.Errorf("step (%g) must be positive", step)
+	}
+	if stop < start {
+		return nil, fmt.Errorf("stop (%g) must be >= start (%g)", stop, start)
+	}
+
+	// The small tolerance keeps stop in the range despite floating point error
+	count := int(math.Floor((stop-start)/step+1e-9)) + 1
+	values := make([]float64, count)
+	for i := range values {
+		values[i] = tidy(start + float64(i)*step)
+	}
+	return values, nil
+}
+
+// bounds parses min:max into its two endpoints
+func bounds(name, spec string) ([]float64, error) {
+	numbers, err := parseNumbers(name, strings.Split(spec, ":"))
+	if err != nil {
+		return nil, err
+	}
+	if numbers[1] < numbers[0] {
+		return nil, fmt.Errorf("max (%g) must be >= min (%g)", numbers[1], numbers[0])
+	}
+	return numbers, nil
+}
+
+// spaced expands min,max,n into n linearly or logarithmically spaced values
+func spaced(name, args string, logarithmic bool) ([]float64, error) {
+	parts := strings.Split(args, ",")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("expected (min,max,n), got (%s)", args)
+	}
+	numbers, err := parseNumbers(name, parts[:2])
+	if err != nil {
+		return nil, err
+	}
+	lower, upper := numbers[0], numbers[1]
+	n, err := strconv.Atoi(parts[2])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("count %q must be a positive integer", parts[2])
+	}
+	if upper < lower {
+		return nil, fmt.Errorf("max (%g) must be >= min (%g)", upper, lower)
+	}
+	if logarithmic && lower <= 0 {
+		return nil, fmt.Errorf("log range min (%g) must be positive", lower)
+	}
+	if n == 1 {
+		return []float64{lower}, nil
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		fraction := float64(i) / float64(n-1)
+		if logarithmic {
+			values[i] = tidy(lower * math.Pow(upper/lower, fraction))
+		} else {
+			values[i] = tidy(lower + fraction*(upper-lower))
+		}
+	}
+	return values, nil
+}
+
+// list parses a comma-separated list of values
+func list(name, spec string) ([]float64, error) {
+	values, err := parseNumbers(name, strings.Split(spec, ","))
+	if err != nil {
+		return nil, err
+	}
+	sort.Float64s(values)
+	return values, nil
+}
+
+// parseNumbers parses every string as a value of the named parameter, so gas and fee
+// parameters accept units such as 15M or 0.5gwei
+func parseNumbers(name string, parts []string) ([]float64, error) {
+	numbers := make([]float64, len(parts))
+	for i, part := range parts {
+		number, err := config.ParseParamValue(name, part)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+// tidy rounds away floating point noise such as 0.15000000000000002
+func tidy(value float64) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'g', 12, 64), 64)
+	return rounded
+}
@@ -0,0 +1,101 @@
+package sweep
+
+import (
+	"fmt"
+
+	"github.com/brianbland/feemarketsim/pkg/analysis"
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/simulator"
+)
+
+// Case is one workload a configuration is evaluated on: a synthetic scenario or a blockchain dataset
+type Case struct {
+	Name         string
+	Scenario     string              // Built-in scenario name, regenerated for each configuration
+	ScenarioFile string              // Scenario file, loaded for each configuration
+	DataSet      *blockchain.DataSet // Blockchain dataset, loaded once
+}
+
+// IsDataSet reports whether the case replays a blockchain dataset
+func (c Case) IsDataSet() bool {
+	return c.DataSet != nil
+}
+
+// Cases returns the workloads selected by the configuration: the scenarios chosen by
+// -scenario or -scenario-file followed by every -dataset file
+func Cases(cfg config.Config) ([]Case, error) {
+	var cases []Case
+
+	switch {
+	case cfg.Simulation.ScenarioFile != "":
+		cases = append(cases, Case{Name: cfg.Simulation.ScenarioFile, ScenarioFile: cfg.Simulation.ScenarioFile})
+	case cfg.Simulation.Scenario == "all":
+		for _, name := range []string{"full", "empty", "stable", "mixed"} {
+			cases = append(cases, Case{Name: name, Scenario: name})
+		}
+	case cfg.Simulation.Scenario != "none":
+		cases = append(cases, Case{Name: cfg.Simulation.Scenario, Scenario: cfg.Simulation.Scenario})
+	}
+
+	for _, filename := range cfg.Simulation.DataSets {
+		dataset, err := blockchain.LoadDataSetFromFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load dataset %s: %w", filename, err)
+		}
+		if err := blockchain.ValidateDataSet(dataset); err != nil {
+			return nil, fmt.Errorf("invalid dataset %s: %w", filename, err)
+		}
+		cases = append(cases, Case{Name: filename, DataSet: dataset})
+	}
+
+	if len(cases) == 0 {
+		return nil, fmt.Errorf("no scenarios or datasets selected")
+	}
+	return cases, nil
+}
+
+// MetricNames lists every metric an evaluation can report, in output order.
+// Dataset-only metrics are absent from scenario evaluations.
+func MetricNames() []string {
+	names := append([]string{}, analysis.MetricNames...)
+	return append(names, blockchain.MetricNames...)
+}
+
+// EvaluateCase runs the configured adjuster on a single case and returns its metrics
+func EvaluateCase(cfg config.Config, c Case) (map[string]float64, error) {
+	adjusterType, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.IsDataSet() {
+		sim := blockchain.NewSimulator(cfg, adjusterType)
+		sim.SetQuiet(true)
+		simResult, analysisResult, err := sim.SimulateAgainstDataSet(c.DataSet)
+		if err != nil {
+			return nil, err
+		}
+
+		metrics := analysisResult.Metrics()
+		for name, value := range simResult.Metrics() {
+			metrics[name] = value
+		}
+		return metrics, nil
+	}
+
+	// Scenarios depend on the configuration (e.g. target block size), so build them per point
+	cfg.Simulation.Scenario = c.Scenario
+	cfg.Simulation.ScenarioFile = c.ScenarioFile
+	selected, err := scenarios.NewGenerator(cfg.Simulation).Select(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) != 1 {
+		return nil, fmt.Errorf("case %s selected %d scenarios, expected 1", c.Name, len(selected))
+	}
+
+	result := analysis.NewAnalyzer(cfg).RunDetailedAnalysis(selected[0])
+	return result.Metrics(), nil
+}
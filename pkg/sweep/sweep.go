@@ -0,0 +1,215 @@
+package sweep
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"sync"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+// Row holds the metrics of one parameter point on one case
+type Row struct {
+	Point   int                `json:"point"`
+	Params  map[string]float64 `json:"params"`
+	Case    string             `json:"case"`
+	Metrics map[string]float64 `json:"metrics,omitempty"`
+	Error   string             `json:"error,omitempty"` // Set when the point is not a valid configuration
+}
+
+// Sweep evaluates a set of parameter points across a suite of cases
+type Sweep struct {
+	config  config.Config
+	params  []Param
+	cases   []Case
+	workers int
+}
+
+// New creates a sweep over the given parameters and the cases selected by the configuration
+func New(cfg config.Config, params []Param) (*Sweep, error) {
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no parameters to sweep")
+	}
+
+	cases, err := Cases(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	workers := cfg.Simulation.Sweep.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	return &Sweep{
+		config:  cfg,
+		params:  params,
+		cases:   cases,
+		workers: workers,
+	}, nil
+}
+
+// Params returns the swept parameters
+func (s *Sweep) Params() []Param {
+	return s.params
+}
+
+// Cases returns the cases every point is evaluated on
+func (s *Sweep) Cases() []Case {
+	return s.cases
+}
+
+// Points returns the parameter values of every point, one value per parameter.
+// Grid mode enumerates every combination; random mode draws from the randomizer seed.
+func (s *Sweep) Points() [][]float64 {
+	if s.config.Simulation.Sweep.Mode == "random" {
+		rng := rand.New(rand.NewSource(s.config.Simulation.Randomizer.Seed))
+		points := make([][]float64, s.config.Simulation.Sweep.Samples)
+		for i := range points {
+			points[i] = make([]float64, len(s.params))
+			for j, param := range s.params {
+				points[i][j] = param.Sample(rng)
+			}
+		}
+		return points
+	}
+	return Grid(s.params)
+}
+
+// Grid returns every combination of the parameters' grid values, varying the last parameter
+// fastest. Without parameters it returns a single empty point.
+func Grid(params []Param) [][]float64 {
+	points := [][]float64{{}}
+	for _, param := range params {
+		next := make([][]float64, 0, len(points)*len(param.Values))
+		for _, point := range points {
+			for _, value := range param.Values {
+				extended := append(append([]float64{}, point...), value)
+				next = append(next, extended)
+			}
+		}
+		points = next
+	}
+	return points
+}
+
+// Run evaluates every point in parallel and returns the rows in point and case order.
+// Every point uses the same randomizer seed, so differences between points come from
+// the parameters alone.
+func (s *Sweep) Run(ctx context.Context) ([]Row, error) {
+	return s.RunPoints(ctx, s.Points())
+}
+
+// RunPoints evaluates the given points, one value per parameter, like Run
+func (s *Sweep) RunPoints(ctx context.Context, points [][]float64) ([]Row, error) {
+	rows := make([][]Row, len(points))
+
+	jobs := make(chan int, len(points))
+	for i := range points {
+		jobs <- i
+	}
+	close(jobs)
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				rows[i] = s.evaluatePoint(i, points[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var all []Row
+	for _, pointRows := range rows {
+		all = append(all, pointRows...)
+	}
+	return all, nil
+}
+
+// evaluatePoint applies the point to the configuration and evaluates it on every case
+func (s *Sweep) evaluatePoint(index int, values []float64) []Row {
+	cfg, applyErr := Apply(s.config, s.params, values)
+
+	// Record the applied values, which are rounded for integer parameters
+	params := make(map[string]float64, len(s.params))
+	for j, param := range s.params {
+		params[param.Name] = values[j]
+		if applied, err := config.GetParam(cfg, param.Name); err == nil {
+			params[param.Name] = applied
+		}
+	}
+
+	rows := make([]Row, len(s.cases))
+	for c, sweepCase := range s.cases {
+		rows[c] = Row{Point: index, Params: params, Case: sweepCase.Name}
+		if applyErr != nil {
+			rows[c].Error = applyErr.Error()
+			continue
+		}
+
+		metrics, err := EvaluateCase(cfg, sweepCase)
+		if err != nil {
+			rows[c].Error = err.Error()
+			continue
+		}
+		rows[c].Metrics = metrics
+	}
+	return rows
+}
+
+// MeanMetrics averages each point's metrics over its cases. Points with a failed case are nil.
+func MeanMetrics(rows []Row, points int) []map[string]float64 {
+	sums := make([]map[string]float64, points)
+	counts := make([]map[string]int, points)
+	failed := make([]bool, points)
+	for _, row := range rows {
+		if row.Error != "" {
+			failed[row.Point] = true
+			continue
+		}
+		if sums[row.Point] == nil {
+			sums[row.Point] = make(map[string]float64)
+			counts[row.Point] = make(map[string]int)
+		}
+		for name, value := range row.Metrics {
+			sums[row.Point][name] += value
+			counts[row.Point][name]++
+		}
+	}
+
+	for i := range sums {
+		if failed[i] {
+			sums[i] = nil
+			continue
+		}
+		for name := range sums[i] {
+			sums[i][name] /= float64(counts[i][name])
+		}
+	}
+	return sums
+}
+
+// Apply returns a copy of the configuration with the parameter values set and validated
+func Apply(cfg config.Config, params []Param, values []float64) (config.Config, error) {
+	for j, param := range params {
+		if err := config.SetParam(&cfg, param.Name, values[j]); err != nil {
+			return cfg, err
+		}
+	}
+	if err := config.Validate(cfg); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,205 @@
+package sweep
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+)
+
+func TestParseParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected []float64
+		log      bool
+		expectOK bool
+	}{
+		{"step range", "aimd-gamma=0.05:0.25:0.05", []float64{0.05, 0.1, 0.15, 0.2, 0.25}, false, true},
+		{"bounds", "aimd-gamma=0.1:0.4", []float64{0.1, 0.4}, false, true},
+		{"log range", "pid-kp=log(0.001,1,4)", []float64{0.001, 0.01, 0.1, 1}, true, true},
+		{"linear range", "aimd-beta=lin(0.5,0.9,3)", []float64{0.5, 0.7, 0.9}, false, true},
+		{"list", "window-size=20,5,10", []float64{5, 10, 20}, false, true},
+		{"leading dash", "-pid-kd=0.1", []float64{0.1}, false, true},
+		{"unknown parameter", "aimd-nope=1,2", nil, false, false},
+		{"non-numeric parameter", "scenario=1,2", nil, false, false},
+		{"negative step", "aimd-gamma=0.5:0.1:-0.1", nil, false, false},
+		{"log of zero", "pid-kp=log(0,1,5)", nil, false, false},
+		{"missing range", "pid-kp", nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			param, err := ParseParam(tt.text)
+			if !tt.expectOK {
+				if err == nil {
+					t.Errorf("Expected an error for %q", tt.text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(param.Values, tt.expected) {
+				t.Errorf("Expected values %v, got %v", tt.expected, param.Values)
+			}
+			if param.Log != tt.log {
+				t.Errorf("Expected log=%v, got %v", tt.log, param.Log)
+			}
+		})
+	}
+}
+
+func testConfig() config.Config {
+	cfg := config.Default()
+	cfg.Simulation.AdjusterType = "aimd"
+	cfg.Simulation.Scenario = "stable"
+	cfg.Simulation.Randomizer.Seed = 5
+	cfg.Simulation.Randomizer.GaussianNoise = 0.1
+	return cfg
+}
+
+func TestGridPoints(t *testing.T) {
+	params, err := ParseParams([]string{"aimd-gamma=0.1,0.2,0.3", "aimd-beta=0.8,0.9"})
+	if err != nil {
+		t.Fatalf("Failed to parse params: %v", err)
+	}
+	s, err := New(testConfig(), params)
+	if err != nil {
+		t.Fatalf("Failed to create sweep: %v", err)
+	}
+
+	points := s.Points()
+	if len(points) != 6 {
+		t.Fatalf("Expected 6 grid points, got %d", len(points))
+	}
+	if !reflect.DeepEqual(points[0], []float64{0.1, 0.8}) || !reflect.DeepEqual(points[5], []float64{0.3, 0.9}) {
+		t.Errorf("Unexpected grid order: %v", points)
+	}
+}
+
+func TestRandomPointsAreSeeded(t *testing.T) {
+	cfg := testConfig()
+	cfg.Simulation.Sweep.Mode = "random"
+	cfg.Simulation.Sweep.Samples = 20
+
+	params, err := ParseParams([]string{"pid-kp=log(0.001,1,2)"})
+	if err != nil {
+		t.Fatalf("Failed to parse params: %v", err)
+	}
+	first, _ := New(cfg, params)
+	second, _ := New(cfg, params)
+
+	points := first.Points()
+	if !reflect.DeepEqual(points, second.Points()) {
+		t.Error("Expected identical samples for the same seed")
+	}
+	for _, point := range points {
+		if point[0] < 0.001 || point[0] > 1 {
+			t.Errorf("Sample %f outside [0.001, 1]", point[0])
+		}
+	}
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	params, err := ParseParams([]string{"aimd-gamma=0.1:0.5:0.1", "aimd-beta=0.5,1.5"})
+	if err != nil {
+		t.Fatalf("Failed to parse params: %v", err)
+	}
+
+	run := func(workers int) []Row {
+		cfg := testConfig()
+		cfg.Simulation.Sweep.Workers = workers
+		s, err := New(cfg, params)
+		if err != nil {
+			t.Fatalf("Failed to create sweep: %v", err)
+		}
+		rows, err := s.Run(context.Background())
+		if err != nil {
+			t.Fatalf("Sweep failed: %v", err)
+		}
+		return rows
+	}
+
+	serial, parallel := run(1), run(4)
+	if !reflect.DeepEqual(serial, parallel) {
+		t.Error("Expected identical rows regardless of worker count")
+	}
+
+	for _, row := range serial {
+		invalid := row.Params["aimd-beta"] > 1
+		if invalid && row.Error == "" {
+			t.Errorf("Expected a validation error for point %d with beta %f", row.Point, row.Params["aimd-beta"])
+		}
+		if !invalid && (row.Error != "" || len(row.Metrics) == 0) {
+			t.Errorf("Expected metrics for point %d, got error %q", row.Point, row.Error)
+		}
+	}
+}
+
+func TestDataSetCase(t *testing.T) {
+	dataset := &blockchain.DataSet{
+		StartBlock:      100,
+		EndBlock:        119,
+		InitialBaseFee:  1_000_000_000,
+		InitialGasLimit: 30_000_000,
+	}
+	for i := 0; i < 20; i++ {
+		dataset.Blocks = append(dataset.Blocks, blockchain.BlockData{
+			Number:  uint64(100 + i),
+			GasUsed: uint64(10_000_000 + i*1_000_000),
+			Transactions: []blockchain.Transaction{
+				{Gas: 21_000, GasUsed: 21_000, MaxFeePerGas: 1_050_000_000},
+			},
+		})
+	}
+
+	filename := filepath.Join(t.TempDir(), "dataset.json")
+	if err := blockchain.SaveDataSetToFile(dataset, filename); err != nil {
+		t.Fatalf("Failed to save dataset: %v", err)
+	}
+
+	cfg := testConfig()
+	cfg.Simulation.Scenario = "none"
+	cfg.Simulation.DataSets = []string{filename}
+
+	cases, err := Cases(cfg)
+	if err != nil {
+		t.Fatalf("Failed to build cases: %v", err)
+	}
+	if len(cases) != 1 || !cases[0].IsDataSet() {
+		t.Fatalf("Expected a single dataset case, got %+v", cases)
+	}
+
+	metrics, err := EvaluateCase(cfg, cases[0])
+	if err != nil {
+		t.Fatalf("Evaluation failed: %v", err)
+	}
+	if _, ok := metrics["dropped_tx_percent"]; !ok {
+		t.Error("Expected dataset metrics to include dropped_tx_percent")
+	}
+	if metrics["total_blocks"] != 20 {
+		t.Errorf("Expected 20 blocks, got %f", metrics["total_blocks"])
+	}
+}
+
+func TestParseParamUnits(t *testing.T) {
+	param, err := ParseParam("target-block-size=10M:30M:10M")
+	if err != nil {
+		t.Fatalf("ParseParam failed: %v", err)
+	}
+	if len(param.Values) != 3 || param.Values[0] != 10e6 || param.Values[2] != 30e6 {
+		t.Errorf("Unexpected gas values %v", param.Values)
+	}
+
+	param, err = ParseParam("initial-base-fee=log(0.01gwei,10gwei,4)")
+	if err != nil {
+		t.Fatalf("ParseParam failed: %v", err)
+	}
+	if param.Min != 1e7 || param.Max != 1e10 {
+		t.Errorf("Unexpected fee bounds %g - %g", param.Min, param.Max)
+	}
+}
@@ -0,0 +1,85 @@
+package tune
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Term is one weighted metric of an objective
+type Term struct {
+	Metric string
+	Weight float64
+}
+
+// Objective is a weighted sum of metrics to minimize
+type Objective []Term
+
+// ParseObjective parses a comma-separated list of metric:weight terms.
+// A metric without a weight gets weight 1; negative weights reward larger values.
+func ParseObjective(spec string) (Objective, error) {
+	known := make(map[string]bool)
+	for _, name := range sweep.MetricNames() {
+		known[name] = true
+	}
+
+	var objective Objective
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		metric, weightText, hasWeight := strings.Cut(part, ":")
+		weight := 1.0
+		if hasWeight {
+			var err error
+			weight, err = strconv.ParseFloat(strings.TrimSpace(weightText), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight %q for %s", weightText, metric)
+			}
+		}
+
+		metric = strings.TrimSpace(metric)
+		if !known[metric] {
+			return nil, fmt.Errorf("unknown objective metric: %s", metric)
+		}
+		objective = append(objective, Term{Metric: metric, Weight: weight})
+	}
+
+	if len(objective) == 0 {
+		return nil, fmt.Errorf("objective has no terms")
+	}
+	return objective, nil
+}
+
+// Score returns the weighted sum of the metrics, each divided by its magnitude in the
+// baseline so that metrics in wei and metrics in percent contribute comparably.
+// Metrics a case does not report (e.g. dropped transactions on a synthetic scenario) are skipped.
+func (o Objective) Score(metrics, baseline map[string]float64) float64 {
+	var score float64
+	for _, term := range o {
+		value, ok := metrics[term.Metric]
+		if !ok {
+			continue
+		}
+		scale := math.Abs(baseline[term.Metric])
+		if scale < 1e-12 {
+			scale = 1
+		}
+		score += term.Weight * value / scale
+	}
+	return score
+}
+
+// String formats the objective in the form accepted by ParseObjective
+func (o Objective) String() string {
+	parts := make([]string, len(o))
+	for i, term := range o {
+		parts[i] = fmt.Sprintf("%s:%g", term.Metric, term.Weight)
+	}
+	return strings.Join(parts, ",")
+}
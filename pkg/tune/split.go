@@ -0,0 +1,47 @@
+package tune
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Split splits each dataset case into contiguous block ranges, then holds out the last
+// fraction of the cases for validation. Holding out the latest block ranges tests whether
+// parameters tuned on earlier blocks carry over to later ones.
+func Split(cases []sweep.Case, holdout float64, segments int) ([]sweep.Case, []sweep.Case, error) {
+	var expanded []sweep.Case
+	for _, c := range cases {
+		if !c.IsDataSet() || segments <= 1 {
+			expanded = append(expanded, c)
+			continue
+		}
+
+		parts, err := blockchain.SplitDataSet(c.DataSet, segments)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to split %s: %w", c.Name, err)
+		}
+		for _, part := range parts {
+			expanded = append(expanded, sweep.Case{
+				Name:    fmt.Sprintf("%s[%d-%d]", c.Name, part.StartBlock, part.EndBlock),
+				DataSet: part,
+			})
+		}
+	}
+
+	held := int(math.Round(holdout * float64(len(expanded))))
+	if holdout > 0 && held == 0 {
+		held = 1
+	}
+	if held >= len(expanded) {
+		held = len(expanded) - 1
+	}
+	if held < 0 {
+		held = 0
+	}
+
+	cut := len(expanded) - held
+	return expanded[:cut], expanded[cut:], nil
+}
@@ -0,0 +1,422 @@
+package tune
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/output"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+// Result contains the outcome of a tuning run
+type Result struct {
+	AdjusterType string
+	Method       string
+	Objective    Objective
+	Params       []sweep.Param
+	Start        []float64 // Parameter values the search started from
+	Best         []float64 // Best parameter values found on the training cases
+
+	TrainCases   []string
+	HoldoutCases []string
+	StartTrain   float64 // Objective of the start point on the training cases
+	BestTrain    float64 // Objective of the best point on the training cases
+	StartHoldout float64 // Objective of the start point on the holdout cases (NaN without holdout)
+	BestHoldout  float64 // Objective of the best point on the holdout cases (NaN without holdout)
+
+	Evaluations int       // Number of objective evaluations
+	Invalid     int       // Evaluations of points that failed validation or simulation
+	Progress    []float64 // Best training objective after each evaluation
+}
+
+// Tuner searches adjuster parameters that minimize an objective over a set of cases
+type Tuner struct {
+	config    config.Config
+	tune      config.TuneConfig
+	params    []sweep.Param
+	objective Objective
+	train     []sweep.Case
+	holdout   []sweep.Case
+	baseline  map[string]map[string]float64 // Metrics of the start point, by case name
+	start     []float64
+	rng       *rand.Rand
+
+	evaluations int
+	invalid     int
+	best        []float64
+	bestScore   float64
+	progress    []float64
+}
+
+// NewTuner creates a tuner for the parameters, objective and cases selected by the configuration
+func NewTuner(cfg config.Config) (*Tuner, error) {
+	params, err := sweep.ParseParams(cfg.Simulation.Tune.Params)
+	if err != nil {
+		return nil, err
+	}
+	if len(params) == 0 {
+		return nil, fmt.Errorf("no parameters to tune")
+	}
+
+	objective, err := ParseObjective(cfg.Simulation.Tune.Objective)
+	if err != nil {
+		return nil, err
+	}
+
+	cases, err := sweep.Cases(cfg)
+	if err != nil {
+		return nil, err
+	}
+	train, holdout, err := Split(cases, cfg.Simulation.Tune.Holdout, cfg.Simulation.Tune.Segments)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start from the configured values, clamped into the bounds
+	start := make([]float64, len(params))
+	for i, param := range params {
+		value, err := config.GetParam(cfg, param.Name)
+		if err != nil {
+			return nil, err
+		}
+		start[i] = math.Min(math.Max(value, param.Min), param.Max)
+	}
+
+	return &Tuner{
+		config:    cfg,
+		tune:      cfg.Simulation.Tune,
+		params:    params,
+		objective: objective,
+		train:     train,
+		holdout:   holdout,
+		start:     start,
+		rng:       rand.New(rand.NewSource(cfg.Simulation.Randomizer.Seed)),
+	}, nil
+}
+
+// Run measures the start point, searches the parameter space on the training cases,
+// and scores the start and best points on the holdout cases
+func (t *Tuner) Run(ctx context.Context) (Result, error) {
+	startCfg, err := sweep.Apply(t.config, t.params, t.start)
+	if err != nil {
+		return Result{}, fmt.Errorf("start point is not a valid configuration: %w", err)
+	}
+
+	t.baseline = make(map[string]map[string]float64)
+	for _, c := range append(append([]sweep.Case{}, t.train...), t.holdout...) {
+		metrics, err := sweep.EvaluateCase(startCfg, c)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to evaluate start point on %s: %w", c.Name, err)
+		}
+		t.baseline[c.Name] = metrics
+	}
+
+	t.bestScore = math.Inf(1)
+	objective := func(u []float64) float64 {
+		values := t.toValues(u)
+		score := t.score(t.train, values)
+
+		t.evaluations++
+		if math.IsInf(score, 1) {
+			t.invalid++
+		}
+		if score < t.bestScore {
+			t.bestScore = score
+			t.best = values
+		}
+		t.progress = append(t.progress, t.bestScore)
+		return score
+	}
+
+	startTrain := objective(t.toUnit(t.start))
+	switch t.tune.Method {
+	case "nelder-mead":
+		t.nelderMead(ctx, objective, startTrain)
+	case "random":
+		t.randomSearch(ctx, objective)
+	default:
+		return Result{}, fmt.Errorf("unknown tune method: %s", t.tune.Method)
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+	if t.best == nil {
+		t.best = t.start
+	}
+
+	result := Result{
+		AdjusterType: t.config.Simulation.AdjusterType,
+		Method:       t.tune.Method,
+		Objective:    t.objective,
+		Params:       t.params,
+		Start:        t.start,
+		Best:         t.best,
+		TrainCases:   caseNames(t.train),
+		HoldoutCases: caseNames(t.holdout),
+		StartTrain:   startTrain,
+		BestTrain:    t.bestScore,
+		StartHoldout: math.NaN(),
+		BestHoldout:  math.NaN(),
+		Evaluations:  t.evaluations,
+		Invalid:      t.invalid,
+		Progress:     t.progress,
+	}
+	if len(t.holdout) > 0 {
+		result.StartHoldout = t.score(t.holdout, t.start)
+		result.BestHoldout = t.score(t.holdout, t.best)
+	}
+	return result, nil
+}
+
+// score returns the mean objective over the cases, or +Inf when the point is invalid
+func (t *Tuner) score(cases []sweep.Case, values []float64) float64 {
+	cfg, err := sweep.Apply(t.config, t.params, values)
+	if err != nil {
+		return math.Inf(1)
+	}
+
+	scores := make([]float64, len(cases))
+	var wg sync.WaitGroup
+	for i, c := range cases {
+		wg.Add(1)
+		go func(i int, c sweep.Case) {
+			defer wg.Done()
+			metrics, err := sweep.EvaluateCase(cfg, c)
+			if err != nil {
+				scores[i] = math.Inf(1)
+				return
+			}
+			scores[i] = t.objective.Score(metrics, t.baseline[c.Name])
+		}(i, c)
+	}
+	wg.Wait()
+
+	var sum float64
+	for _, s := range scores {
+		sum += s
+	}
+	if math.IsNaN(sum) {
+		return math.Inf(1)
+	}
+	return sum / float64(len(scores))
+}
+
+// nelderMead runs the Nelder-Mead simplex method in the unit cube until the evaluation
+// budget is spent or the simplex collapses
+func (t *Tuner) nelderMead(ctx context.Context, f func([]float64) float64, startScore float64) {
+	const (
+		reflection  = 1.0
+		expansion   = 2.0
+		contraction = 0.5
+		shrink      = 0.5
+		initialStep = 0.25
+		tolerance   = 1e-6
+	)
+
+	type vertex struct {
+		point []float64
+		score float64
+	}
+
+	dims := len(t.params)
+	startUnit := t.toUnit(t.start)
+	simplex := []vertex{{point: startUnit, score: startScore}}
+	for d := 0; d < dims; d++ {
+		point := append([]float64{}, startUnit...)
+		if point[d]+initialStep <= 1 {
+			point[d] += initialStep
+		} else {
+			point[d] -= initialStep
+		}
+		simplex = append(simplex, vertex{point: point, score: f(point)})
+	}
+
+	budgetLeft := func() bool { return t.evaluations < t.tune.Iterations && ctx.Err() == nil }
+	evaluate := func(point []float64) vertex {
+		clamped := clampUnit(point)
+		return vertex{point: clamped, score: f(clamped)}
+	}
+
+	for budgetLeft() {
+		sort.SliceStable(simplex, func(i, j int) bool { return simplex[i].score < simplex[j].score })
+
+		// Stop once every vertex is within tolerance of the best one
+		var size float64
+		for _, v := range simplex[1:] {
+			for d := range v.point {
+				size = math.Max(size, math.Abs(v.point[d]-simplex[0].point[d]))
+			}
+		}
+		if size < tolerance {
+			return
+		}
+
+		// Centroid of every vertex except the worst
+		centroid := make([]float64, dims)
+		for _, v := range simplex[:dims] {
+			for d := range centroid {
+				centroid[d] += v.point[d] / float64(dims)
+			}
+		}
+		worst := simplex[dims]
+		along := func(coefficient float64) []float64 {
+			point := make([]float64, dims)
+			for d := range point {
+				point[d] = centroid[d] + coefficient*(centroid[d]-worst.point[d])
+			}
+			return point
+		}
+
+		reflected := evaluate(along(reflection))
+		switch {
+		case reflected.score < simplex[0].score:
+			if !budgetLeft() {
+				simplex[dims] = reflected
+				continue
+			}
+			expanded := evaluate(along(expansion))
+			if expanded.score < reflected.score {
+				simplex[dims] = expanded
+			} else {
+				simplex[dims] = reflected
+			}
+		case reflected.score < simplex[dims-1].score:
+			simplex[dims] = reflected
+		default:
+			if !budgetLeft() {
+				continue
+			}
+			contracted := evaluate(along(-contraction))
+			if contracted.score < worst.score {
+				simplex[dims] = contracted
+				continue
+			}
+			// Shrink every vertex towards the best one
+			for i := 1; i < len(simplex) && budgetLeft(); i++ {
+				point := make([]float64, dims)
+				for d := range point {
+					point[d] = simplex[0].point[d] + shrink*(simplex[i].point[d]-simplex[0].point[d])
+				}
+				simplex[i] = evaluate(point)
+			}
+		}
+	}
+}
+
+// randomSearch evaluates uniformly random points in the unit cube
+func (t *Tuner) randomSearch(ctx context.Context, f func([]float64) float64) {
+	for t.evaluations < t.tune.Iterations && ctx.Err() == nil {
+		point := make([]float64, len(t.params))
+		for d := range point {
+			point[d] = t.rng.Float64()
+		}
+		f(point)
+	}
+}
+
+// toValues maps a point in the unit cube to parameter values
+func (t *Tuner) toValues(u []float64) []float64 {
+	values := make([]float64, len(t.params))
+	for i, param := range t.params {
+		values[i] = param.FromUnit(u[i])
+	}
+	return values
+}
+
+// toUnit maps parameter values to a point in the unit cube
+func (t *Tuner) toUnit(values []float64) []float64 {
+	u := make([]float64, len(t.params))
+	for i, param := range t.params {
+		u[i] = param.ToUnit(values[i])
+	}
+	return u
+}
+
+// clampUnit clamps every coordinate into [0, 1]
+func clampUnit(point []float64) []float64 {
+	clamped := make([]float64, len(point))
+	for i, x := range point {
+		clamped[i] = math.Min(math.Max(x, 0), 1)
+	}
+	return clamped
+}
+
+// caseNames returns the names of the cases
+func caseNames(cases []sweep.Case) []string {
+	names := make([]string, len(cases))
+	for i, c := range cases {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// PrintResult prints the tuned parameters and train/holdout objectives
+func PrintResult(w io.Writer, result Result) {
+	fmt.Fprintf(w, "\n"+strings.Repeat("=", 80)+"\n")
+	fmt.Fprintf(w, "PARAMETER TUNING: %s (%s, %d evaluations)\n", result.AdjusterType, result.Method, result.Evaluations)
+	fmt.Fprintf(w, strings.Repeat("=", 80)+"\n")
+
+	fmt.Fprintf(w, "  Objective (minimized): %s\n", result.Objective)
+	fmt.Fprintf(w, "  Training cases: %s\n", strings.Join(result.TrainCases, ", "))
+	if len(result.HoldoutCases) > 0 {
+		fmt.Fprintf(w, "  Holdout cases: %s\n", strings.Join(result.HoldoutCases, ", "))
+	}
+	if result.Invalid > 0 {
+		fmt.Fprintf(w, "  Invalid points skipped: %d\n", result.Invalid)
+	}
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Parameter\tBounds\tStart\tBest")
+	for i, param := range result.Params {
+		scale := ""
+		if param.Log {
+			scale = " (log)"
+		}
+		fmt.Fprintf(tw, "%s\t[%g, %g]%s\t%.6g\t%.6g\n", param.Name, param.Min, param.Max, scale, result.Start[i], result.Best[i])
+	}
+	tw.Flush()
+
+	fmt.Fprintln(w)
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Objective\tStart\tBest\tChange")
+	fmt.Fprintf(tw, "Train\t%.6f\t%.6f\t%+.6f\n", result.StartTrain, result.BestTrain, result.BestTrain-result.StartTrain)
+	if len(result.HoldoutCases) > 0 {
+		fmt.Fprintf(tw, "Holdout\t%.6f\t%.6f\t%+.6f\n", result.StartHoldout, result.BestHoldout, result.BestHoldout-result.StartHoldout)
+	}
+	tw.Flush()
+
+	if len(result.HoldoutCases) > 0 && result.BestHoldout > result.StartHoldout {
+		fmt.Fprintf(w, "\n  Warning: the tuned parameters are worse than the start point on the holdout cases,\n")
+		fmt.Fprintf(w, "  so they likely overfit the training cases.\n")
+	}
+	fmt.Fprintf(w, "\n  Tuned flags: %s\n", sweep.Flags(result.Params, result.Best))
+}
+
+// ParamsTable returns the bounds, start and best value of every tuned parameter
+func ParamsTable(result Result) *output.Table {
+	table := output.NewTable("params", "Tuned Parameters", "param", "min", "max", "log", "start", "best")
+	for i, param := range result.Params {
+		table.Append(param.Name, param.Min, param.Max, param.Log, result.Start[i], result.Best[i])
+	}
+	return table
+}
+
+// ObjectiveTable returns the objective of the start and best points on the training cases, and
+// on the holdout cases when there are any
+func ObjectiveTable(result Result) *output.Table {
+	table := output.NewTable("objective", "Objective (minimized): "+result.Objective.String(), "cases", "start", "best", "change")
+	table.Append("train", result.StartTrain, result.BestTrain, result.BestTrain-result.StartTrain)
+	if len(result.HoldoutCases) > 0 {
+		table.Append("holdout", result.StartHoldout, result.BestHoldout, result.BestHoldout-result.StartHoldout)
+	}
+	return table
+}
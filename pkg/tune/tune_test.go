@@ -0,0 +1,117 @@
+package tune
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/sweep"
+)
+
+func TestParseObjective(t *testing.T) {
+	objective, err := ParseObjective("base_fee_volatility:2, responsiveness_score:-1,target_deviation")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := Objective{
+		{Metric: "base_fee_volatility", Weight: 2},
+		{Metric: "responsiveness_score", Weight: -1},
+		{Metric: "target_deviation", Weight: 1},
+	}
+	if !reflect.DeepEqual(objective, expected) {
+		t.Errorf("Expected %v, got %v", expected, objective)
+	}
+
+	for _, spec := range []string{"", "not_a_metric:1", "base_fee_volatility:abc"} {
+		if _, err := ParseObjective(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestObjectiveScore(t *testing.T) {
+	objective := Objective{
+		{Metric: "base_fee_volatility", Weight: 1},
+		{Metric: "responsiveness_score", Weight: -2},
+		{Metric: "dropped_tx_percent", Weight: 1},
+	}
+	baseline := map[string]float64{"base_fee_volatility": 1e8, "responsiveness_score": 0.5}
+	metrics := map[string]float64{"base_fee_volatility": 5e7, "responsiveness_score": 0.25}
+
+	// 0.5 from volatility, -1 from responsiveness, dropped transactions missing
+	if got := objective.Score(metrics, baseline); math.Abs(got-(-0.5)) > 1e-12 {
+		t.Errorf("Expected score -0.5, got %f", got)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	dataset := &blockchain.DataSet{StartBlock: 10, EndBlock: 17, InitialBaseFee: 1e9, InitialGasLimit: 30_000_000}
+	for i := 0; i < 8; i++ {
+		dataset.Blocks = append(dataset.Blocks, blockchain.BlockData{Number: uint64(10 + i), BaseFeePerGas: uint64(1e9 + i), GasLimit: 30_000_000})
+	}
+	cases := []sweep.Case{
+		{Name: "stable", Scenario: "stable"},
+		{Name: "data.json", DataSet: dataset},
+	}
+
+	train, holdout, err := Split(cases, 0.4, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(train) != 3 || len(holdout) != 2 {
+		t.Fatalf("Expected 3 training and 2 holdout cases, got %d and %d", len(train), len(holdout))
+	}
+	if holdout[1].Name != "data.json[16-17]" {
+		t.Errorf("Expected the last block range to be held out, got %s", holdout[1].Name)
+	}
+	if holdout[1].DataSet.InitialBaseFee != 1e9+6 {
+		t.Errorf("Expected segment to start from its first block's base fee, got %d", holdout[1].DataSet.InitialBaseFee)
+	}
+
+	// A single case is never held out entirely
+	train, holdout, _ = Split(cases[:1], 0.5, 1)
+	if len(train) != 1 || len(holdout) != 0 {
+		t.Errorf("Expected the only case to be used for training, got %d and %d", len(train), len(holdout))
+	}
+}
+
+func TestTunerImprovesTrainingObjective(t *testing.T) {
+	for _, method := range []string{"nelder-mead", "random"} {
+		t.Run(method, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Simulation.AdjusterType = "aimd"
+			cfg.Simulation.Randomizer.Seed = 3
+			cfg.Simulation.Tune.Method = method
+			cfg.Simulation.Tune.Iterations = 30
+			cfg.Simulation.Tune.Params = []string{"aimd-gamma=0.01:1", "aimd-beta=0.5:0.99"}
+			cfg.Simulation.Tune.Objective = "base_fee_volatility:1,responsiveness_score:-1"
+
+			tuner, err := NewTuner(cfg)
+			if err != nil {
+				t.Fatalf("Failed to create tuner: %v", err)
+			}
+			result, err := tuner.Run(context.Background())
+			if err != nil {
+				t.Fatalf("Tuning failed: %v", err)
+			}
+
+			if result.Evaluations > 30 {
+				t.Errorf("Expected at most 30 evaluations, got %d", result.Evaluations)
+			}
+			if result.BestTrain > result.StartTrain {
+				t.Errorf("Best training objective %f is worse than the start %f", result.BestTrain, result.StartTrain)
+			}
+			if len(result.HoldoutCases) != 1 || math.IsNaN(result.BestHoldout) {
+				t.Errorf("Expected one scored holdout case, got %v (%f)", result.HoldoutCases, result.BestHoldout)
+			}
+			for i := 1; i < len(result.Progress); i++ {
+				if result.Progress[i] > result.Progress[i-1] {
+					t.Fatalf("Progress must not get worse: %v", result.Progress)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Package version reports the build version the Go toolchain records in the binary
+package version
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// Version returns the main module version and, for builds from a checkout, the VCS revision,
+// e.g. v1.2.0 or (devel) 3f2a9c1e0b7d+dirty. It is "unknown" without build information.
+func Version() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+
+	version := info.Main.Version
+	if version == "" {
+		version = "(devel)"
+	}
+
+	var revision string
+	var modified bool
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			revision = setting.Value
+		case "vcs.modified":
+			modified = setting.Value == "true"
+		}
+	}
+	if len(revision) > 12 {
+		revision = revision[:12]
+	}
+	// Pseudo-versions already end with the revision
+	if revision != "" && !strings.Contains(version, revision) {
+		version += " " + revision
+		if modified {
+			version += "+dirty"
+		}
+	}
+	return version
+}
@@ -0,0 +1,81 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/adversarial"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateAttackChart creates a chart overlaying honest and attacked base fees for each adjuster
+func (g *Generator) GenerateAttackChart(results []adversarial.Result, filename string) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no attack results to chart")
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "800px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("Adversarial Analysis: %s", results[0].ScenarioName),
+			Subtitle: fmt.Sprintf("Strategy: %s, attacker share: %.1f%%", results[0].Strategy, results[0].Share*100),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Block Number",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Base Fee (Gwei)",
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: opts.Bool(true),
+			Top:  "10%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "axis",
+		}),
+	)
+
+	for _, result := range results {
+		baselineData := make([]opts.LineData, len(result.BaselineBaseFees))
+		for i, fee := range result.BaselineBaseFees {
+			baselineData[i] = opts.LineData{Value: []interface{}{i + 1, float64(fee) / 1e9}}
+		}
+		attackedData := make([]opts.LineData, len(result.AttackedBaseFees))
+		for i, fee := range result.AttackedBaseFees {
+			attackedData[i] = opts.LineData{Value: []interface{}{i + 1, float64(fee) / 1e9}}
+		}
+
+		line.AddSeries(fmt.Sprintf("%s (honest)", result.AdjusterType), baselineData,
+			charts.WithLineStyleOpts(opts.LineStyle{
+				Width: 1,
+				Type:  "dashed",
+			}),
+		).
+			AddSeries(fmt.Sprintf("%s (attacked)", result.AdjusterType), attackedData,
+				charts.WithLineStyleOpts(opts.LineStyle{
+					Width: 2,
+				}),
+			)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := line.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Attack chart saved", "file", filename)
+	return nil
+}
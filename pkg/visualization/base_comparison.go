@@ -61,10 +61,15 @@ func (g *Generator) GenerateBaseComparisonChartWithOptions(cfg config.Config, da
 		charts.WithTitleOpts(opts.Title{
 			Title: fmt.Sprintf("Base vs Simulated Fee Comparison (Blocks %d-%d)", dataset.StartBlock, dataset.EndBlock),
 			Subtitle: func() string {
+				subtitle := "Fee Mechanism Comparison Analysis"
 				if useLogScale {
-					return "Fee Mechanism Comparison Analysis - Logarithmic Scale"
+					subtitle += " - Logarithmic Scale"
 				}
-				return "Fee Mechanism Comparison Analysis"
+				if a := simResult.Accuracy; a != nil {
+					subtitle += fmt.Sprintf("\nRMSE %s, MAPE %.1f%%, correlation %.3f, lag %+d blocks",
+						config.FormatGwei(a.RMSE), a.MAPE, a.Correlation, a.Lag)
+				}
+				return subtitle
 			}(),
 		}),
 		charts.WithXAxisOpts(opts.XAxis{
@@ -177,17 +182,77 @@ func (g *Generator) GenerateBaseComparisonChartWithOptions(cfg config.Config, da
 	if useLogScale {
 		scaleType = "logarithmic"
 	}
-	fmt.Printf("Base comparison chart (%s scale) saved to %s\n", scaleType, filename)
+	g.logger.Info("Base comparison chart saved", "file", filename, "scale", scaleType)
 
 	// Also generate a detailed gas usage comparison
 	gasFilename := strings.Replace(filename, ".html", "_gas.html", 1)
 	if err := g.generateGasUsageComparison(data, gasFilename, dataset); err != nil {
-		fmt.Printf("Warning: failed to generate gas usage chart: %v\n", err)
+		g.logger.Warn("Failed to generate gas usage chart", "error", err)
+	}
+
+	// And the simulation error broken down by block range
+	if simResult.Accuracy != nil && len(simResult.Accuracy.Segments) > 0 {
+		accuracyFilename := strings.Replace(filename, ".html", "_accuracy.html", 1)
+		if err := g.generateAccuracyChart(*simResult.Accuracy, accuracyFilename); err != nil {
+			g.logger.Warn("Failed to generate accuracy chart", "error", err)
+		}
 	}
 
 	return nil
 }
 
+// generateAccuracyChart creates a bar chart of the percentage error and bias per block range
+func (g *Generator) generateAccuracyChart(accuracy blockchain.Accuracy, filename string) error {
+	ranges := make([]string, len(accuracy.Segments))
+	mape := make([]opts.BarData, len(accuracy.Segments))
+	bias := make([]opts.BarData, len(accuracy.Segments))
+	for i, segment := range accuracy.Segments {
+		ranges[i] = fmt.Sprintf("%d-%d", segment.StartBlock, segment.EndBlock)
+		mape[i] = opts.BarData{Value: segment.MAPE}
+		bias[i] = opts.BarData{Value: segment.Bias}
+	}
+
+	bar := charts.NewBar()
+	bar.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "600px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title: "Simulation Error by Block Range",
+			Subtitle: fmt.Sprintf("MAPE %.1f%%, bias %+.1f%%, directional agreement %.1f%% over %d blocks",
+				accuracy.MAPE, accuracy.Bias, accuracy.DirectionalAgreement, accuracy.Blocks),
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: opts.Bool(true),
+			Top:  "10%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "axis",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name:      "Blocks",
+			AxisLabel: &opts.AxisLabel{Rotate: 30},
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Error (%)",
+			Type: "value",
+		}),
+	)
+	bar.SetXAxis(ranges).
+		AddSeries("MAPE", mape).
+		AddSeries("Bias", bias)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	return bar.Render(file)
+}
+
 // generateGasUsageComparison creates a separate chart for gas usage analysis
 func (g *Generator) generateGasUsageComparison(data *blockchain.ComparisonData, filename string, dataset *blockchain.DataSet) error {
 	// Create line chart for gas usage
@@ -0,0 +1,80 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/dynamics"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateBodeChart creates Bode-style magnitude and phase charts of every adjuster's
+// frequency response, with demand period on a logarithmic axis
+func (g *Generator) GenerateBodeChart(responses []dynamics.FrequencyResponse, filename string) error {
+	if len(responses) == 0 {
+		return fmt.Errorf("no frequency responses to chart")
+	}
+
+	newChart := func(title, subtitle, yName string) *charts.Line {
+		line := charts.NewLine()
+		line.SetGlobalOptions(
+			charts.WithInitializationOpts(opts.Initialization{
+				Width:  "1200px",
+				Height: "500px",
+			}),
+			charts.WithTitleOpts(opts.Title{
+				Title:    title,
+				Subtitle: subtitle,
+			}),
+			charts.WithXAxisOpts(opts.XAxis{
+				Name: "Demand Period (blocks)",
+				Type: "log",
+			}),
+			charts.WithYAxisOpts(opts.YAxis{
+				Name: yName,
+				Type: "value",
+			}),
+			charts.WithLegendOpts(opts.Legend{
+				Show: opts.Bool(true),
+				Top:  "10%",
+			}),
+			charts.WithTooltipOpts(opts.Tooltip{
+				Show:    opts.Bool(true),
+				Trigger: "axis",
+			}),
+		)
+		return line
+	}
+
+	magnitude := newChart("Bode Magnitude", "Fee amplitude relative to equilibrium amplitude - above 0 dB amplifies demand cycles", "Gain (dB)")
+	phase := newChart("Bode Phase", "Fee phase relative to equilibrium - negative values lag demand", "Phase (degrees)")
+
+	for _, response := range responses {
+		gains := make([]opts.LineData, len(response.Points))
+		phases := make([]opts.LineData, len(response.Points))
+		for i, point := range response.Points {
+			gains[i] = opts.LineData{Value: []interface{}{point.Period, point.GainDB}}
+			phases[i] = opts.LineData{Value: []interface{}{point.Period, point.Phase}}
+		}
+		magnitude.AddSeries(response.AdjusterType, gains)
+		phase.AddSeries(response.AdjusterType, phases)
+	}
+
+	page := components.NewPage()
+	page.AddCharts(magnitude, phase)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Bode chart saved", "file", filename)
+	return nil
+}
@@ -26,10 +26,11 @@ func (g *Generator) GenerateChartWithLogScale(cfg config.Config, scenario scenar
 func (g *Generator) GenerateChartWithOptions(cfg config.Config, scenario scenarios.Scenario, filename string, useLogScale bool) error {
 	adjusterType, err := simulator.ParseAdjusterType(cfg.Simulation.AdjusterType)
 	if err != nil {
-		fmt.Printf("Error: Invalid adjuster type: %v\n", err)
 		return err
 	}
-	adjuster, err := simulator.NewAdjusterFactory().CreateAdjusterWithConfigs(adjusterType, &cfg)
+	factory := simulator.NewAdjusterFactory()
+	factory.SetLogger(g.logger)
+	adjuster, err := factory.CreateAdjusterWithConfigs(adjusterType, &cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create adjuster: %w", err)
 	}
@@ -176,7 +177,7 @@ func (g *Generator) GenerateChartWithOptions(cfg config.Config, scenario scenari
 	if useLogScale {
 		scaleType = "logarithmic"
 	}
-	fmt.Printf("Interactive chart (%s scale) saved to %s\n", scaleType, filename)
+	g.logger.Info("Interactive chart saved", "file", filename, "scale", scaleType)
 	return nil
 }
 
@@ -186,7 +187,7 @@ func (g *Generator) GenerateChartForScenario(cfg config.Config, scenario scenari
 	filename := fmt.Sprintf("chart_%s.html", strings.ToLower(strings.ReplaceAll(scenario.Name, " ", "_")))
 
 	if err := g.GenerateChart(cfg, scenario, filename); err != nil {
-		fmt.Printf("Warning: failed to generate chart for %s: %v\n", scenario.Name, err)
+		g.logger.Warn("Failed to generate chart", "scenario", scenario.Name, "error", err)
 	}
 }
 
@@ -196,6 +197,6 @@ func (g *Generator) GenerateChartForScenarioWithLogScale(cfg config.Config, scen
 	filename := fmt.Sprintf("chart_%s_log.html", strings.ToLower(strings.ReplaceAll(scenario.Name, " ", "_")))
 
 	if err := g.GenerateChartWithLogScale(cfg, scenario, filename); err != nil {
-		fmt.Printf("Warning: failed to generate log scale chart for %s: %v\n", scenario.Name, err)
+		g.logger.Warn("Failed to generate log scale chart", "scenario", scenario.Name, "error", err)
 	}
 }
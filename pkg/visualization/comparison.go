@@ -0,0 +1,164 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/compare"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateComparisonChart overlays the base fee of every run on one shared scenario
+func (g *Generator) GenerateComparisonChart(comparison compare.ScenarioComparison, useLogScale bool, filename string) error {
+	line, err := g.ComparisonChart(comparison, useLogScale)
+	if err != nil {
+		return err
+	}
+	if err := renderOverlay(line, filename); err != nil {
+		return err
+	}
+	g.logger.Info("Comparison chart saved", "file", filename)
+	return nil
+}
+
+// ComparisonChart returns the chart GenerateComparisonChart renders, e.g. to add it to a page
+func (g *Generator) ComparisonChart(comparison compare.ScenarioComparison, useLogScale bool) (*charts.Line, error) {
+	if len(comparison.BaseFees) == 0 {
+		return nil, fmt.Errorf("no runs to chart")
+	}
+
+	line := newOverlayChart(
+		fmt.Sprintf("Algorithm Comparison: %s", comparison.Scenario.Name),
+		"Every algorithm replays the identical gas usage sequence",
+		useLogScale,
+	)
+	for i, baseFees := range comparison.BaseFees {
+		data := make([]opts.LineData, len(baseFees))
+		for b, fee := range baseFees {
+			data[b] = opts.LineData{Value: []interface{}{b + 1, displayFee(float64(fee)/1e9, useLogScale)}}
+		}
+		line.AddSeries(comparison.Labels[i], data)
+	}
+	return line, nil
+}
+
+// GenerateDataSetComparisonChart overlays the simulated base fee of every run on the actual Base fee
+func (g *Generator) GenerateDataSetComparisonChart(comparison compare.DataSetComparison, useLogScale bool, filename string) error {
+	line, err := g.DataSetComparisonChart(comparison, useLogScale)
+	if err != nil {
+		return err
+	}
+	if err := renderOverlay(line, filename); err != nil {
+		return err
+	}
+	g.logger.Info("Comparison chart saved", "file", filename)
+	return nil
+}
+
+// DataSetComparisonChart returns the chart GenerateDataSetComparisonChart renders
+func (g *Generator) DataSetComparisonChart(comparison compare.DataSetComparison, useLogScale bool) (*charts.Line, error) {
+	if len(comparison.SimResults) == 0 || comparison.SimResults[0].ComparisonData == nil {
+		return nil, fmt.Errorf("simulation did not collect visualization data")
+	}
+
+	dataset := comparison.DataSet
+	line := newOverlayChart(
+		fmt.Sprintf("Algorithm Comparison: Base Blocks %d-%d", dataset.StartBlock, dataset.EndBlock),
+		"Every algorithm replays the identical blocks; dashed line is the actual base fee",
+		useLogScale,
+	)
+
+	actual := comparison.SimResults[0].ComparisonData
+	actualData := make([]opts.LineData, len(actual.ActualBaseFees))
+	for b, fee := range actual.ActualBaseFees {
+		actualData[b] = opts.LineData{Value: []interface{}{actual.BlockNumbers[b], displayFee(fee, useLogScale)}}
+	}
+	line.AddSeries("Actual Base", actualData,
+		charts.WithLineStyleOpts(opts.LineStyle{Width: 2, Type: "dashed"}),
+	)
+
+	for i, simResult := range comparison.SimResults {
+		data := simResult.ComparisonData
+		series := make([]opts.LineData, len(data.SimulatedBaseFees))
+		for b, fee := range data.SimulatedBaseFees {
+			series[b] = opts.LineData{Value: []interface{}{data.BlockNumbers[b], displayFee(fee, useLogScale)}}
+		}
+		line.AddSeries(comparison.Labels[i], series)
+	}
+	return line, nil
+}
+
+// newOverlayChart creates a base fee line chart with one series per run
+func newOverlayChart(title, subtitle string, useLogScale bool) *charts.Line {
+	yAxis := opts.YAxis{Name: "Base Fee (Gwei)", Type: "value"}
+	if useLogScale {
+		subtitle += " - Logarithmic Scale"
+		yAxis = opts.YAxis{Name: "Base Fee (Gwei) - Log Scale", Type: "log", Min: 1e-6}
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "700px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    title,
+			Subtitle: subtitle,
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Block Number",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(yAxis),
+		charts.WithLegendOpts(opts.Legend{
+			Show: opts.Bool(true),
+			Top:  "10%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "axis",
+		}),
+		charts.WithToolboxOpts(opts.Toolbox{
+			Show: opts.Bool(true),
+			Feature: &opts.ToolBoxFeature{
+				SaveAsImage: &opts.ToolBoxFeatureSaveAsImage{
+					Show:  opts.Bool(true),
+					Type:  "png",
+					Title: "Save as Image",
+				},
+				DataZoom: &opts.ToolBoxFeatureDataZoom{
+					Show:  opts.Bool(true),
+					Title: map[string]string{"zoom": "Zoom", "back": "Back"},
+				},
+			},
+		}),
+	)
+	line.SetSeriesOptions(
+		charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}),
+	)
+	return line
+}
+
+// displayFee replaces non-positive fees with a tiny value on a log scale, which cannot show zero
+func displayFee(fee float64, useLogScale bool) float64 {
+	if useLogScale && fee <= 0 {
+		return 1e-9
+	}
+	return fee
+}
+
+// renderOverlay writes the chart to filename
+func renderOverlay(line *charts.Line, filename string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := line.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package visualization
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/dynamics"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateEquilibriumChart creates the convergence path of every adjuster under a constant demand
+// curve: the base fee over time, and the path through fee-utilization space against the curve
+func (g *Generator) GenerateEquilibriumChart(curve dynamics.Curve, targetBlockSize uint64, results []dynamics.Equilibrium, filename string) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no equilibrium results to chart")
+	}
+
+	initOpts := charts.WithInitializationOpts(opts.Initialization{
+		Width:  "1200px",
+		Height: "600px",
+	})
+	legendOpts := charts.WithLegendOpts(opts.Legend{
+		Show: opts.Bool(true),
+		Top:  "10%",
+	})
+
+	// Base fee path over time
+	path := charts.NewLine()
+	path.SetGlobalOptions(
+		initOpts,
+		legendOpts,
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Convergence Path",
+			Subtitle: "Base fee under constant demand - dashed line is the fee at which demand equals target",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Block",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name:  "Base Fee (Gwei)",
+			Type:  "value",
+			Scale: opts.Bool(true),
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "axis",
+		}),
+		charts.WithDataZoomOpts(opts.DataZoom{
+			Type:  "slider",
+			Start: 0,
+			End:   100,
+		}),
+	)
+
+	blockNumbers := make([]int, len(results[0].BaseFees))
+	for i := range blockNumbers {
+		blockNumbers[i] = i
+	}
+	path.SetXAxis(blockNumbers)
+
+	if theoretical := results[0].TheoreticalFee; theoretical > 0 {
+		equilibrium := make([]opts.LineData, len(blockNumbers))
+		for i := range equilibrium {
+			equilibrium[i] = opts.LineData{Value: theoretical / 1e9}
+		}
+		path.AddSeries("Equilibrium", equilibrium,
+			charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Color: "#999999"}),
+			charts.WithItemStyleOpts(opts.ItemStyle{Color: "#999999"}),
+		)
+	}
+
+	minFee, maxFee := math.Inf(1), math.Inf(-1)
+	for _, result := range results {
+		data := make([]opts.LineData, len(result.BaseFees))
+		for i, fee := range result.BaseFees {
+			data[i] = opts.LineData{Value: fee / 1e9}
+			minFee = math.Min(minFee, fee)
+			maxFee = math.Max(maxFee, fee)
+		}
+		path.AddSeries(fmt.Sprintf("%s (%s)", result.AdjusterType, result.Status), data)
+	}
+	path.SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}))
+
+	// Phase portrait: each block's fee and the utilization it produced, over the demand curve
+	portrait := charts.NewLine()
+	portrait.SetGlobalOptions(
+		initOpts,
+		legendOpts,
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Fee-Utilization Path",
+			Subtitle: "Each point is a block; the demand curve is where every path would sit if the fee cleared demand",
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name:  "Base Fee (Gwei)",
+			Type:  "value",
+			Scale: opts.Bool(true),
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Utilization (x target)",
+			Type: "value",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "item",
+		}),
+	)
+
+	const curvePoints = 100
+	demand := make([]opts.LineData, 0, curvePoints)
+	low, high := math.Max(minFee, 1), math.Max(maxFee, minFee*1.01)
+	for i := 0; i < curvePoints; i++ {
+		fee := low * math.Pow(high/low, float64(i)/(curvePoints-1))
+		demand = append(demand, opts.LineData{Value: []interface{}{fee / 1e9, curve.GasAt(fee) / float64(targetBlockSize)}})
+	}
+	portrait.AddSeries("Demand curve", demand,
+		charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Color: "#999999"}),
+		charts.WithItemStyleOpts(opts.ItemStyle{Color: "#999999"}),
+	)
+
+	for _, result := range results {
+		data := make([]opts.LineData, len(result.BaseFees))
+		for i, fee := range result.BaseFees {
+			data[i] = opts.LineData{Value: []interface{}{fee / 1e9, result.Utilizations[i]}}
+		}
+		portrait.AddSeries(result.AdjusterType, data)
+	}
+	portrait.SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}))
+
+	page := components.NewPage()
+	page.AddCharts(path, portrait)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Equilibrium chart saved", "file", filename)
+	return nil
+}
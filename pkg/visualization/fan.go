@@ -0,0 +1,94 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/montecarlo"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateFanChart creates a fan chart of base fee percentile bands across Monte Carlo runs
+func (g *Generator) GenerateFanChart(summary montecarlo.ScenarioSummary, adjusterType string, filename string) error {
+	bands := summary.FeeBands
+	if len(bands.P50) == 0 {
+		return fmt.Errorf("no fee bands to chart for %s", summary.ScenarioName)
+	}
+
+	line := charts.NewLine()
+	line.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "800px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("Base Fee Fan Chart: %s", summary.ScenarioName),
+			Subtitle: fmt.Sprintf("%s across %d runs - median with 25-75 and 5-95 percentile bands", adjusterType, summary.Runs),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Block Number",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Base Fee (Gwei)",
+			Type: "value",
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: opts.Bool(true),
+			Top:  "10%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:    opts.Bool(true),
+			Trigger: "axis",
+		}),
+	)
+
+	blockNumbers := make([]int, len(bands.P50))
+	for i := range blockNumbers {
+		blockNumbers[i] = i + 1
+	}
+	line.SetXAxis(blockNumbers)
+
+	// Bands are drawn as an invisible lower bound with the band width stacked on top
+	addBand := func(name, stack string, lower, upper []float64, opacity float32) {
+		base := make([]opts.LineData, len(lower))
+		width := make([]opts.LineData, len(lower))
+		for i := range lower {
+			base[i] = opts.LineData{Value: lower[i]}
+			width[i] = opts.LineData{Value: upper[i] - lower[i]}
+		}
+		line.AddSeries(name+" lower", base,
+			charts.WithLineChartOpts(opts.LineChart{Stack: stack, ShowSymbol: opts.Bool(false)}),
+			charts.WithLineStyleOpts(opts.LineStyle{Opacity: 0}),
+		)
+		line.AddSeries(name, width,
+			charts.WithLineChartOpts(opts.LineChart{Stack: stack, ShowSymbol: opts.Bool(false)}),
+			charts.WithLineStyleOpts(opts.LineStyle{Opacity: 0}),
+			charts.WithAreaStyleOpts(opts.AreaStyle{Opacity: opacity}),
+		)
+	}
+	addBand("P5-P95", "outer", bands.P5, bands.P95, 0.2)
+	addBand("P25-P75", "inner", bands.P25, bands.P75, 0.4)
+
+	median := make([]opts.LineData, len(bands.P50))
+	for i, fee := range bands.P50 {
+		median[i] = opts.LineData{Value: fee}
+	}
+	line.AddSeries("Median", median,
+		charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}),
+		charts.WithLineStyleOpts(opts.LineStyle{Width: 2}),
+	)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := line.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Fan chart saved", "file", filename)
+	return nil
+}
@@ -0,0 +1,113 @@
+package visualization
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/brianbland/feemarketsim/pkg/pareto"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/event"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateParetoChart creates a scatter of every evaluated configuration with the non-dominated
+// set highlighted. Hovering a point shows its flags; clicking it opens them for copying.
+// A third objective, if any, is shown as color.
+func (g *Generator) GenerateParetoChart(result pareto.Result, filename string) error {
+	if len(result.Points) == 0 {
+		return fmt.Errorf("no points to chart")
+	}
+
+	names := make([]string, len(result.Objectives))
+	for i, objective := range result.Objectives {
+		names[i] = objective.String()
+	}
+
+	scatter := charts.NewScatter()
+	scatter.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "800px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    fmt.Sprintf("Pareto Frontier: %s", result.AdjusterType),
+			Subtitle: fmt.Sprintf("%d of %d configurations non-dominated over %s - click a point to copy its flags", len(result.Frontier), len(result.Points), strings.Join(names, ", ")),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name:  names[0],
+			Type:  "value",
+			Scale: opts.Bool(true),
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name:  names[1],
+			Type:  "value",
+			Scale: opts.Bool(true),
+		}),
+		charts.WithLegendOpts(opts.Legend{
+			Show: opts.Bool(true),
+			Top:  "10%",
+		}),
+		charts.WithTooltipOpts(opts.Tooltip{
+			Show:      opts.Bool(true),
+			Trigger:   "item",
+			Formatter: opts.FuncOpts(`function (params) { return params.name + '<br/>' + params.value.map(function (v) { return Number(v).toPrecision(6); }).join(', '); }`),
+		}),
+		charts.WithEventListeners(event.Listener{
+			EventName: "click",
+			Handler:   opts.FuncOpts(`(params) => window.prompt('Configuration flags:', params.name)`),
+		}),
+	)
+
+	if len(result.Objectives) == 3 {
+		minZ, maxZ := math.Inf(1), math.Inf(-1)
+		for _, point := range result.Points {
+			minZ = math.Min(minZ, point.Values[2])
+			maxZ = math.Max(maxZ, point.Values[2])
+		}
+		scatter.SetGlobalOptions(charts.WithVisualMapOpts(opts.VisualMap{
+			Calculable: opts.Bool(true),
+			Dimension:  "2",
+			Min:        float32(minZ),
+			Max:        float32(maxZ),
+			Text:       []string{names[2]},
+			InRange:    &opts.VisualMapInRange{Color: []string{"#313695", "#74add1", "#fee090", "#f46d43", "#a50026"}},
+		}))
+	}
+
+	var dominated, frontier []opts.ScatterData
+	for _, point := range result.Points {
+		data := opts.ScatterData{
+			Name:  point.Flags,
+			Value: point.Values,
+		}
+		if point.OnFrontier {
+			data.SymbolSize = 14
+			frontier = append(frontier, data)
+		} else {
+			data.SymbolSize = 6
+			dominated = append(dominated, data)
+		}
+	}
+
+	scatter.AddSeries("Dominated", dominated,
+		charts.WithItemStyleOpts(opts.ItemStyle{Opacity: 0.4}),
+	).
+		AddSeries("Pareto frontier", frontier,
+			charts.WithItemStyleOpts(opts.ItemStyle{Opacity: 1}),
+		)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := scatter.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Pareto chart saved", "file", filename)
+	return nil
+}
@@ -0,0 +1,99 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/dynamics"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateResponseChart creates one chart per test input comparing every algorithm's base fee
+// with the equilibrium fee. Fees are shown relative to the initial equilibrium.
+func (g *Generator) GenerateResponseChart(responses []dynamics.Response, filename string) error {
+	if len(responses) == 0 {
+		return fmt.Errorf("no responses to chart")
+	}
+
+	page := components.NewPage()
+	for _, input := range dynamics.Inputs {
+		var matching []dynamics.Response
+		for _, response := range responses {
+			if response.Input == input {
+				matching = append(matching, response)
+			}
+		}
+		if len(matching) == 0 {
+			continue
+		}
+
+		line := charts.NewLine()
+		line.SetGlobalOptions(
+			charts.WithInitializationOpts(opts.Initialization{
+				Width:  "1200px",
+				Height: "500px",
+			}),
+			charts.WithTitleOpts(opts.Title{
+				Title:    fmt.Sprintf("Response to %s Input", input),
+				Subtitle: fmt.Sprintf("Base fee relative to the initial equilibrium - input at block %d", dynamics.Onset),
+			}),
+			charts.WithXAxisOpts(opts.XAxis{
+				Name: "Block",
+			}),
+			charts.WithYAxisOpts(opts.YAxis{
+				Name:  "Fee / Initial Fee",
+				Type:  "value",
+				Scale: opts.Bool(true),
+			}),
+			charts.WithLegendOpts(opts.Legend{
+				Show: opts.Bool(true),
+				Top:  "10%",
+			}),
+			charts.WithTooltipOpts(opts.Tooltip{
+				Show:    opts.Bool(true),
+				Trigger: "axis",
+			}),
+		)
+
+		reference := matching[0].Equilibrium[0]
+		blockNumbers := make([]int, len(matching[0].BaseFees))
+		for i := range blockNumbers {
+			blockNumbers[i] = i
+		}
+		line.SetXAxis(blockNumbers)
+
+		equilibrium := make([]opts.LineData, len(matching[0].Equilibrium))
+		for i, fee := range matching[0].Equilibrium {
+			equilibrium[i] = opts.LineData{Value: fee / reference}
+		}
+		line.AddSeries("Equilibrium", equilibrium,
+			charts.WithLineStyleOpts(opts.LineStyle{Type: "dashed", Color: "#999999"}),
+			charts.WithItemStyleOpts(opts.ItemStyle{Color: "#999999"}),
+		)
+
+		for _, response := range matching {
+			data := make([]opts.LineData, len(response.BaseFees))
+			for i, fee := range response.BaseFees {
+				data[i] = opts.LineData{Value: fee / reference}
+			}
+			line.AddSeries(response.AdjusterType, data)
+		}
+		line.SetSeriesOptions(charts.WithLineChartOpts(opts.LineChart{ShowSymbol: opts.Bool(false)}))
+		page.AddCharts(line)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Response chart saved", "file", filename)
+	return nil
+}
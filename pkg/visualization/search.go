@@ -0,0 +1,86 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/adversarial"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateSearchChart creates a chart of the worst-case gas sequence and the search convergence
+func (g *Generator) GenerateSearchChart(result adversarial.SearchResult, filename string) error {
+	gasLine := charts.NewLine()
+	gasLine.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "500px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    result.Scenario.Name,
+			Subtitle: fmt.Sprintf("Score %.6f after %d simulations", result.Score, result.Evaluations),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Block Number",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: "Gas Usage (Millions)",
+			Type: "value",
+		}),
+	)
+
+	gasData := make([]opts.LineData, len(result.Scenario.Blocks))
+	for i, gas := range result.Scenario.Blocks {
+		gasData[i] = opts.LineData{Value: []interface{}{i + 1, float64(gas) / 1e6}}
+	}
+	gasLine.AddSeries("Gas Usage", gasData,
+		charts.WithLineChartOpts(opts.LineChart{
+			Step: "middle",
+		}),
+	)
+
+	progressLine := charts.NewLine()
+	progressLine.SetGlobalOptions(
+		charts.WithInitializationOpts(opts.Initialization{
+			Width:  "1200px",
+			Height: "400px",
+		}),
+		charts.WithTitleOpts(opts.Title{
+			Title:    "Search Convergence",
+			Subtitle: fmt.Sprintf("Best %s per iteration (%s)", result.Metric, result.Method),
+		}),
+		charts.WithXAxisOpts(opts.XAxis{
+			Name: "Iteration",
+			Type: "value",
+		}),
+		charts.WithYAxisOpts(opts.YAxis{
+			Name: string(result.Metric),
+			Type: "value",
+		}),
+	)
+
+	progressData := make([]opts.LineData, len(result.Progress))
+	for i, score := range result.Progress {
+		progressData[i] = opts.LineData{Value: []interface{}{i + 1, score}}
+	}
+	progressLine.AddSeries("Best Score", progressData)
+
+	page := components.NewPage()
+	page.AddCharts(gasLine, progressLine)
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Search chart saved", "file", filename)
+	return nil
+}
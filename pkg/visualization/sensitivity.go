@@ -0,0 +1,84 @@
+package visualization
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/brianbland/feemarketsim/pkg/sensitivity"
+	"github.com/go-echarts/go-echarts/v2/charts"
+	"github.com/go-echarts/go-echarts/v2/components"
+	"github.com/go-echarts/go-echarts/v2/opts"
+)
+
+// GenerateSensitivityChart creates one bar chart per metric with the parameters in rank order.
+// Morris charts show mu* and sigma; Sobol charts show first-order and total-effect indices.
+func (g *Generator) GenerateSensitivityChart(result sensitivity.Result, filename string) error {
+	if len(result.Metrics) == 0 {
+		return fmt.Errorf("no metrics to chart")
+	}
+
+	page := components.NewPage()
+	for _, metric := range result.Metrics {
+		names := make([]string, len(metric.Indices))
+		primary := make([]opts.BarData, len(metric.Indices))
+		secondary := make([]opts.BarData, len(metric.Indices))
+		for i, index := range metric.Indices {
+			names[i] = index.Param
+			if result.Method == "sobol" {
+				primary[i] = opts.BarData{Value: index.First}
+				secondary[i] = opts.BarData{Value: index.Total}
+			} else {
+				primary[i] = opts.BarData{Value: index.MuStar}
+				secondary[i] = opts.BarData{Value: index.Sigma}
+			}
+		}
+
+		primaryName, secondaryName := "mu*", "sigma"
+		if result.Method == "sobol" {
+			primaryName, secondaryName = "First-order", "Total-effect"
+		}
+
+		bar := charts.NewBar()
+		bar.SetGlobalOptions(
+			charts.WithInitializationOpts(opts.Initialization{
+				Width:  "1200px",
+				Height: "500px",
+			}),
+			charts.WithTitleOpts(opts.Title{
+				Title:    fmt.Sprintf("Sensitivity of %s: %s", metric.Metric, result.AdjusterType),
+				Subtitle: fmt.Sprintf("%s method, %d evaluations", result.Method, result.Evaluations),
+			}),
+			charts.WithLegendOpts(opts.Legend{
+				Show: opts.Bool(true),
+				Top:  "10%",
+			}),
+			charts.WithTooltipOpts(opts.Tooltip{
+				Show:    opts.Bool(true),
+				Trigger: "axis",
+			}),
+			charts.WithXAxisOpts(opts.XAxis{
+				AxisLabel: &opts.AxisLabel{Rotate: 30},
+			}),
+			charts.WithYAxisOpts(opts.YAxis{
+				Type: "value",
+			}),
+		)
+		bar.SetXAxis(names).
+			AddSeries(primaryName, primary).
+			AddSeries(secondaryName, secondary)
+		page.AddCharts(bar)
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer file.Close()
+
+	if err := page.Render(file); err != nil {
+		return fmt.Errorf("failed to render chart: %w", err)
+	}
+
+	g.logger.Info("Sensitivity chart saved", "file", filename)
+	return nil
+}
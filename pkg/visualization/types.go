@@ -1,9 +1,19 @@
 package visualization
 
 import (
+	"log/slog"
+
+	"github.com/brianbland/feemarketsim/pkg/adversarial"
 	"github.com/brianbland/feemarketsim/pkg/blockchain"
+	"github.com/brianbland/feemarketsim/pkg/compare"
 	"github.com/brianbland/feemarketsim/pkg/config"
+	"github.com/brianbland/feemarketsim/pkg/dynamics"
+	"github.com/brianbland/feemarketsim/pkg/logging"
+	"github.com/brianbland/feemarketsim/pkg/montecarlo"
+	"github.com/brianbland/feemarketsim/pkg/pareto"
 	"github.com/brianbland/feemarketsim/pkg/scenarios"
+	"github.com/brianbland/feemarketsim/pkg/sensitivity"
+	"github.com/go-echarts/go-echarts/v2/charts"
 )
 
 // ChartData holds data for creating AIMD charts
@@ -25,14 +35,28 @@ type ChartGenerator interface {
 	GenerateChartForScenarioWithLogScale(config config.Config, scenario scenarios.Scenario)
 	GenerateBaseComparisonChart(config config.Config, dataset *blockchain.DataSet, simResult *blockchain.SimulationResult, filename string) error
 	GenerateBaseComparisonChartWithLogScale(config config.Config, dataset *blockchain.DataSet, simResult *blockchain.SimulationResult, filename string) error
+	GenerateAttackChart(results []adversarial.Result, filename string) error
+	GenerateSearchChart(result adversarial.SearchResult, filename string) error
+	GenerateFanChart(summary montecarlo.ScenarioSummary, adjusterType string, filename string) error
+	GenerateParetoChart(result pareto.Result, filename string) error
+	GenerateSensitivityChart(result sensitivity.Result, filename string) error
+	GenerateResponseChart(responses []dynamics.Response, filename string) error
+	GenerateBodeChart(responses []dynamics.FrequencyResponse, filename string) error
+	GenerateEquilibriumChart(curve dynamics.Curve, targetBlockSize uint64, results []dynamics.Equilibrium, filename string) error
+	GenerateComparisonChart(comparison compare.ScenarioComparison, useLogScale bool, filename string) error
+	GenerateDataSetComparisonChart(comparison compare.DataSetComparison, useLogScale bool, filename string) error
+	ComparisonChart(comparison compare.ScenarioComparison, useLogScale bool) (*charts.Line, error)
+	DataSetComparisonChart(comparison compare.DataSetComparison, useLogScale bool) (*charts.Line, error)
 }
 
 // Generator implements ChartGenerator interface
-type Generator struct{}
+type Generator struct {
+	logger *slog.Logger
+}
 
-// NewGenerator creates a new chart generator
-func NewGenerator() ChartGenerator {
-	return &Generator{}
+// NewGenerator creates a new chart generator reporting saved charts to logger; nil discards
+func NewGenerator(logger *slog.Logger) ChartGenerator {
+	return &Generator{logger: logging.OrDiscard(logger)}
 }
 
 // ChartOptions contains styling and size options for charts
@@ -12,9 +12,9 @@ import (
 )
 
 func TestNewGenerator(t *testing.T) {
-	generator := NewGenerator()
+	generator := NewGenerator(nil)
 	if generator == nil {
-		t.Fatal("NewGenerator() returned nil")
+		t.Fatal("NewGenerator(nil) returned nil")
 	}
 
 	// Verify it implements the interface
@@ -41,7 +41,7 @@ func TestGenerateChart(t *testing.T) {
 		Blocks:      []uint64{15000000, 16000000, 14000000, 15500000, 14500000},
 	}
 
-	generator := NewGenerator()
+	generator := NewGenerator(nil)
 	testFile := "test_chart.html"
 
 	// Clean up any existing test file
@@ -126,7 +126,7 @@ func TestGenerateBaseComparisonChart(t *testing.T) {
 		},
 	}
 
-	generator := NewGenerator()
+	generator := NewGenerator(nil)
 	testFile := "test_base_comparison.html"
 
 	// Clean up any existing test files
@@ -134,6 +134,7 @@ func TestGenerateBaseComparisonChart(t *testing.T) {
 		os.Remove(testFile)
 		gasFile := strings.Replace(testFile, ".html", "_gas.html", 1)
 		os.Remove(gasFile)
+		os.Remove(strings.Replace(testFile, ".html", "_accuracy.html", 1))
 	}()
 
 	// Create blockchain simulator with the correct signature
@@ -178,7 +179,7 @@ func TestGenerateChartForScenario(t *testing.T) {
 		Blocks:      []uint64{15000000, 16000000},
 	}
 
-	generator := NewGenerator()
+	generator := NewGenerator(nil)
 
 	expectedFile := "chart_test_scenario.html"
 	defer os.Remove(expectedFile)
@@ -261,7 +262,7 @@ func TestGenerateChartWithLogScale(t *testing.T) {
 		Blocks: []uint64{15000000, 20000000, 25000000, 10000000, 5000000},
 	}
 
-	gen := NewGenerator()
+	gen := NewGenerator(nil)
 	err := gen.GenerateChartWithLogScale(cfg, scenario, "test_chart_log.html")
 	if err != nil {
 		t.Fatalf("Failed to generate chart with log scale: %v", err)
@@ -350,7 +351,7 @@ func TestGenerateBaseComparisonChartWithLogScale(t *testing.T) {
 		t.Fatalf("Failed to run simulation: %v", err)
 	}
 
-	gen := NewGenerator()
+	gen := NewGenerator(nil)
 	err = gen.GenerateBaseComparisonChartWithLogScale(cfg, dataset, simResult, "test_base_comparison_log.html")
 	if err != nil {
 		t.Fatalf("Failed to generate base comparison chart with log scale: %v", err)
@@ -365,4 +366,5 @@ func TestGenerateBaseComparisonChartWithLogScale(t *testing.T) {
 	os.Remove("test_base_comparison_log.html")
 	gasFile := strings.Replace("test_base_comparison_log.html", ".html", "_gas.html", 1)
 	os.Remove(gasFile)
+	os.Remove(strings.Replace("test_base_comparison_log.html", ".html", "_accuracy.html", 1))
 }